@@ -13,13 +13,22 @@ import (
 	"github.com/lmacrc/weather/pkg/cronzap"
 	"github.com/lmacrc/weather/pkg/event"
 	"github.com/lmacrc/weather/pkg/weather"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
 	whttp "github.com/lmacrc/weather/pkg/weather/http"
 	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service/aprs"
 	"github.com/lmacrc/weather/pkg/weather/service/archive"
 	"github.com/lmacrc/weather/pkg/weather/service/camera"
+	"github.com/lmacrc/weather/pkg/weather/service/clientraw"
 	"github.com/lmacrc/weather/pkg/weather/service/ftp"
 	"github.com/lmacrc/weather/pkg/weather/service/influxdb"
+	"github.com/lmacrc/weather/pkg/weather/service/noaa"
+	"github.com/lmacrc/weather/pkg/weather/service/pws"
+	"github.com/lmacrc/weather/pkg/weather/service/rainevent"
 	"github.com/lmacrc/weather/pkg/weather/service/realtime"
+	"github.com/lmacrc/weather/pkg/weather/service/watchdog"
+	"github.com/lmacrc/weather/pkg/weather/service/webtags"
+	"github.com/lmacrc/weather/pkg/weather/service/windy"
 	"github.com/lmacrc/weather/pkg/weather/store"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/robfig/cron/v3"
@@ -70,7 +79,13 @@ func newServer() *cobra.Command {
 				return err
 			}
 
-			s, err := store.New(db, bus)
+			loc, err := calendar.LoadLocation(viper.GetViper())
+			if err != nil {
+				log.Error("Failed to load station time zone.", zap.Error(err))
+				return err
+			}
+
+			s, err := store.New(db, bus, store.WithLocation(loc))
 			if err != nil {
 				return err
 			}
@@ -110,15 +125,67 @@ func newServer() *cobra.Command {
 				log.Info("InfluxDB service disabled.")
 			}
 
-			cs := cron.New(cron.WithLogger(&cronzap.Adapter{Log: log.With(zap.String("service", "cron"))}))
+			watchdogSvc, err := watchdog.New(log, vp, s, bus)
+			if err != nil {
+				log.Error("Failed to initialise watchdog service.", zap.Error(err))
+				return err
+			}
 
-			if viper.GetBool("realtime.enabled") {
-				reportSvc, err := reporting.New(log, vp, s)
+			go func() {
+				watchdogSvc.Run(ctx)
+			}()
+
+			rainEventSvc, err := rainevent.New(log, vp, s, bus)
+			if err != nil {
+				log.Error("Failed to initialise rain event service.", zap.Error(err))
+				return err
+			}
+
+			go func() {
+				rainEventSvc.Run(ctx)
+			}()
+
+			// the reporter subscribes to the observations before the upload services, which read its rain statistics
+			reportSvc, err := reporting.New(log, vp, s, reporting.WithRollingStatistics(bus))
+			if err != nil {
+				log.Error("Failed to initialise reporting service.", zap.Error(err))
+				return err
+			}
+
+			for _, site := range []pws.Site{pws.Wunderground, pws.PWSweather, pws.MetOfficeWOW} {
+				if !viper.GetBool(site.Name + ".enabled") {
+					log.Info("PWS upload service disabled.", zap.String("site", site.Name))
+					continue
+				}
+
+				pwsSvc, err := pws.New(log, vp, site, reportSvc, bus)
+				if err != nil {
+					log.Error("Failed to initialise PWS upload service.", zap.String("site", site.Name), zap.Error(err))
+					return err
+				}
+
+				go func() {
+					pwsSvc.Run(ctx)
+				}()
+			}
+
+			if viper.GetBool("windy.enabled") {
+				windySvc, err := windy.New(log, vp, reportSvc, bus)
 				if err != nil {
-					log.Error("Failed to initialise reporting service.", zap.Error(err))
+					log.Error("Failed to initialise Windy service.", zap.Error(err))
 					return err
 				}
 
+				go func() {
+					windySvc.Run(ctx)
+				}()
+			} else {
+				log.Info("Windy service disabled.")
+			}
+
+			cs := cron.New(cron.WithLocation(loc), cron.WithLogger(&cronzap.Adapter{Log: log.With(zap.String("service", "cron"))}))
+
+			if viper.GetBool("realtime.enabled") {
 				realtimeSvc, err := realtime.New(log, vp, reportSvc, ftpSvc, bus)
 				if err != nil {
 					log.Error("Failed to initialise realtime service.", zap.Error(err))
@@ -132,6 +199,34 @@ func newServer() *cobra.Command {
 				log.Info("Realtime service disabled.")
 			}
 
+			if viper.GetBool("clientraw.enabled") {
+				clientrawSvc, err := clientraw.New(log, vp, reportSvc, ftpSvc, bus)
+				if err != nil {
+					log.Error("Failed to initialise clientraw service.", zap.Error(err))
+					return err
+				}
+
+				go func() {
+					clientrawSvc.Run(ctx)
+				}()
+			} else {
+				log.Info("Clientraw service disabled.")
+			}
+
+			if viper.GetBool("aprs.enabled") {
+				aprsSvc, err := aprs.New(log, vp, reportSvc, bus)
+				if err != nil {
+					log.Error("Failed to initialise APRS service.", zap.Error(err))
+					return err
+				}
+
+				go func() {
+					aprsSvc.Run(ctx)
+				}()
+			} else {
+				log.Info("APRS service disabled.")
+			}
+
 			if viper.GetBool("camera.enabled") {
 				cameraSvc, err := camera.New(log, vp, ftpSvc)
 				if err != nil {
@@ -149,7 +244,7 @@ func newServer() *cobra.Command {
 			if viper.GetBool("archive.enabled") {
 				log.Info("Archive service enabled.")
 
-				archiveSvc, err := archive.New(log, db, vp, s, ftpSvc)
+				archiveSvc, err := archive.New(log, db, vp, s, ftpSvc, archive.WithSummarizer(reportSvc))
 				if err != nil {
 					log.Error("Failed to initialise archive service.", zap.Error(err))
 					return err
@@ -167,13 +262,44 @@ func newServer() *cobra.Command {
 				log.Info("Archive service disabled.")
 			}
 
+			if viper.GetBool("noaa.enabled") {
+				log.Info("NOAA report service enabled.")
+
+				noaaSvc, err := noaa.New(log, vp, reportSvc, ftpSvc)
+				if err != nil {
+					log.Error("Failed to initialise NOAA report service.", zap.Error(err))
+					return err
+				}
+
+				cs.Schedule(noaaSvc.Schedule(), noaaSvc)
+			} else {
+				log.Info("NOAA report service disabled.")
+			}
+
+			if viper.GetBool("webtags.enabled") {
+				log.Info("Web tags service enabled.")
+
+				webtagsSvc, err := webtags.New(log, vp, reportSvc, ftpSvc)
+				if err != nil {
+					log.Error("Failed to initialise web tags service.", zap.Error(err))
+					return err
+				}
+
+				for _, j := range webtagsSvc.Jobs() {
+					cs.Schedule(j.Schedule(), j)
+				}
+			} else {
+				log.Info("Web tags service disabled.")
+			}
+
 			cs.Start()
 
 			mux := http.NewServeMux()
 
 			mux.Handle("/metrics", promhttp.Handler())
 
-			wh, err := whttp.New(log, vp, s)
+			// observations are written via the rain event service, which computes the event rain
+			wh, err := whttp.New(log, vp, rainEventSvc)
 			if err != nil {
 				return err
 			}
@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/jinzhu/now"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
 	"github.com/lmacrc/weather/pkg/weather/service/archive"
 	"github.com/lmacrc/weather/pkg/weather/service/ftp"
 	"github.com/spf13/cobra"
@@ -20,10 +21,15 @@ func newArchiveCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			log := zap.NewNop()
 
-			last := now.BeginningOfDay()
-
 			vp := viper.GetViper()
 
+			cal, err := calendar.New(vp)
+			if err != nil {
+				return err
+			}
+
+			last := cal.Date(time.Now())
+
 			var ftpSvc *ftp.Service
 			if vp.GetBool("ftp.enabled") {
 				ftpSvc, err = ftp.New(log, db, vp)
@@ -32,12 +38,17 @@ func newArchiveCommand() *cobra.Command {
 				}
 			}
 
-			arSvc, _ := archive.New(zap.NewNop(), db, vp, st, ftpSvc)
+			reportSvc, err := reporting.New(log, vp, st)
+			if err != nil {
+				return err
+			}
+
+			arSvc, _ := archive.New(zap.NewNop(), db, vp, st, ftpSvc, archive.WithSummarizer(reportSvc))
 			arSvc.ArchiveAll()
 
 			var dates []time.Time
 			for _, arg := range args {
-				ts, err := time.Parse("20060102", arg)
+				ts, err := time.ParseInLocation("20060102", arg, loc)
 				if err != nil {
 					return fmt.Errorf("invalid date %q: must be YYYYMMDD", arg)
 				} else if ts.Equal(last) || ts.After(last) {
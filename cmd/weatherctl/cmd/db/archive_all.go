@@ -1,6 +1,7 @@
 package db
 
 import (
+	"github.com/lmacrc/weather/pkg/weather/reporting"
 	"github.com/lmacrc/weather/pkg/weather/service/archive"
 	"github.com/lmacrc/weather/pkg/weather/service/ftp"
 	"github.com/spf13/cobra"
@@ -26,7 +27,12 @@ func newArchiveAllCommand() *cobra.Command {
 				}
 			}
 
-			arSvc, _ := archive.New(zap.NewNop(), db, vp, st, ftpSvc)
+			reportSvc, err := reporting.New(log, vp, st)
+			if err != nil {
+				return err
+			}
+
+			arSvc, _ := archive.New(zap.NewNop(), db, vp, st, ftpSvc, archive.WithSummarizer(reportSvc))
 			return arSvc.ArchiveAll()
 		},
 	}
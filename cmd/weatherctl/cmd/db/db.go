@@ -1,8 +1,11 @@
 package db
 
 import (
+	"time"
+
 	"github.com/lmacrc/weather/pkg/event"
 	"github.com/lmacrc/weather/pkg/weather"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
 	whttp "github.com/lmacrc/weather/pkg/weather/http"
 	"github.com/lmacrc/weather/pkg/weather/service/archive"
 	"github.com/lmacrc/weather/pkg/weather/service/camera"
@@ -27,6 +30,7 @@ var (
 	bus *event.Bus
 	db  *gorm.DB
 	st  *store.Store
+	loc *time.Location // loc is the time zone of the station
 )
 
 func NewDbCommand() *cobra.Command {
@@ -49,9 +53,14 @@ func NewDbCommand() *cobra.Command {
 				return err
 			}
 
+			loc, err = calendar.LoadLocation(vp)
+			if err != nil {
+				return err
+			}
+
 			bus = event.New()
 
-			st, err = store.New(db, bus)
+			st, err = store.New(db, bus, store.WithLocation(loc))
 			return
 		},
 	}
@@ -59,9 +68,11 @@ func NewDbCommand() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&dbFlags.Config, "config", "", "Override config file for weather service")
 	cmd.AddCommand(newGetLastCommand())
 	cmd.AddCommand(newGetStatsCommand())
+	cmd.AddCommand(newGetWindCommand())
 	cmd.AddCommand(newGetImageCommand())
 	cmd.AddCommand(newArchiveCommand())
 	cmd.AddCommand(newArchiveAllCommand())
+	cmd.AddCommand(newImportCommand())
 
 	return cmd
 }
@@ -1,76 +1,359 @@
 package db
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fatih/structs"
+	"github.com/lmacrc/weather/pkg/filepath/template"
 	"github.com/lmacrc/weather/pkg/weather/meteorology"
 	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service/realtime"
+	"github.com/lmacrc/weather/pkg/weather/units"
 	"github.com/lmacrc/weather/pkg/xunit"
 	"github.com/martinlindhe/unit"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
+const (
+	statsFormatText     = "text"
+	statsFormatJSON     = "json"
+	statsFormatRealtime = "realtime"
+)
+
 func newGetStatsCommand() *cobra.Command {
-	return &cobra.Command{
+	flags := struct {
+		At     string
+		Range  []string
+		Step   time.Duration
+		Format string
+		Output string
+	}{
+		Step:   5 * time.Minute,
+		Format: statsFormatText,
+	}
+
+	const layout = "2006-01-02 15:04:05"
+
+	parse := func(name, v string) (time.Time, error) {
+		t, err := time.ParseInLocation(layout, v, loc)
+		if err != nil {
+			return t, fmt.Errorf("invalid %s %q: must be %q", name, v, layout)
+		}
+		return t, nil
+	}
+
+	cmd := &cobra.Command{
 		Use:   "get-stats",
-		Short: "Get latest statistics",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			r, err := reporting.New(zap.NewNop(), viper.GetViper(), st)
+		Short: "Get the statistics at a time, or for each step of a range",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			var times []time.Time
+			switch {
+			case len(flags.Range) > 0:
+				if flags.At != "" {
+					return fmt.Errorf("specify only one of --at or --range")
+				}
+				if len(flags.Range) != 2 {
+					return fmt.Errorf("invalid range: must be start,end")
+				}
+				if flags.Step <= 0 {
+					return fmt.Errorf("invalid step %s: must be positive", flags.Step)
+				}
+				start, err := parse("range start", flags.Range[0])
+				if err != nil {
+					return err
+				}
+				end, err := parse("range end", flags.Range[1])
+				if err != nil {
+					return err
+				}
+				for ts := start; !ts.After(end); ts = ts.Add(flags.Step) {
+					times = append(times, ts)
+				}
+			case flags.At != "":
+				at, err := parse("at", flags.At)
+				if err != nil {
+					return err
+				}
+				times = append(times, at)
+			default:
+				times = append(times, time.Now().In(loc))
+			}
+
+			var format statsFormatter
+			switch flags.Format {
+			case statsFormatText:
+				format = writeStatsText
+			case statsFormatJSON:
+				format = writeStatsJSON
+			case statsFormatRealtime:
+				cfg := realtime.NewConfig()
+				if err := viper.UnmarshalKey("realtime", &cfg, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
+					return fmt.Errorf("config: %w", err)
+				}
+				format = func(w io.Writer, _ units.System, stats *reporting.Statistics) error {
+					// as the realtime service, which skips the file per the stale policy
+					stats, ok := cfg.Stale.Apply(stats)
+					if !ok {
+						return nil
+					}
+					data, err := realtime.Statistics(*stats).MarshalText()
+					if err != nil {
+						return err
+					}
+					_, err = fmt.Fprintf(w, "%s\n", data)
+					return err
+				}
+			default:
+				return fmt.Errorf("invalid format %q: expect %s,%s,%s", flags.Format, statsFormatText, statsFormatJSON, statsFormatRealtime)
+			}
+
+			out, err := newStatsOutput(flags.Output)
 			if err != nil {
 				return err
 			}
+			defer func() {
+				if cerr := out.Close(); err == nil {
+					err = cerr
+				}
+			}()
 
-			res := r.Generate(time.Now())
-			s := structs.New(res)
-			fields := s.Fields()
-			ftoa := func(v float64) string {
-				return strconv.FormatFloat(v, 'f', 1, 64)
+			r, err := reporting.New(zap.NewNop(), viper.GetViper(), st)
+			if err != nil {
+				return err
 			}
 
-			for _, f := range fields {
-				var s string
-				switch v := f.Value().(type) {
-				case unit.Temperature:
-					s = ftoa(v.Celsius()) + " °C"
-				case unit.Speed:
-					s = ftoa(v.KilometersPerHour()) + " km/h"
-				case unit.Pressure:
-					s = ftoa(v.Hectopascals()) + " hPa"
-				case unit.Length:
-					s = ftoa(v.Millimeters()) + " mm"
-				case xunit.Irradiance:
-					s = ftoa(v.WattsPerSquareMetre()) + " w/m²"
-				case unit.Angle:
-					s = ftoa(v.Degrees()) + "°"
-				case string:
-					s = v
-				case bool:
-					if v {
-						s = "✓"
-					} else {
-						s = "𐄂"
-					}
-				case int:
-					s = strconv.Itoa(v)
-				case time.Time:
-					s = v.Format(time.RFC850)
-				case meteorology.Direction:
-					s = string(v)
-				case float64:
-					s = ftoa(v)
-				default:
-					s = "<no conversion>"
+			for _, ts := range times {
+				w, err := out.Writer(ts)
+				if err != nil {
+					return err
+				}
+				if err := format(w, r.Units(), r.Generate(ts)); err != nil {
+					return err
 				}
-
-				fmt.Printf("%-20s: %s\n", f.Name(), s)
 			}
 
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&flags.At, "at", "", "Time of the statistics, "+layout+", rather than now")
+	cmd.Flags().StringSliceVar(&flags.Range, "range", nil, "Start and end times, "+layout+", of the statistics for each step")
+	cmd.Flags().DurationVar(&flags.Step, "step", flags.Step, "Interval between the statistics of --range")
+	cmd.Flags().StringVar(&flags.Format, "format", flags.Format, "Output format: text, json or realtime (realtime.txt)")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Write the statistics to a file, rather than stdout. "+
+		"The file name is a template, such as realtime-{{ strftime \"%Y%m%d%H%M\" .Date }}.txt, to write a file for each time")
+
+	return cmd
+}
+
+// statsOutput writes the statistics to stdout, or to the file named by the template for the time of the statistics.
+// The statistics are appended to the current file whilst the name is unchanged.
+type statsOutput struct {
+	dir      string
+	filename *template.Template
+	path     string
+	f        *os.File
+}
+
+func newStatsOutput(output string) (*statsOutput, error) {
+	if output == "" {
+		return &statsOutput{}, nil
+	}
+
+	filename, err := template.New("output").Parse(filepath.Base(output))
+	if err != nil {
+		return nil, fmt.Errorf("parsing output: %w", err)
+	}
+	return &statsOutput{dir: filepath.Dir(output), filename: filename}, nil
+}
+
+func (o *statsOutput) Writer(ts time.Time) (io.Writer, error) {
+	if o.filename == nil {
+		return os.Stdout, nil
+	}
+
+	var buf bytes.Buffer
+	if err := o.filename.Execute(&buf, map[string]interface{}{"Date": ts}); err != nil {
+		return nil, fmt.Errorf("output template: %w", err)
+	}
+
+	path := filepath.Join(o.dir, buf.String())
+	if o.f != nil && path == o.path {
+		return o.f, nil
+	}
+
+	if err := o.Close(); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	o.path, o.f = path, f
+	return f, nil
+}
+
+func (o *statsOutput) Close() error {
+	if o.f == nil {
+		return nil
+	}
+	err := o.f.Close()
+	o.f = nil
+	return err
+}
+
+type statsFormatter func(w io.Writer, u units.System, stats *reporting.Statistics) error
+
+// statsField is a field of the statistics, converted to the unit system.
+type statsField struct {
+	Name  string
+	Value interface{}
+	Unit  string
+}
+
+func statsFields(u units.System, stats *reporting.Statistics) []statsField {
+	ftoa := func(v float64, prec int) json.Number {
+		return json.Number(strconv.FormatFloat(v, 'f', prec, 64))
+	}
+
+	var res []statsField
+	for _, f := range structs.New(stats).Fields() {
+		sf := statsField{Name: f.Name()}
+		switch v := f.Value().(type) {
+		case unit.Temperature:
+			if f.Name() == "TempTrend" || strings.HasSuffix(f.Name(), "Anomaly") {
+				sf.Value = ftoa(u.Temperature.Delta(unit.Temperature(v.Celsius())), 1)
+			} else {
+				sf.Value = ftoa(u.Temperature.To(v), 1)
+			}
+			sf.Unit = "°" + string(u.Temperature)
+		case unit.Speed:
+			sf.Value, sf.Unit = ftoa(u.Speed.To(v), 1), string(u.Speed)
+		case unit.Pressure:
+			sf.Value, sf.Unit = ftoa(u.Pressure.To(v), pressurePrec(u.Pressure)), string(u.Pressure)
+		case unit.Length:
+			if f.Name() == "WindRun" {
+				sf.Value, sf.Unit = ftoa(u.Speed.Distance().To(v), 1), string(u.Speed.Distance())
+			} else {
+				sf.Value, sf.Unit = ftoa(u.Rain.To(v), rainPrec(u.Rain)), string(u.Rain)
+			}
+		case xunit.Irradiance:
+			sf.Value, sf.Unit = ftoa(v.WattsPerSquareMetre(), 1), "w/m²"
+		case unit.Angle:
+			sf.Value, sf.Unit = ftoa(v.Degrees(), 1), "°"
+		case float64:
+			sf.Value = ftoa(v, 1)
+		case meteorology.ZambrettiForecast:
+			sf.Value = v.Letter() + " - " + v.String()
+		case meteorology.PressureCharacteristic:
+			sf.Value = v.Code()
+		case meteorology.Direction:
+			sf.Value = string(v)
+		case string, bool, int, time.Time:
+			sf.Value = v
+		default:
+			sf.Value = nil
+		}
+		res = append(res, sf)
+	}
+
+	return res
+}
+
+// writeStatsText writes the statistics as a line for each field, followed by a blank line.
+func writeStatsText(w io.Writer, u units.System, stats *reporting.Statistics) error {
+	for _, f := range statsFields(u, stats) {
+		var s string
+		switch v := f.Value.(type) {
+		case json.Number:
+			s = string(v)
+			if f.Unit == "°" {
+				s += f.Unit
+			} else if f.Unit != "" {
+				s += " " + f.Unit
+			}
+		case string:
+			s = v
+		case bool:
+			if v {
+				s = "✓"
+			} else {
+				s = "𐄂"
+			}
+		case int:
+			s = strconv.Itoa(v)
+		case time.Time:
+			s = v.Format(time.RFC850)
+		default:
+			s = "<no conversion>"
+		}
+
+		if _, err := fmt.Fprintf(w, "%-20s: %s\n", f.Name, s); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// writeStatsJSON writes the statistics as a JSON object on a single line. The values are in
+// the unit system, whose units are the WindUnits, TempUnits, PressureUnits and RainUnits fields.
+func writeStatsJSON(w io.Writer, u units.System, stats *reporting.Statistics) error {
+	fields := statsFields(u, stats)
+
+	// preserve the order of the fields
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		v := f.Value
+		if n, ok := v.(json.Number); ok {
+			if f, err := n.Float64(); err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				// NaN and infinity are not valid JSON
+				v = nil
+			}
+		}
+		name, _ := json.Marshal(f.Name)
+		value, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func pressurePrec(u units.Pressure) int {
+	if u == units.InchOfMercury {
+		return 2
+	}
+	return 1
+}
+
+func rainPrec(u units.Rain) int {
+	if u == units.Inches {
+		return 2
+	}
+	return 1
 }
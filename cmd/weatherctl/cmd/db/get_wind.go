@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func newGetWindCommand() *cobra.Command {
+	flags := struct {
+		Start   string
+		End     string
+		Period  time.Duration
+		Classes []float64
+	}{
+		Period:  24 * time.Hour,
+		Classes: []float64{10, 20, 30, 40},
+	}
+
+	const layout = "2006-01-02 15:04"
+
+	cmd := &cobra.Command{
+		Use:   "get-wind",
+		Short: "Get wind statistics and wind rose for a period",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			end := time.Now().In(loc)
+			if flags.End != "" {
+				end, err = time.ParseInLocation(layout, flags.End, loc)
+				if err != nil {
+					return fmt.Errorf("invalid end %q: must be %q", flags.End, layout)
+				}
+			}
+
+			start := end.Add(-flags.Period)
+			if flags.Start != "" {
+				start, err = time.ParseInLocation(layout, flags.Start, loc)
+				if err != nil {
+					return fmt.Errorf("invalid start %q: must be %q", flags.Start, layout)
+				}
+			}
+
+			r, err := reporting.New(zap.NewNop(), viper.GetViper(), st)
+			if err != nil {
+				return err
+			}
+
+			u := r.Units().Speed
+			classes := make([]unit.Speed, 0, len(flags.Classes))
+			for _, c := range flags.Classes {
+				classes = append(classes, u.From(c))
+			}
+
+			ws := r.WindStatistics(start, end)
+			if ws.Samples == 0 {
+				fmt.Println("No observations for period.")
+				return nil
+			}
+
+			ftoa := func(v float64) string {
+				return strconv.FormatFloat(v, 'f', 1, 64)
+			}
+
+			fmt.Printf("%-20s: %s - %s\n", "Period", start.Format(layout), end.Format(layout))
+			fmt.Printf("%-20s: %d\n", "Samples", ws.Samples)
+			fmt.Printf("%-20s: %s %s\n", "Scalar mean speed", ftoa(u.To(ws.ScalarMeanSpeed)), u)
+			fmt.Printf("%-20s: %s %s\n", "Vector mean speed", ftoa(u.To(ws.VectorMeanSpeed)), u)
+			fmt.Printf("%-20s: %s° (%s)\n", "Vector mean dir", ftoa(ws.VectorMeanDirection.Degrees()), meteorology.CardinalDirection(ws.VectorMeanDirection.Degrees()))
+			fmt.Printf("%-20s: %s°\n", "Direction std dev", ftoa(ws.DirectionStdDev.Degrees()))
+			fmt.Printf("%-20s: %s %s\n", "Max speed", ftoa(u.To(ws.MaxSpeed)), u)
+			fmt.Printf("%-20s: %s %s\n", "Max gust", ftoa(u.To(ws.MaxGust)), u)
+			fmt.Printf("%-20s: %s\n", "Gust factor", strconv.FormatFloat(ws.GustFactor, 'f', 2, 64))
+			fmt.Printf("%-20s: %s%%\n", "Calm", ftoa(ws.Calm*100))
+			fmt.Println()
+
+			wr := r.WindRose(start, end, classes)
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+			header := []string{"Dir"}
+			lo := 0.0
+			for _, c := range flags.Classes {
+				header = append(header, fmt.Sprintf("%s-%s", ftoa(lo), ftoa(c)))
+				lo = c
+			}
+			header = append(header, fmt.Sprintf(">=%s", ftoa(lo)), "Total")
+			fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+
+			for i, dir := range wr.Directions() {
+				row := []string{dir.String()}
+				var total float64
+				for _, f := range wr.Frequency[i] {
+					row = append(row, ftoa(f*100))
+					total += f
+				}
+				row = append(row, ftoa(total*100))
+				fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+			}
+			_ = tw.Flush()
+
+			fmt.Printf("\nFrequencies are %% of samples; speed classes are %s; calm %s%%.\n", u, ftoa(wr.Calm*100))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Start, "start", "", "Start of the period, "+layout+" (default end - period)")
+	cmd.Flags().StringVar(&flags.End, "end", "", "End of the period, "+layout+" (default now)")
+	cmd.Flags().DurationVar(&flags.Period, "period", flags.Period, "Duration of the period, when start is not specified")
+	cmd.Flags().Float64SliceVar(&flags.Classes, "classes", flags.Classes, "Upper bounds of the wind rose speed classes, in the configured wind units")
+
+	return cmd
+}
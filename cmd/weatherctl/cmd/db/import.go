@@ -0,0 +1,387 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/importer/cumulus"
+	"github.com/lmacrc/weather/pkg/weather/importer/normals"
+	"github.com/lmacrc/weather/pkg/weather/importer/weewx"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/spf13/cobra"
+)
+
+func newImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import historical data from other weather applications",
+	}
+
+	cmd.AddCommand(newImportCumulusCommand())
+	cmd.AddCommand(newImportWeeWXCommand())
+	cmd.AddCommand(newImportNormalsCommand())
+
+	return cmd
+}
+
+// observationBatch buffers observations to be written to the store in batches.
+type observationBatch struct {
+	since, until time.Time
+	obs          []model.Observation
+	read         int // read is the number of observations in range
+	count        int // count is the number of observations written, excluding those already in the store
+}
+
+func (b *observationBatch) Add(o model.Observation) error {
+	if !inRange(o.Timestamp, b.since, b.until) {
+		return nil
+	}
+
+	b.read++
+	b.obs = append(b.obs, o)
+	if len(b.obs) >= 1000 {
+		return b.Flush()
+	}
+	return nil
+}
+
+func (b *observationBatch) Flush() error {
+	if len(b.obs) == 0 {
+		return nil
+	}
+	n, err := st.WriteObservations(b.obs)
+	if err != nil {
+		return fmt.Errorf("write observations: %w", err)
+	}
+	b.count += n
+	b.obs = b.obs[:0]
+	return nil
+}
+
+// inRange returns true when ts is at or after since, and before until, unless either is zero.
+// The same range applies to the observations and the dates of the daily summaries.
+func inRange(ts, since, until time.Time) bool {
+	return !ts.Before(since) && (until.IsZero() || ts.Before(until))
+}
+
+func parseDateFlag(name, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	ts, err := time.ParseInLocation("20060102", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s date %q: must be YYYYMMDD", name, s)
+	}
+	return ts, nil
+}
+
+func newImportCumulusCommand() *cobra.Command {
+	var flags = struct {
+		since, until     string
+		system           string
+		temp, wind       string
+		pressure, rain   string
+		fieldSeparator   string
+		decimalSeparator string
+	}{
+		system: "metric",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "cumulus PATH [PATH...]",
+		Short: "Import Cumulus MX monthly log files (MMMyylog.txt) and dayfile.txt",
+		Long: `Import Cumulus MX monthly log files (MMMyylog.txt) and dayfile.txt.
+
+Each PATH may be a file or a Cumulus data directory, in which case all monthly log
+files and the dayfile.txt it contains are imported. Observations are read from the
+monthly log files and daily summaries are read from dayfile.txt.
+
+The units must match those Cumulus was configured to use when the files were recorded.
+Observations with the time of an existing observation are skipped, and existing daily
+summaries for the same date are replaced, so an import may be repeated.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			opts := cumulus.NewOptions()
+			opts.Location = loc
+
+			if opts.Units, err = units.ParseSystem(flags.system); err != nil {
+				return err
+			}
+			if flags.temp != "" {
+				if opts.Units.Temperature, err = units.ParseTemperature(flags.temp); err != nil {
+					return err
+				}
+			}
+			if flags.wind != "" {
+				if opts.Units.Speed, err = units.ParseSpeed(flags.wind); err != nil {
+					return err
+				}
+			}
+			if flags.pressure != "" {
+				if opts.Units.Pressure, err = units.ParsePressure(flags.pressure); err != nil {
+					return err
+				}
+			}
+			if flags.rain != "" {
+				if opts.Units.Rain, err = units.ParseRain(flags.rain); err != nil {
+					return err
+				}
+			}
+			if opts.FieldSeparator, err = parseSeparatorFlag("field-separator", flags.fieldSeparator); err != nil {
+				return err
+			}
+			if opts.DecimalSeparator, err = parseSeparatorFlag("decimal-separator", flags.decimalSeparator); err != nil {
+				return err
+			}
+
+			var batch observationBatch
+			if batch.since, err = parseDateFlag("since", flags.since); err != nil {
+				return err
+			}
+			if batch.until, err = parseDateFlag("until", flags.until); err != nil {
+				return err
+			}
+
+			paths, err := cumulusPaths(args)
+			if err != nil {
+				return err
+			}
+
+			for _, path := range paths {
+				if strings.EqualFold(filepath.Base(path), "dayfile.txt") {
+					n, err := importCumulusDayfile(path, opts, batch.since, batch.until)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Imported %d daily summaries from %s\n", n, path)
+					continue
+				}
+
+				before := batch.read
+				err := readFile(path, func(f *os.File) error {
+					return cumulus.ReadLog(f, opts, batch.Add)
+				})
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Read %d observations from %s\n", batch.read-before, path)
+			}
+
+			if err := batch.Flush(); err != nil {
+				return err
+			}
+			fmt.Printf("Imported %d new observations\n", batch.count)
+
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&flags.since, "since", "", "Only import data from the start of the specified date (YYYYMMDD)")
+	fs.StringVar(&flags.until, "until", "", "Only import data prior to the specified date (YYYYMMDD)")
+	fs.StringVar(&flags.system, "units", flags.system, "Unit system of the data files (metric, metricwx, us)")
+	fs.StringVar(&flags.temp, "temp-unit", "", "Override the temperature unit (C, F)")
+	fs.StringVar(&flags.wind, "wind-unit", "", "Override the wind speed unit (m/s, km/h, mph, kts)")
+	fs.StringVar(&flags.pressure, "pressure-unit", "", "Override the pressure unit (hPa, mb, inHg)")
+	fs.StringVar(&flags.rain, "rain-unit", "", "Override the rain unit (mm, in)")
+	fs.StringVar(&flags.fieldSeparator, "field-separator", "", "Field separator of the data files; detected when unspecified")
+	fs.StringVar(&flags.decimalSeparator, "decimal-separator", "", "Decimal separator of the data files; detected when unspecified")
+
+	return cmd
+}
+
+func parseSeparatorFlag(name, s string) (rune, error) {
+	switch len([]rune(s)) {
+	case 0:
+		return 0, nil
+	case 1:
+		return []rune(s)[0], nil
+	default:
+		return 0, fmt.Errorf("invalid %s %q: must be a single character", name, s)
+	}
+}
+
+// cumulusPaths expands any directories in args to the Cumulus data files they contain.
+func cumulusPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		fi, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+
+		logs, err := filepath.Glob(filepath.Join(arg, "*log.txt"))
+		if err != nil {
+			return nil, err
+		}
+		// sort monthly logs chronologically, as they are named MMMyylog.txt
+		sortCumulusLogs(logs)
+		paths = append(paths, logs...)
+
+		dayfile := filepath.Join(arg, "dayfile.txt")
+		if _, err := os.Stat(dayfile); err == nil {
+			paths = append(paths, dayfile)
+		}
+	}
+	return paths, nil
+}
+
+func sortCumulusLogs(paths []string) {
+	key := func(path string) string {
+		base := filepath.Base(path)
+		if len(base) < 5 {
+			return base
+		}
+		ts, err := time.Parse("Jan06", base[:5])
+		if err != nil {
+			return base
+		}
+		return ts.Format("200601")
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		return key(paths[i]) < key(paths[j])
+	})
+}
+
+func importCumulusDayfile(path string, opts cumulus.Options, since, until time.Time) (int, error) {
+	n := 0
+	err := readFile(path, func(f *os.File) error {
+		return cumulus.ReadDayfile(f, opts, func(ds model.DailySummary) error {
+			if !inRange(ds.Date, since, until) {
+				return nil
+			}
+			n++
+			return st.WriteDailySummary(ds)
+		})
+	})
+	return n, err
+}
+
+func readFile(path string, fn func(f *os.File) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := fn(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func newImportWeeWXCommand() *cobra.Command {
+	var flags = struct {
+		since, until string
+	}{}
+
+	cmd := &cobra.Command{
+		Use:   "weewx PATH",
+		Short: "Import the archive table of a WeeWX SQLite database (weewx.sdb)",
+		Long: `Import the archive table of a WeeWX SQLite database (weewx.sdb).
+
+Records stored using the US, METRIC and METRICWX unit systems are supported. Records
+without an outside temperature, humidity or barometer are skipped, as are those with
+the time of an existing observation, so an import may be repeated.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			var batch observationBatch
+			if batch.since, err = parseDateFlag("since", flags.since); err != nil {
+				return err
+			}
+			if batch.until, err = parseDateFlag("until", flags.until); err != nil {
+				return err
+			}
+
+			ar, err := weewx.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = ar.Close() }()
+
+			if err := ar.Read(batch.since, loc, batch.Add); err != nil {
+				return err
+			}
+			if err := batch.Flush(); err != nil {
+				return err
+			}
+			fmt.Printf("Imported %d new observations\n", batch.count)
+
+			return nil
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&flags.since, "since", "", "Only import data from the start of the specified date (YYYYMMDD)")
+	fs.StringVar(&flags.until, "until", "", "Only import data prior to the specified date (YYYYMMDD)")
+
+	return cmd
+}
+
+func newImportNormalsCommand() *cobra.Command {
+	var flags = struct {
+		format string
+	}{
+		format: "csv",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "normals PATH",
+		Short: "Import climate normals, the long-term averages and extremes of each month or day",
+		Long: `Import climate normals, the long-term averages and extremes of each month or day.
+
+The csv format has a header naming the columns, of which only month is required:
+
+  month,day,temp_hi_mean_c,temp_lo_mean_c,temp_mean_c,temp_hi_record_c,temp_lo_record_c,rain_mm
+
+A normal is for the whole month when the day is blank. The bom format is the climate
+statistics of a site published by the Australian Bureau of Meteorology as CSV, which
+provides the normals of each month.
+
+Existing normals for the same month and day are replaced.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var read func(io.Reader, func(model.ClimateNormal) error) error
+			switch flags.format {
+			case "csv":
+				read = normals.ReadCSV
+			case "bom":
+				read = normals.ReadBoM
+			default:
+				return fmt.Errorf("invalid format %q: expect csv,bom", flags.format)
+			}
+
+			var res []model.ClimateNormal
+			err := readFile(args[0], func(f *os.File) error {
+				return read(f, func(cn model.ClimateNormal) error {
+					res = append(res, cn)
+					return nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+			if err := st.WriteClimateNormals(res); err != nil {
+				return fmt.Errorf("write climate normals: %w", err)
+			}
+			fmt.Printf("Imported %d climate normals\n", len(res))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.format, "format", flags.format, "Format of the file: csv or bom")
+
+	return cmd
+}
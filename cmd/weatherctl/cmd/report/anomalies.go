@@ -0,0 +1,125 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func newAnomaliesCommand() *cobra.Command {
+	flags := struct {
+		Month string
+	}{}
+
+	const layout = "2006-01"
+
+	cmd := &cobra.Command{
+		Use:   "anomalies",
+		Short: "Compare each day of a month with the climate normals and the archived days of the month",
+		Long: `Compare each day of a month with the climate normals and the archived days of the month.
+
+The anomalies are the departures from the normal means, imported using
+"weatherctl db import normals", and the percentile ranks are amongst the
+archived days of the same month in any year.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			month := time.Now().In(loc)
+			if flags.Month != "" {
+				if month, err = time.ParseInLocation(layout, flags.Month, loc); err != nil {
+					return fmt.Errorf("invalid month %q: must be %q", flags.Month, layout)
+				}
+			}
+			start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, loc)
+
+			r, err := reporting.New(zap.NewNop(), viper.GetViper(), st)
+			if err != nil {
+				return err
+			}
+
+			days, err := r.DailySummaries(start, start.AddDate(0, 1, 0))
+			if err != nil {
+				return fmt.Errorf("daily summaries: %w", err)
+			}
+
+			return writeAnomalies(os.Stdout, r, start, days)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Month, "month", "", "Month of the report, "+layout+", rather than the current month")
+
+	return cmd
+}
+
+func writeAnomalies(w io.Writer, r *reporting.Reporter, month time.Time, days []*model.DailySummary) error {
+	u := r.Units()
+	temp := "°" + string(u.Temperature)
+
+	delta := func(t unit.Temperature) string {
+		if v := u.Temperature.Delta(unit.Temperature(t.Celsius())); !math.IsNaN(v) {
+			return fmt.Sprintf("%+.1f", v)
+		}
+		return "-"
+	}
+	pct := func(v float64) string {
+		if math.IsNaN(v) {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f", v)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Day\tHigh %[1]s\tAnomaly\tPercentile\tLow %[1]s\tAnomaly\tPercentile\tRain %[2]s\t%% Normal\tPercentile\t\n", temp, u.Rain)
+
+	var (
+		sum  float64
+		rain unit.Length
+	)
+	for _, ds := range days {
+		a, err := r.Anomalies(ds)
+		if err != nil {
+			return fmt.Errorf("anomalies: %w", err)
+		}
+		sum += (ds.TempHi.Celsius() + ds.TempLo.Celsius()) / 2
+		rain += ds.Rain
+
+		fmt.Fprintf(tw, "%d\t%.1f\t%s\t%s\t%.1f\t%s\t%s\t%.1f\t%s\t%s\t\n", ds.Date.Day(),
+			u.Temperature.To(ds.TempHi), delta(a.TempHi), pct(a.TempHiPercentile),
+			u.Temperature.To(ds.TempLo), delta(a.TempLo), pct(a.TempLoPercentile),
+			u.Rain.To(ds.Rain), pct(a.RainPercent), pct(a.RainPercentile))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	normal, err := st.ClimateNormal(month.Month(), 0)
+	if err != nil {
+		return fmt.Errorf("climate normals: %w", err)
+	}
+	if normal == nil || len(days) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	if normal.TempMean != 0 {
+		d := u.Temperature.Delta(unit.Temperature(sum/float64(len(days)) - normal.TempMean.Celsius()))
+		dir := "above"
+		if d < 0 {
+			dir = "below"
+		}
+		fmt.Fprintf(w, "Mean temperature %.1f%s %s the %s average\n", math.Abs(d), temp, dir, month.Month())
+	}
+	if normal.Rain > 0 {
+		fmt.Fprintf(w, "Rainfall %.1f %s, %.0f%% of the %s average\n", u.Rain.To(rain), u.Rain, 100*float64(rain/normal.Rain), month.Month())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service/noaa"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func newNoaaCommand() *cobra.Command {
+	flags := struct {
+		Month  string
+		Year   int
+		Output string
+	}{}
+
+	const layout = "2006-01"
+
+	cmd := &cobra.Command{
+		Use:   "noaa",
+		Short: "Generate a NOAA monthly or yearly climate summary",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if (flags.Month == "") == (flags.Year == 0) {
+				return errors.New("specify one of --month or --year")
+			}
+
+			r, err := reporting.New(zap.NewNop(), viper.GetViper(), st)
+			if err != nil {
+				return err
+			}
+
+			svc, err := noaa.New(zap.NewNop(), viper.GetViper(), r, nil)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if flags.Output != "" {
+				f, err := os.Create(flags.Output)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = f.Close() }()
+				w = f
+			}
+
+			if flags.Year != 0 {
+				return svc.WriteYear(w, time.Date(flags.Year, time.January, 1, 0, 0, 0, 0, loc))
+			}
+
+			month, err := time.ParseInLocation(layout, flags.Month, loc)
+			if err != nil {
+				return fmt.Errorf("invalid month %q: must be %q", flags.Month, layout)
+			}
+			return svc.WriteMonth(w, month)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Month, "month", "", "Month of the report, "+layout)
+	cmd.Flags().IntVar(&flags.Year, "year", 0, "Year of the report")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Write the report to a file, rather than stdout")
+
+	return cmd
+}
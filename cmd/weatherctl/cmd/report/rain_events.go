@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newRainEventsCommand() *cobra.Command {
+	flags := struct {
+		Since string
+		Until string
+	}{}
+
+	const layout = "2006-01-02"
+
+	cmd := &cobra.Command{
+		Use:   "rain-events",
+		Short: "List the rain events, or storms, of a period",
+		Long: `List the rain events, or storms, of a period, which defaults to the last 7 days.
+
+An event ends once no rain has fallen for the event_dry_gap of the [reporting.rain]
+configuration, and is in progress until then.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			until := time.Now().In(loc)
+			if flags.Until != "" {
+				if until, err = time.ParseInLocation(layout, flags.Until, loc); err != nil {
+					return fmt.Errorf("invalid until %q: must be %q", flags.Until, layout)
+				}
+			}
+			since := until.AddDate(0, 0, -7)
+			if flags.Since != "" {
+				if since, err = time.ParseInLocation(layout, flags.Since, loc); err != nil {
+					return fmt.Errorf("invalid since %q: must be %q", flags.Since, layout)
+				}
+			}
+
+			cfg, err := reporting.ReadConfig(viper.GetViper())
+			if err != nil {
+				return err
+			}
+			u, err := cfg.Units.Resolve()
+			if err != nil {
+				return err
+			}
+
+			events, err := st.RainEvents(since, until)
+			if err != nil {
+				return fmt.Errorf("rain events: %w", err)
+			}
+
+			const timeLayout = "2006-01-02 15:04"
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "Start\tEnd\tDuration\tRain %[1]s\tPeak rate %[1]s/h\tPeak time\t\n", u.Rain)
+			for _, ev := range events {
+				end := ev.End.In(loc).Format(timeLayout)
+				if ev.InProgress {
+					end += " (in progress)"
+				}
+				peak := "-"
+				if !ev.RainRateHiTime.IsZero() {
+					peak = ev.RainRateHiTime.In(loc).Format(timeLayout)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%.*f\t%.*f\t%s\t\n",
+					ev.Start.In(loc).Format(timeLayout), end, ev.Duration.Round(time.Minute),
+					rainPrec(u.Rain), u.Rain.To(ev.Rain), rainPrec(u.Rain), u.Rain.To(ev.RainRateHi), peak)
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Since, "since", "", "Start date of the period, "+layout)
+	cmd.Flags().StringVar(&flags.Until, "until", "", "End date of the period, "+layout+", rather than now")
+
+	return cmd
+}
+
+func rainPrec(u units.Rain) int {
+	if u == units.Inches {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var reportFlags = struct {
+	Config string
+}{}
+
+var (
+	st  *store.Store
+	loc *time.Location // loc is the time zone of the station
+)
+
+func NewReportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Commands to generate weather reports",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) (err error) {
+			err = weather.ReadConfig(reportFlags.Config)
+			if err != nil {
+				return
+			}
+
+			db, err := weather.OpenDb(viper.GetString("database.url"))
+			if err != nil {
+				return err
+			}
+
+			loc, err = calendar.LoadLocation(viper.GetViper())
+			if err != nil {
+				return err
+			}
+
+			st, err = store.New(db, event.New(), store.WithLocation(loc))
+			return
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&reportFlags.Config, "config", "", "Override config file for weather service")
+	cmd.AddCommand(newNoaaCommand())
+	cmd.AddCommand(newAnomaliesCommand())
+	cmd.AddCommand(newRainEventsCommand())
+	cmd.AddCommand(newWebtagsCommand())
+
+	return cmd
+}
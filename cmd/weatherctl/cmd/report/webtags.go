@@ -0,0 +1,83 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service/webtags"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func newWebtagsCommand() *cobra.Command {
+	flags := struct {
+		At     string
+		Format string
+		Output string
+	}{
+		Format: string(webtags.FormatCumulus),
+	}
+
+	const layout = "2006-01-02 15:04:05"
+
+	cmd := &cobra.Command{
+		Use:   "webtags TEMPLATE",
+		Short: "Render a template file of Cumulus web tags or a Go template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			var format webtags.Format
+			if err := format.UnmarshalText([]byte(flags.Format)); err != nil {
+				return err
+			}
+
+			ts := time.Now().In(loc)
+			if flags.At != "" {
+				ts, err = time.ParseInLocation(layout, flags.At, loc)
+				if err != nil {
+					return fmt.Errorf("invalid at %q: must be %q", flags.At, layout)
+				}
+			}
+
+			r, err := reporting.New(zap.NewNop(), viper.GetViper(), st)
+			if err != nil {
+				return err
+			}
+
+			svc, err := webtags.New(zap.NewNop(), viper.GetViper(), r, nil)
+			if err != nil {
+				return err
+			}
+
+			d, err := svc.Data(ts)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if flags.Output != "" {
+				f, err := os.Create(flags.Output)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if cerr := f.Close(); err == nil {
+						err = cerr
+					}
+				}()
+				w = f
+			}
+
+			return svc.Render(w, args[0], format, d)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.At, "at", "", "Time of the statistics, "+layout+", rather than now")
+	cmd.Flags().StringVar(&flags.Format, "format", flags.Format, "Format of the template: cumulus or go")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Write the rendered template to a file, rather than stdout")
+
+	return cmd
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/lmacrc/weather/cmd/weatherctl/cmd/db"
+	"github.com/lmacrc/weather/cmd/weatherctl/cmd/report"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +15,7 @@ func Execute() {
 	}
 
 	cmd.AddCommand(db.NewDbCommand())
+	cmd.AddCommand(report.NewReportCommand())
 
 	if err := cmd.Execute(); err != nil {
 		cmd.PrintErr(err)
@@ -2,6 +2,9 @@ package main
 
 import (
 	"github.com/lmacrc/weather/cmd/weatherctl/cmd"
+
+	// embed the time zone database, for hosts without one
+	_ "time/tzdata"
 )
 
 func main() {
@@ -17,13 +17,19 @@ type Template struct {
 // New allocates a new, undefined template with the given name.
 func New(name string) *Template {
 	t := template.New(name)
-	t.Funcs(template.FuncMap{
-		"strftime": strftimeFn,
-	})
+	t.Funcs(FuncMap())
 
 	return &Template{t}
 }
 
+// FuncMap returns the functions of the templates, such as strftime, for
+// templates of other content than file names.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"strftime": strftimeFn,
+	}
+}
+
 func (t *Template) ExecuteTemplate(wr io.Writer, name string, data interface{}) error {
 	var buf bytes.Buffer
 	err := t.inner.ExecuteTemplate(&buf, name, data)
@@ -64,6 +70,7 @@ func strftimeFn(f string, t time.Time) (string, error) {
 // Must is a helper that wraps a call to a function returning (*Template, error)
 // and panics if the error is non-nil. It is intended for use in variable
 // initializations such as
+//
 //	var t = template.Must(template.New("name").Parse("text"))
 func Must(t *Template, err error) *Template {
 	if err != nil {
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+const CurrentDate = "2006-01-02"
+
+// Date stores the calendar date of a time.Time, ignoring the time of day and location.
+type Date struct{ time.Time }
+
+func DateFromTime(t time.Time) Date {
+	return Date{t}
+}
+
+// In returns the midnight of the date in loc.
+func (dt Date) In(loc *time.Location) time.Time {
+	y, m, d := dt.Time.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+func (dt Date) Value() (driver.Value, error) {
+	return dt.Time.Format(CurrentDate), nil
+}
+
+func (dt *Date) Scan(value interface{}) error {
+	var err error
+	switch v := value.(type) {
+	case string:
+		dt.Time, err = time.Parse(CurrentDate, v)
+	case []byte:
+		dt.Time, err = time.Parse(CurrentDate, string(v))
+	case time.Time:
+		dt.Time = v
+	default:
+		err = errors.New("invalid type for current_date")
+	}
+	return err
+}
+
+func (dt *Date) MarshalCSV() (string, error) {
+	return dt.Time.Format(CurrentDate), nil
+}
+
+func (dt *Date) UnmarshalCSV(csv string) (err error) {
+	dt.Time, err = time.Parse(CurrentDate, csv)
+	return err
+}
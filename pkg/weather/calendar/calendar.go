@@ -0,0 +1,102 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Calendar determines the boundaries of the meteorological day, which may start
+// at an hour other than midnight, such as the 9am rain day of the Bureau of Meteorology.
+//
+// A meteorological day is identified by the date on which it starts, in the time zone of the station.
+type Calendar struct {
+	startHour int
+	loc       *time.Location // loc is nil when the time zone is not configured
+}
+
+func New(vp *viper.Viper) (*Calendar, error) {
+	cfg := NewConfig()
+	if err := vp.UnmarshalKey("calendar", &cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	if cfg.DayStartHour < 0 || cfg.DayStartHour > 23 {
+		return nil, fmt.Errorf("config: invalid day_start_hour %d: expect 0 through 23", cfg.DayStartHour)
+	}
+
+	c := &Calendar{startHour: cfg.DayStartHour}
+	if tz := vp.GetString("location.timezone"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("config: invalid location timezone %q: %w", tz, err)
+		}
+		c.loc = loc
+	}
+
+	return c, nil
+}
+
+// LoadLocation returns the time zone of the station, per the location.timezone configuration,
+// or the time zone of the host if it is not configured.
+func LoadLocation(vp *viper.Viper) (*time.Location, error) {
+	c, err := New(vp)
+	if err != nil {
+		return nil, err
+	}
+	return c.Location(), nil
+}
+
+// Location returns the time zone of the station, or the time zone of the host if it is not configured.
+func (c *Calendar) Location() *time.Location {
+	if c.loc == nil {
+		return time.Local
+	}
+	return c.loc
+}
+
+// In returns t in the time zone of the station. When the time zone is not configured, t is
+// returned unchanged, and the meteorological day is determined by the location of t.
+func (c *Calendar) In(t time.Time) time.Time {
+	if c.loc == nil {
+		return t
+	}
+	return t.In(c.loc)
+}
+
+// DayStartHour returns the hour of the day at which the meteorological day starts.
+func (c *Calendar) DayStartHour() int { return c.startHour }
+
+// Date returns midnight of the date which identifies the meteorological day containing t.
+func (c *Calendar) Date(t time.Time) time.Time {
+	t = c.In(t)
+	y, m, d := t.Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	if t.Before(c.Start(date)) {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// BeginningOfDay returns the start of the meteorological day containing t.
+func (c *Calendar) BeginningOfDay(t time.Time) time.Time {
+	return c.Start(c.Date(t))
+}
+
+// Day returns the start and end of the meteorological day containing t.
+// The end is the start of the following meteorological day.
+func (c *Calendar) Day(t time.Time) (start, end time.Time) {
+	date := c.Date(t)
+	return c.Start(date), c.Start(date.AddDate(0, 0, 1))
+}
+
+// Start returns the start of the meteorological day identified by date.
+func (c *Calendar) Start(date time.Time) time.Time {
+	loc := date.Location()
+	if c.loc != nil {
+		loc = c.loc
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d, c.startHour, 0, 0, 0, loc)
+}
@@ -0,0 +1,111 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar(t *testing.T) {
+	loc, err := time.LoadLocation("Australia/Hobart")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		startHour int
+		t         time.Time
+		wantDate  time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "midnight",
+			startHour: 0,
+			t:         time.Date(2021, 7, 2, 8, 30, 0, 0, loc),
+			wantDate:  time.Date(2021, 7, 2, 0, 0, 0, 0, loc),
+			wantStart: time.Date(2021, 7, 2, 0, 0, 0, 0, loc),
+			wantEnd:   time.Date(2021, 7, 3, 0, 0, 0, 0, loc),
+		},
+		{
+			name:      "9am before rollover",
+			startHour: 9,
+			t:         time.Date(2021, 7, 2, 8, 30, 0, 0, loc),
+			wantDate:  time.Date(2021, 7, 1, 0, 0, 0, 0, loc),
+			wantStart: time.Date(2021, 7, 1, 9, 0, 0, 0, loc),
+			wantEnd:   time.Date(2021, 7, 2, 9, 0, 0, 0, loc),
+		},
+		{
+			name:      "9am at rollover",
+			startHour: 9,
+			t:         time.Date(2021, 7, 2, 9, 0, 0, 0, loc),
+			wantDate:  time.Date(2021, 7, 2, 0, 0, 0, 0, loc),
+			wantStart: time.Date(2021, 7, 2, 9, 0, 0, 0, loc),
+			wantEnd:   time.Date(2021, 7, 3, 9, 0, 0, 0, loc),
+		},
+		{
+			name:      "9am daylight saving ends",
+			startHour: 9,
+			t:         time.Date(2021, 4, 4, 12, 0, 0, 0, loc),
+			wantDate:  time.Date(2021, 4, 4, 0, 0, 0, 0, loc),
+			wantStart: time.Date(2021, 4, 4, 9, 0, 0, 0, loc),
+			wantEnd:   time.Date(2021, 4, 5, 9, 0, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Calendar{startHour: tt.startHour}
+			assert.Equal(t, tt.wantDate, c.Date(tt.t))
+			assert.Equal(t, tt.wantStart, c.BeginningOfDay(tt.t))
+
+			start, end := c.Day(tt.t)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	vp := viper.New()
+	c, err := New(vp)
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.DayStartHour())
+
+	vp.Set("calendar.day_start_hour", 9)
+	c, err = New(vp)
+	require.NoError(t, err)
+	assert.Equal(t, 9, c.DayStartHour())
+
+	vp.Set("calendar.day_start_hour", 24)
+	_, err = New(vp)
+	assert.Error(t, err)
+}
+
+func TestNew_Timezone(t *testing.T) {
+	vp := viper.New()
+	c, err := New(vp)
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, c.Location(), "host time zone by default")
+
+	vp.Set("calendar.day_start_hour", 9)
+	vp.Set("location.timezone", "Australia/Hobart")
+	c, err = New(vp)
+	require.NoError(t, err)
+	loc, err := time.LoadLocation("Australia/Hobart")
+	require.NoError(t, err)
+	assert.Equal(t, loc, c.Location())
+
+	// 8:30 on 2 July in Hobart, regardless of the location of the time
+	ts := time.Date(2021, 7, 1, 22, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2021, 7, 1, 0, 0, 0, 0, loc), c.Date(ts))
+	start, end := c.Day(ts)
+	assert.Equal(t, time.Date(2021, 7, 1, 9, 0, 0, 0, loc), start)
+	assert.Equal(t, time.Date(2021, 7, 2, 9, 0, 0, 0, loc), end)
+	assert.Equal(t, time.Date(2021, 7, 2, 9, 0, 0, 0, loc), c.Start(time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC)))
+
+	vp.Set("location.timezone", "Nowhere/Special")
+	_, err = New(vp)
+	assert.Error(t, err)
+}
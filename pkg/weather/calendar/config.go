@@ -0,0 +1,12 @@
+package calendar
+
+type Config struct {
+	// DayStartHour is the hour of the day, 0 through 23, at which the meteorological day starts.
+	DayStartHour int `toml:"day_start_hour" mapstructure:"day_start_hour"`
+}
+
+func NewConfig() Config {
+	return Config{
+		DayStartHour: 0,
+	}
+}
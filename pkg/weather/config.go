@@ -4,29 +4,35 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/lmacrc/weather/pkg/weather/calendar"
 	"github.com/lmacrc/weather/pkg/weather/reporting"
 	"github.com/lmacrc/weather/pkg/weather/service/archive"
 	"github.com/lmacrc/weather/pkg/weather/service/camera"
 	"github.com/lmacrc/weather/pkg/weather/service/camera/remote"
 	"github.com/lmacrc/weather/pkg/weather/service/camera/rpi"
 	"github.com/lmacrc/weather/pkg/weather/service/ftp"
+	"github.com/lmacrc/weather/pkg/weather/service/noaa"
 	"github.com/lmacrc/weather/pkg/weather/service/realtime"
 	"github.com/spf13/viper"
 )
 
 type Location struct {
+	Name      string // Name of the station, for reports
 	Latitude  float64
 	Longitude float64
+	Altitude  float64 // Altitude of the station in metres above sea level
 }
 
 type Config struct {
 	DbPath   string `toml:"database_path" mapstructure:"database_path"`
 	Location Location
+	Calendar calendar.Config
 
 	Ftp       ftp.Config
 	Archive   archive.Config
 	Realtime  realtime.Config
 	Reporting reporting.Config
+	Noaa      noaa.Config
 
 	Camera camera.Config
 
@@ -40,10 +46,12 @@ func NewConfig() Config {
 	return Config{
 		DbPath:    "weather.db",
 		Location:  Location{},
+		Calendar:  calendar.NewConfig(),
 		Ftp:       ftp.NewConfig(),
 		Archive:   archive.NewConfig(),
 		Realtime:  realtime.NewConfig(),
 		Reporting: reporting.NewConfig(),
+		Noaa:      noaa.NewConfig(),
 		Camera:    camera.NewConfig(),
 		CameraDriver: struct {
 			Remote remote.Config
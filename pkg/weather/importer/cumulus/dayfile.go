@@ -0,0 +1,103 @@
+package cumulus
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+// Field indexes of dayfile.txt
+const (
+	dayDate               = 0
+	dayWindGustHi         = 1
+	dayWindGustHiDir      = 2
+	dayWindGustHiTime     = 3
+	dayTempLo             = 4
+	dayTempLoTime         = 5
+	dayTempHi             = 6
+	dayTempHiTime         = 7
+	dayPressureLo         = 8
+	dayPressureLoTime     = 9
+	dayPressureHi         = 10
+	dayPressureHiTime     = 11
+	dayRainRateHi         = 12
+	dayRainRateHiTime     = 13
+	dayRain               = 14
+	dayTempAvg            = 15
+	dayWindRun            = 16
+	dayWindSpeedHi        = 17
+	dayWindSpeedHiTime    = 18
+	dayHumidityLo         = 19
+	dayHumidityHi         = 21
+	dayEvapotranspiration = 23
+	daySunshineHours      = 24
+	dayDominantWindDir    = 39
+	dayHeatingDegreeDays  = 40
+	dayCoolingDegreeDays  = 41
+	daySolarRadiationHi   = 42
+	dayUltravioletIndexHi = 44
+	dayMinimumFieldsLen   = dayWindRun + 1
+)
+
+// ReadDayfile reads the daily summaries of a Cumulus dayfile.txt from r, calling fn for each.
+// Fields added by later versions of Cumulus are left as zero values when they are not present.
+func ReadDayfile(r io.Reader, opts Options, fn func(ds model.DailySummary) error) error {
+	return scanLines(r, func(line string) error {
+		rec := opts.newRecord(line)
+		if len(rec.fields) < dayMinimumFieldsLen {
+			return fmt.Errorf("expected at least %d fields, got %d", dayMinimumFieldsLen, len(rec.fields))
+		}
+
+		date := rec.date(dayDate, opts.Location)
+		u := opts.Units
+
+		ds := model.DailySummary{
+			Date:           date,
+			WindGustHi:     u.Speed.From(rec.float(dayWindGustHi)),
+			WindGustHiDir:  unit.Angle(rec.float(dayWindGustHiDir)) * unit.Degree,
+			WindGustHiTime: rec.clock(dayWindGustHiTime, date),
+			TempLo:         u.Temperature.From(rec.float(dayTempLo)),
+			TempLoTime:     rec.clock(dayTempLoTime, date),
+			TempHi:         u.Temperature.From(rec.float(dayTempHi)),
+			TempHiTime:     rec.clock(dayTempHiTime, date),
+			PressureLo:     u.Pressure.From(rec.float(dayPressureLo)),
+			PressureLoTime: rec.clock(dayPressureLoTime, date),
+			PressureHi:     u.Pressure.From(rec.float(dayPressureHi)),
+			PressureHiTime: rec.clock(dayPressureHiTime, date),
+			RainRateHi:     u.Rain.From(rec.float(dayRainRateHi)),
+			RainRateHiTime: rec.clock(dayRainRateHiTime, date),
+			Rain:           u.Rain.From(rec.float(dayRain)),
+			TempAvg:        u.Temperature.From(rec.float(dayTempAvg)),
+			WindRun:        u.Speed.Distance().From(rec.float(dayWindRun)),
+
+			WindSpeedHi:        u.Speed.From(rec.optFloat(dayWindSpeedHi)),
+			WindSpeedHiTime:    rec.optClock(dayWindSpeedHiTime, date),
+			Evapotranspiration: u.Rain.From(rec.optFloat(dayEvapotranspiration)),
+			SunshineHours:      time.Duration(rec.optFloat(daySunshineHours) * float64(time.Hour)),
+			DominantWindDir:    unit.Angle(rec.optFloat(dayDominantWindDir)) * unit.Degree,
+			HeatingDegreeDays:  rec.optFloat(dayHeatingDegreeDays),
+			CoolingDegreeDays:  rec.optFloat(dayCoolingDegreeDays),
+			SolarRadiationHi:   xunit.Irradiance(rec.optFloat(daySolarRadiationHi)) * xunit.WattPerSquareMetre,
+		}
+
+		if rec.has(dayHumidityLo) {
+			ds.HumidityLo = rec.int(dayHumidityLo)
+		}
+		if rec.has(dayHumidityHi) {
+			ds.HumidityHi = rec.int(dayHumidityHi)
+		}
+		if rec.has(dayUltravioletIndexHi) {
+			ds.UltravioletIndexHi = rec.int(dayUltravioletIndexHi)
+		}
+
+		if rec.err != nil {
+			return rec.err
+		}
+
+		return fn(ds)
+	})
+}
@@ -0,0 +1,101 @@
+// Package cumulus reads the data files of Cumulus MX, for importing historical
+// data into the weather database.
+//
+// See https://cumuluswiki.org/a/Standard_log_files and https://cumuluswiki.org/a/Dayfile.txt
+package cumulus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+// Field indexes of the monthly log file, MMMyylog.txt
+const (
+	logDate             = 0
+	logTime             = 1
+	logTemp             = 2
+	logHumidity         = 3
+	logWindSpeedAvg     = 5
+	logWindGust         = 6
+	logWindBearingAvg   = 7
+	logRainRate         = 8
+	logRainToday        = 9
+	logPressure         = 10
+	logRainCounter      = 11
+	logIndoorTemp       = 12
+	logIndoorHumidity   = 13
+	logUVIndex          = 17
+	logSolarRadiation   = 18
+	logWindBearing      = 24
+	logMinimumFieldsLen = logIndoorHumidity + 1
+)
+
+// ReadLog reads the observations of a Cumulus monthly log file from r, calling fn for each.
+//
+// The log records only the sea level pressure, which is also used as the absolute pressure
+// of the observations, as the station pressure cannot be recovered from it.
+func ReadLog(r io.Reader, opts Options, fn func(o model.Observation) error) error {
+	return scanLines(r, func(line string) error {
+		rec := opts.newRecord(line)
+		if len(rec.fields) < logMinimumFieldsLen {
+			return fmt.Errorf("expected at least %d fields, got %d", logMinimumFieldsLen, len(rec.fields))
+		}
+
+		date := rec.date(logDate, opts.Location)
+		u := opts.Units
+
+		o := model.Observation{
+			Timestamp:       rec.clock(logTime, date),
+			TempOutdoor:     u.Temperature.From(rec.float(logTemp)),
+			HumidityOutdoor: rec.int(logHumidity),
+			WindSpeed:       u.Speed.From(rec.float(logWindSpeedAvg)),
+			WindGust:        u.Speed.From(rec.float(logWindGust)),
+			WindDir:         unit.Angle(rec.float(logWindBearingAvg)) * unit.Degree,
+			RainRatePerHour: u.Rain.From(rec.float(logRainRate)),
+			DailyRain:       u.Rain.From(rec.float(logRainToday)),
+			BarometricRel:   u.Pressure.From(rec.float(logPressure)),
+			TotalRain:       u.Rain.From(rec.float(logRainCounter)),
+			TempIndoor:      u.Temperature.From(rec.float(logIndoorTemp)),
+			HumidityIndoor:  rec.int(logIndoorHumidity),
+		}
+
+		o.BarometricAbs = o.BarometricRel
+
+		if rec.has(logUVIndex) {
+			o.UltravioletIndex = rec.int(logUVIndex)
+		}
+		o.SolarRadiation = xunit.Irradiance(rec.optFloat(logSolarRadiation)) * xunit.WattPerSquareMetre
+		if rec.has(logWindBearing) {
+			o.WindDir = unit.Angle(rec.float(logWindBearing)) * unit.Degree
+		}
+
+		if rec.err != nil {
+			return rec.err
+		}
+
+		return fn(o)
+	})
+}
+
+// scanLines calls fn for each non-empty line of r, annotating any error with the line number.
+func scanLines(r io.Reader, fn func(line string) error) error {
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimPrefix(strings.TrimSpace(sc.Text()), "\ufeff")
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,112 @@
+package cumulus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLog(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		opts func(o *Options)
+	}{
+		{
+			name: "comma separated",
+			line: "18/10/08,16:05,8.4,84,5.8,24.2,33.0,261,0.0,1.0,999.7,588.4,20.3,57,36.0,3.6,8.4,1,420,0.12,560.5,6.1,0,0.0,270",
+		},
+		{
+			name: "semicolon separated with decimal comma",
+			line: "18-10-08;16:05;8,4;84;5,8;24,2;33,0;261;0,0;1,0;999,7;588,4;20,3;57;36,0;3,6;8,4;1;420;0,12;560,5;6,1;0;0,0;270",
+		},
+		{
+			name: "us units",
+			line: "18/10/08,16:05,47.12,84,42.4,15.04,20.51,261,0.0,0.03937,29.521,23.1653,68.54,57,22.4,38.5,47.1,1,420,0.005,22.1,43.0,0,0.0,270",
+			opts: func(o *Options) { o.Units = units.US },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewOptions()
+			opts.Location = time.UTC
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+
+			var got []model.Observation
+			err := ReadLog(strings.NewReader(tt.line+"\n"), opts, func(o model.Observation) error {
+				got = append(got, o)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+
+			o := got[0]
+			assert.Equal(t, time.Date(2008, 10, 18, 16, 5, 0, 0, time.UTC), o.Timestamp)
+			assert.InDelta(t, 8.4, o.TempOutdoor.Celsius(), 0.01)
+			assert.Equal(t, 84, o.HumidityOutdoor)
+			assert.InDelta(t, 24.2, o.WindSpeed.KilometersPerHour(), 0.1)
+			assert.InDelta(t, 33.0, o.WindGust.KilometersPerHour(), 0.1)
+			assert.InDelta(t, 270, o.WindDir.Degrees(), 0.01)
+			assert.InDelta(t, 1.0, o.DailyRain.Millimeters(), 0.01)
+			assert.InDelta(t, 999.7, o.BarometricRel.Hectopascals(), 0.1)
+			assert.Equal(t, o.BarometricRel, o.BarometricAbs, "sea level pressure only")
+			assert.InDelta(t, 588.4, o.TotalRain.Millimeters(), 0.1)
+			assert.InDelta(t, 20.3, o.TempIndoor.Celsius(), 0.01)
+			assert.Equal(t, 57, o.HumidityIndoor)
+			assert.Equal(t, 1, o.UltravioletIndex)
+			assert.InDelta(t, 420, o.SolarRadiation.WattsPerSquareMetre(), 0.01)
+		})
+	}
+
+	t.Run("too few fields", func(t *testing.T) {
+		err := ReadLog(strings.NewReader("18/10/08,16:05,8.4\n"), NewOptions(), func(o model.Observation) error {
+			return nil
+		})
+		assert.EqualError(t, err, "line 1: expected at least 14 fields, got 3")
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		line := "18/10/08,16:05,x,84,5.8,24.2,33.0,261,0.0,1.0,999.7,588.4,20.3,57"
+		err := ReadLog(strings.NewReader(line), NewOptions(), func(o model.Observation) error {
+			return nil
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestReadDayfile(t *testing.T) {
+	const line = "18/10/08,44.0,270,14:28,7.8,14:41,10.9,12:00,998.4,12:06,999.8,16:01,2.4,13:10,11.6,9.2,146.6,37.4,14:38,62,15:00,95,05:10,1.2,6.8,10.9,12:00,12.3,12:10,3.1,06:00,1.0,13:00,3.6,14:41,8.4,12:00,4.1,06:00,261,9.1,0.0,610,12:30,4.2,12:45"
+
+	opts := NewOptions()
+	opts.Location = time.UTC
+
+	var got []model.DailySummary
+	err := ReadDayfile(strings.NewReader(line), opts, func(ds model.DailySummary) error {
+		got = append(got, ds)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	ds := got[0]
+	assert.Equal(t, time.Date(2008, 10, 18, 0, 0, 0, 0, time.UTC), ds.Date)
+	assert.InDelta(t, 44.0, ds.WindGustHi.KilometersPerHour(), 0.1)
+	assert.Equal(t, time.Date(2008, 10, 18, 14, 28, 0, 0, time.UTC), ds.WindGustHiTime)
+	assert.InDelta(t, 7.8, ds.TempLo.Celsius(), 0.01)
+	assert.InDelta(t, 10.9, ds.TempHi.Celsius(), 0.01)
+	assert.InDelta(t, 11.6, ds.Rain.Millimeters(), 0.01)
+	assert.InDelta(t, 146.6, ds.WindRun.Kilometers(), 0.01)
+	assert.Equal(t, 62, ds.HumidityLo)
+	assert.Equal(t, 95, ds.HumidityHi)
+	assert.Equal(t, 6*time.Hour+48*time.Minute, ds.SunshineHours.Round(time.Minute))
+	assert.InDelta(t, 261, ds.DominantWindDir.Degrees(), 0.01)
+	assert.InDelta(t, 9.1, ds.HeatingDegreeDays, 0.01)
+	assert.Equal(t, 4, ds.UltravioletIndexHi)
+}
@@ -0,0 +1,150 @@
+package cumulus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/units"
+)
+
+// Options specifies the format of the Cumulus data files, which depends
+// on the locale and unit settings of the Cumulus installation.
+type Options struct {
+	// FieldSeparator separates fields of each line. Detected from each line when zero.
+	FieldSeparator rune
+	// DecimalSeparator is used for decimal numbers. Detected from the field separator when zero.
+	DecimalSeparator rune
+	// Units specifies the units Cumulus was configured to record.
+	Units units.System
+	// Location is the time zone of the recorded dates and times.
+	Location *time.Location
+}
+
+func NewOptions() Options {
+	return Options{
+		Units:    units.Metric,
+		Location: time.Local,
+	}
+}
+
+// record is a single line of a Cumulus data file. The first error
+// encountered when reading fields is retained in err.
+type record struct {
+	fields  []string
+	decimal rune
+	err     error
+}
+
+func (o Options) newRecord(line string) *record {
+	sep := o.FieldSeparator
+	if sep == 0 {
+		if strings.ContainsRune(line, ';') {
+			sep = ';'
+		} else {
+			sep = ','
+		}
+	}
+
+	dec := o.DecimalSeparator
+	if dec == 0 {
+		if sep == ',' {
+			dec = '.'
+		} else {
+			dec = ','
+		}
+	}
+
+	fields := strings.Split(line, string(sep))
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	return &record{fields: fields, decimal: dec}
+}
+
+func (r *record) has(i int) bool { return i < len(r.fields) && r.fields[i] != "" }
+
+func (r *record) float(i int) float64 {
+	if r.err != nil {
+		return 0
+	}
+	if i >= len(r.fields) {
+		r.err = fmt.Errorf("missing field %d", i)
+		return 0
+	}
+
+	s := r.fields[i]
+	if r.decimal != '.' {
+		s = strings.ReplaceAll(s, string(r.decimal), ".")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		r.err = fmt.Errorf("field %d: %w", i, err)
+	}
+	return v
+}
+
+// optFloat returns the value of the optional field i, or zero if it is not present.
+func (r *record) optFloat(i int) float64 {
+	if !r.has(i) {
+		return 0
+	}
+	return r.float(i)
+}
+
+func (r *record) int(i int) int {
+	return int(r.float(i) + 0.5)
+}
+
+// date returns the date of field i, which is formatted as dd/mm/yy using
+// any of the date separators Cumulus supports.
+func (r *record) date(i int, loc *time.Location) time.Time {
+	if r.err != nil {
+		return time.Time{}
+	}
+	if i >= len(r.fields) {
+		r.err = fmt.Errorf("missing field %d", i)
+		return time.Time{}
+	}
+
+	s := strings.Map(func(c rune) rune {
+		if c == '-' || c == '.' || c == ' ' {
+			return '/'
+		}
+		return c
+	}, r.fields[i])
+
+	t, err := time.ParseInLocation("02/01/06", s, loc)
+	if err != nil {
+		r.err = fmt.Errorf("field %d: %w", i, err)
+	}
+	return t
+}
+
+// clock returns the time of day in field i, formatted as hh:mm, applied to date.
+func (r *record) clock(i int, date time.Time) time.Time {
+	if r.err != nil {
+		return time.Time{}
+	}
+	if i >= len(r.fields) {
+		r.err = fmt.Errorf("missing field %d", i)
+		return time.Time{}
+	}
+
+	t, err := time.Parse("15:04", r.fields[i])
+	if err != nil {
+		r.err = fmt.Errorf("field %d: %w", i, err)
+		return time.Time{}
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location())
+}
+
+// optClock returns the time of day of the optional field i, or the zero time if it is not present.
+func (r *record) optClock(i int, date time.Time) time.Time {
+	if !r.has(i) {
+		return time.Time{}
+	}
+	return r.clock(i, date)
+}
@@ -0,0 +1,94 @@
+package normals
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+// bomStatistics maps the statistic elements of the Bureau of Meteorology climate statistics
+// to the field of the normal assigned the value for each month.
+var bomStatistics = []struct {
+	element string
+	set     func(cn *model.ClimateNormal, v float64)
+}{
+	{"mean maximum temperature", func(cn *model.ClimateNormal, v float64) { cn.TempHiMean = unit.FromCelsius(v) }},
+	{"mean minimum temperature", func(cn *model.ClimateNormal, v float64) { cn.TempLoMean = unit.FromCelsius(v) }},
+	{"highest temperature", func(cn *model.ClimateNormal, v float64) { cn.TempHiRecord = unit.FromCelsius(v) }},
+	{"lowest temperature", func(cn *model.ClimateNormal, v float64) { cn.TempLoRecord = unit.FromCelsius(v) }},
+	{"mean rainfall", func(cn *model.ClimateNormal, v float64) { cn.Rain = unit.Length(v) * unit.Millimeter }},
+}
+
+// ReadBoM reads the monthly normals from the climate statistics of a site published by the
+// Australian Bureau of Meteorology as CSV, calling fn for each month.
+//
+// Each statistic is a line starting with the statistic element, such as
+// "Mean maximum temperature (Degrees C) for years 1991 to 2020", followed by the values
+// for January to December. Other lines are ignored, and only the first line of each
+// statistic is read when there are several periods.
+//
+// See http://www.bom.gov.au/climate/data/
+func ReadBoM(r io.Reader, fn func(cn model.ClimateNormal) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cr.TrimLeadingSpace = true
+
+	var (
+		months [12]model.ClimateNormal
+		read   = make(map[string]bool)
+	)
+	for i := range months {
+		months[i].Month = time.Month(i + 1)
+	}
+
+	for {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		for _, stat := range bomStatistics {
+			if !strings.HasPrefix(name, stat.element) || read[stat.element] {
+				continue
+			}
+			read[stat.element] = true
+
+			for i := range months {
+				if i+1 >= len(fields) || strings.TrimSpace(fields[i+1]) == "" {
+					continue
+				}
+				v, err := strconv.ParseFloat(strings.TrimSpace(fields[i+1]), 64)
+				if err != nil {
+					return fmt.Errorf("%s: %s: %w", stat.element, months[i].Month, err)
+				}
+				stat.set(&months[i], v)
+			}
+		}
+	}
+
+	if len(read) == 0 {
+		return errors.New("no climate statistics found")
+	}
+
+	for _, cn := range months {
+		if err := fn(withTempMean(cn)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Package normals reads climate normals, the long-term averages and extremes of each month
+// or day of the year, for importing into the weather database.
+package normals
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+// Columns of the CSV format, whose header names the columns present, in any order.
+const (
+	colMonth        = "month"
+	colDay          = "day"
+	colTempHiMean   = "temp_hi_mean_c"
+	colTempLoMean   = "temp_lo_mean_c"
+	colTempMean     = "temp_mean_c"
+	colTempHiRecord = "temp_hi_record_c"
+	colTempLoRecord = "temp_lo_record_c"
+	colRain         = "rain_mm"
+)
+
+// ReadCSV reads the normals from r, calling fn for each. The first line is a header naming the
+// columns, of which only month is required. A normal is for the whole month when the day is blank
+// or zero. Blank values are not available, and the mean temperature is the average of the mean
+// maximum and minimum temperatures when it is not available.
+func ReadCSV(r io.Reader, fn func(cn model.ClimateNormal) error) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("header: %w", err)
+	}
+
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case colMonth, colDay, colTempHiMean, colTempLoMean, colTempMean, colTempHiRecord, colTempLoRecord, colRain:
+			cols[name] = i
+		default:
+			return fmt.Errorf("header: unknown column %q", name)
+		}
+	}
+	if _, ok := cols[colMonth]; !ok {
+		return fmt.Errorf("header: missing column %q", colMonth)
+	}
+
+	for line := 2; ; line++ {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rec := record{fields: fields, cols: cols}
+		cn := model.ClimateNormal{
+			Month:        time.Month(rec.int(colMonth)),
+			Day:          rec.int(colDay),
+			TempHiMean:   rec.temp(colTempHiMean),
+			TempLoMean:   rec.temp(colTempLoMean),
+			TempMean:     rec.temp(colTempMean),
+			TempHiRecord: rec.temp(colTempHiRecord),
+			TempLoRecord: rec.temp(colTempLoRecord),
+			Rain:         unit.Length(rec.float(colRain)) * unit.Millimeter,
+		}
+		if rec.err != nil {
+			return fmt.Errorf("line %d: %w", line, rec.err)
+		}
+		if err := validate(cn); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := fn(withTempMean(cn)); err != nil {
+			return err
+		}
+	}
+}
+
+// validate returns an error when the month or day of cn is invalid, allowing for 29 February.
+func validate(cn model.ClimateNormal) error {
+	if cn.Month < time.January || cn.Month > time.December {
+		return fmt.Errorf("invalid month %d", cn.Month)
+	}
+	if days := time.Date(2000, cn.Month+1, 0, 0, 0, 0, 0, time.UTC).Day(); cn.Day < 0 || cn.Day > days {
+		return fmt.Errorf("invalid day %d of %s", cn.Day, cn.Month)
+	}
+	return nil
+}
+
+// withTempMean returns cn with the mean temperature, as the average of the mean maximum
+// and minimum temperatures, when it is not available.
+func withTempMean(cn model.ClimateNormal) model.ClimateNormal {
+	if cn.TempMean == 0 && cn.TempHiMean != 0 && cn.TempLoMean != 0 {
+		cn.TempMean = (cn.TempHiMean + cn.TempLoMean) / 2
+	}
+	return cn
+}
+
+// record is a single line of the CSV format. The first error
+// encountered when reading fields is retained in err.
+type record struct {
+	fields []string
+	cols   map[string]int
+	err    error
+}
+
+func (r *record) value(col string) string {
+	i, ok := r.cols[col]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+// float returns the value of col, or zero when it is blank.
+func (r *record) float(col string) float64 {
+	s := r.value(col)
+	if r.err != nil || s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		r.err = fmt.Errorf("%s: %w", col, err)
+	}
+	return v
+}
+
+func (r *record) int(col string) int {
+	s := r.value(col)
+	if r.err != nil || s == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		r.err = fmt.Errorf("%s: %w", col, err)
+	}
+	return v
+}
+
+// temp returns the temperature in degrees Celsius of col, or zero when it is blank.
+func (r *record) temp(col string) unit.Temperature {
+	if r.value(col) == "" {
+		return 0
+	}
+	return unit.FromCelsius(r.float(col))
+}
@@ -0,0 +1,89 @@
+package normals
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	const data = `month,day,temp_hi_mean_c,temp_lo_mean_c,temp_mean_c,rain_mm
+9,,17.1,6.9,,52.4
+9,15,16.8,6.7,11.9,1.7
+`
+	var got []model.ClimateNormal
+	err := ReadCSV(strings.NewReader(data), func(cn model.ClimateNormal) error {
+		got = append(got, cn)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, time.September, got[0].Month)
+	assert.Equal(t, 0, got[0].Day)
+	assert.InDelta(t, 17.1, got[0].TempHiMean.Celsius(), 1e-9)
+	assert.InDelta(t, 12.0, got[0].TempMean.Celsius(), 1e-9, "average of the mean maximum and minimum")
+	assert.InDelta(t, 52.4, got[0].Rain.Millimeters(), 1e-9)
+	assert.Zero(t, got[0].TempHiRecord, "not available")
+
+	assert.Equal(t, 15, got[1].Day)
+	assert.InDelta(t, 11.9, got[1].TempMean.Celsius(), 1e-9)
+}
+
+func TestReadCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown column", "month,temp_hi\n1,20\n"},
+		{"missing month", "day,rain_mm\n1,20\n"},
+		{"invalid month", "month\n13\n"},
+		{"invalid day", "month,day\n2,30\n"},
+		{"invalid value", "month,rain_mm\n1,x\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReadCSV(strings.NewReader(tt.data), func(cn model.ClimateNormal) error { return nil })
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReadBoM(t *testing.T) {
+	const data = `Site name:,HOBART (ELLERSLIE ROAD),,,,,,,,,,,,,,,
+Site number:,094029,,,,,,,,,,,,,,,
+Statistic Element,January,February,March,April,May,June,July,August,September,October,November,December,Annual,Number of Years,Start Year,End Year
+Mean maximum temperature (Degrees C) for years 1991 to 2020 ,22.7,22.6,21.0,18.3,15.7,13.2,12.8,13.9,15.9,17.8,19.6,21.2,17.9,30,1991,2020
+Mean maximum temperature (Degrees C) for years 1882 to 2024 ,21.9,21.7,20.3,17.7,15.0,12.6,12.2,13.2,15.2,17.1,18.8,20.4,17.2,142,1882,2024
+Highest temperature (Degrees C) for years 1882 to 2024 ,41.8,40.1,37.3,30.6,25.5,20.6,21.0,24.5,31.0,34.6,36.8,40.8,41.8,142,1882,2024
+Mean minimum temperature (Degrees C) for years 1991 to 2020 ,12.4,12.5,11.2,9.3,7.5,5.6,5.0,5.4,6.8,8.0,9.6,11.0,8.7,30,1991,2020
+Lowest temperature (Degrees C) for years 1882 to 2024 ,4.5,3.4,1.8,0.8,-1.6,-2.8,-2.8,-1.8,-0.8,0.0,1.4,3.3,-2.8,142,1882,2024
+Mean rainfall (mm) for years 1882 to 2024 ,47.6,39.7,45.0,51.7,45.1,53.0,52.4,51.6,51.8,61.0,54.0,56.8,561.5,142,1882,2024
+Mean number of days of rain >= 1 mm for years 1882 to 2024 ,7.3,6.1,7.6,8.7,9.3,9.7,10.5,11.1,11.0,12.1,10.6,9.8,113.8,142,1882,2024
+`
+	var got []model.ClimateNormal
+	err := ReadBoM(strings.NewReader(data), func(cn model.ClimateNormal) error {
+		got = append(got, cn)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 12)
+
+	sep := got[8]
+	assert.Equal(t, time.September, sep.Month)
+	assert.Equal(t, 0, sep.Day)
+	assert.InDelta(t, 15.9, sep.TempHiMean.Celsius(), 1e-9, "first period")
+	assert.InDelta(t, 6.8, sep.TempLoMean.Celsius(), 1e-9)
+	assert.InDelta(t, 11.35, sep.TempMean.Celsius(), 1e-9)
+	assert.InDelta(t, 31.0, sep.TempHiRecord.Celsius(), 1e-9)
+	assert.InDelta(t, -0.8, sep.TempLoRecord.Celsius(), 1e-9)
+	assert.InDelta(t, 51.8, sep.Rain.Millimeters(), 1e-9)
+	assert.InDelta(t, 0.0, got[9].TempLoRecord.Celsius(), 1e-9, "zero degrees is available")
+
+	err = ReadBoM(strings.NewReader("a,b,c\n"), func(cn model.ClimateNormal) error { return nil })
+	assert.Error(t, err)
+}
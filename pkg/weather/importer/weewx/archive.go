@@ -0,0 +1,154 @@
+// Package weewx reads the archive table of a WeeWX SQLite database, for importing
+// historical data into the weather database.
+//
+// See https://weewx.com/docs/customizing.htm#units
+package weewx
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Unit systems, as stored in the usUnits column of each archive record.
+const (
+	unitSystemUS       = 0x01
+	unitSystemMetric   = 0x10
+	unitSystemMetricWX = 0x11
+)
+
+var unitSystems = map[int]units.System{
+	unitSystemUS:       units.US,
+	unitSystemMetric:   {Temperature: units.Celsius, Speed: units.KilometersPerHour, Pressure: units.Millibar, Rain: units.Centimeters},
+	unitSystemMetricWX: {Temperature: units.Celsius, Speed: units.MetersPerSecond, Pressure: units.Millibar, Rain: units.Millimeters},
+}
+
+type record struct {
+	DateTime    int64           `gorm:"column:dateTime"`
+	UsUnits     int             `gorm:"column:usUnits"`
+	Barometer   sql.NullFloat64 `gorm:"column:barometer"`
+	Pressure    sql.NullFloat64 `gorm:"column:pressure"`
+	InTemp      sql.NullFloat64 `gorm:"column:inTemp"`
+	OutTemp     sql.NullFloat64 `gorm:"column:outTemp"`
+	InHumidity  sql.NullFloat64 `gorm:"column:inHumidity"`
+	OutHumidity sql.NullFloat64 `gorm:"column:outHumidity"`
+	WindSpeed   sql.NullFloat64 `gorm:"column:windSpeed"`
+	WindDir     sql.NullFloat64 `gorm:"column:windDir"`
+	WindGust    sql.NullFloat64 `gorm:"column:windGust"`
+	RainRate    sql.NullFloat64 `gorm:"column:rainRate"`
+	Rain        sql.NullFloat64 `gorm:"column:rain"`
+	Radiation   sql.NullFloat64 `gorm:"column:radiation"`
+	UV          sql.NullFloat64 `gorm:"column:UV"`
+}
+
+type Archive struct {
+	db *gorm.DB
+}
+
+// Open opens the WeeWX SQLite database at path for reading.
+func Open(path string) (*Archive, error) {
+	db, err := gorm.Open(sqlite.Open("file:"+path+"?mode=ro"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db open: %w", err)
+	}
+	return &Archive{db: db}, nil
+}
+
+func (a *Archive) Close() error {
+	db, err := a.db.DB()
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// Read reads the archive records recorded at or after since, calling fn for each, in order.
+//
+// WeeWX records the rain which fell during each archive interval, which is
+// accumulated to produce the TotalRain counter and the DailyRain for the
+// day in loc.
+//
+// A column is NULL when WeeWX has no value, such as when a sensor is not present or contact
+// with it was lost. A record without an outside temperature, humidity or barometer is skipped,
+// as these cannot be zero, and would distort the extremes and averages of the reports. Other
+// NULL columns are read as zero, which is also the value of an observation without the sensor,
+// except the station pressure, for which the barometer is used.
+func (a *Archive) Read(since time.Time, loc *time.Location, fn func(o model.Observation) error) error {
+	rows, err := a.db.Table("archive").
+		Where("dateTime >= ?", since.Unix()).
+		Order("dateTime").
+		Rows()
+	if err != nil {
+		return fmt.Errorf("query archive: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var (
+		total, daily unit.Length
+		day          time.Time
+	)
+
+	for rows.Next() {
+		var rec record
+		if err := a.db.ScanRows(rows, &rec); err != nil {
+			return fmt.Errorf("scan archive: %w", err)
+		}
+
+		u, ok := unitSystems[rec.UsUnits]
+		if !ok {
+			return fmt.Errorf("record %d: unsupported unit system %#x", rec.DateTime, rec.UsUnits)
+		}
+
+		ts := time.Unix(rec.DateTime, 0).In(loc)
+		if y, m, d := ts.Date(); !day.Equal(time.Date(y, m, d, 0, 0, 0, 0, loc)) {
+			day = time.Date(y, m, d, 0, 0, 0, 0, loc)
+			daily = 0
+		}
+
+		rain := u.Rain.From(rec.Rain.Float64)
+		total += rain
+		daily += rain
+
+		// the rain is accumulated regardless, so the counter is not short of it
+		if !rec.OutTemp.Valid || !rec.OutHumidity.Valid || !rec.Barometer.Valid {
+			continue
+		}
+
+		o := model.Observation{
+			Timestamp:        ts.UTC(),
+			BarometricAbs:    u.Pressure.From(rec.Pressure.Float64),
+			BarometricRel:    u.Pressure.From(rec.Barometer.Float64),
+			DailyRain:        daily,
+			TotalRain:        total,
+			RainRatePerHour:  u.Rain.From(rec.RainRate.Float64),
+			HumidityOutdoor:  int(rec.OutHumidity.Float64 + 0.5),
+			HumidityIndoor:   int(rec.InHumidity.Float64 + 0.5),
+			WindDir:          unit.Angle(rec.WindDir.Float64) * unit.Degree,
+			WindGust:         u.Speed.From(rec.WindGust.Float64),
+			WindSpeed:        u.Speed.From(rec.WindSpeed.Float64),
+			SolarRadiation:   xunit.Irradiance(rec.Radiation.Float64) * xunit.WattPerSquareMetre,
+			TempOutdoor:      u.Temperature.From(rec.OutTemp.Float64),
+			TempIndoor:       u.Temperature.From(rec.InTemp.Float64),
+			UltravioletIndex: int(rec.UV.Float64 + 0.5),
+		}
+		if !rec.Pressure.Valid {
+			o.BarometricAbs = o.BarometricRel
+		}
+
+		if err := fn(o); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,93 @@
+package weewx
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func mustCreateArchive(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "weewx.sdb")
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+
+	stmts := []string{
+		`CREATE TABLE archive (dateTime INTEGER NOT NULL PRIMARY KEY, usUnits INTEGER NOT NULL, interval INTEGER NOT NULL,
+			barometer REAL, pressure REAL, altimeter REAL, inTemp REAL, outTemp REAL, inHumidity REAL, outHumidity REAL,
+			windSpeed REAL, windDir REAL, windGust REAL, windGustDir REAL, rainRate REAL, rain REAL, dewpoint REAL,
+			windchill REAL, heatindex REAL, ET REAL, radiation REAL, UV REAL)`,
+		// 2021-07-01 23:55 UTC, US units
+		`INSERT INTO archive (dateTime, usUnits, interval, barometer, pressure, outTemp, outHumidity, windSpeed, windDir, windGust, rainRate, rain, radiation, UV)
+			VALUES (1625183700, 1, 5, 30.033, 29.283, 56.7, 74, 5.4, 344, 6.9, 0.1, 0.1, 309.27, 3)`,
+		// 2021-07-02 00:00 UTC, METRIC units
+		`INSERT INTO archive (dateTime, usUnits, interval, barometer, outTemp, outHumidity, windSpeed, rain)
+			VALUES (1625184000, 16, 5, 1017.0, 13.7, 75, 8.7, 0.2)`,
+		// 2021-07-02 00:05 UTC, METRICWX units, without the outside temperature
+		`INSERT INTO archive (dateTime, usUnits, interval, barometer, outHumidity, windSpeed, rain)
+			VALUES (1625184300, 17, 5, 1017.0, 75, 2.4, 0.5)`,
+		// 2021-07-02 00:10 UTC, METRICWX units
+		`INSERT INTO archive (dateTime, usUnits, interval, barometer, outTemp, outHumidity, windSpeed, rain)
+			VALUES (1625184600, 17, 5, 1017.0, 13.7, 75, 2.4, 0.5)`,
+	}
+	for _, stmt := range stmts {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	sdb, _ := db.DB()
+	_ = sdb.Close()
+
+	return path
+}
+
+func TestArchive_Read(t *testing.T) {
+	ar, err := Open(mustCreateArchive(t))
+	require.NoError(t, err)
+	defer func() { _ = ar.Close() }()
+
+	var got []model.Observation
+	err = ar.Read(time.Time{}, time.UTC, func(o model.Observation) error {
+		got = append(got, o)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	us := got[0]
+	assert.Equal(t, time.Date(2021, 7, 1, 23, 55, 0, 0, time.UTC), us.Timestamp)
+	assert.InDelta(t, 1017.0, us.BarometricRel.Hectopascals(), 0.1)
+	assert.InDelta(t, 991.6, us.BarometricAbs.Hectopascals(), 0.1, "station pressure")
+	assert.InDelta(t, 13.7, us.TempOutdoor.Celsius(), 0.1)
+	assert.Equal(t, 74, us.HumidityOutdoor)
+	assert.InDelta(t, 8.7, us.WindSpeed.KilometersPerHour(), 0.1)
+	assert.InDelta(t, 2.54, us.DailyRain.Millimeters(), 0.01)
+	assert.Equal(t, 3, us.UltravioletIndex)
+
+	metric := got[1]
+	assert.InDelta(t, 8.7, metric.WindSpeed.KilometersPerHour(), 0.1)
+	assert.InDelta(t, 1017.0, metric.BarometricAbs.Hectopascals(), 0.1, "barometer without the station pressure")
+	assert.InDelta(t, 2.0, metric.DailyRain.Millimeters(), 0.01, "daily rain resets at midnight")
+	assert.InDelta(t, 4.54, metric.TotalRain.Millimeters(), 0.01)
+
+	metricWX := got[2]
+	assert.Equal(t, time.Date(2021, 7, 2, 0, 10, 0, 0, time.UTC), metricWX.Timestamp, "record without the outside temperature skipped")
+	assert.InDelta(t, 8.64, metricWX.WindSpeed.KilometersPerHour(), 0.1)
+	assert.Zero(t, metricWX.SolarRadiation, "NULL radiation")
+	assert.InDelta(t, 3.0, metricWX.DailyRain.Millimeters(), 0.01)
+	assert.InDelta(t, 5.54, metricWX.TotalRain.Millimeters(), 0.01)
+
+	t.Run("since", func(t *testing.T) {
+		var n int
+		err = ar.Read(time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC), time.UTC, func(o model.Observation) error {
+			n++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, n, "including the record at since")
+	})
+}
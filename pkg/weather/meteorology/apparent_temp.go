@@ -6,7 +6,8 @@ import (
 	"github.com/martinlindhe/unit"
 )
 
-// ApparentTemperature calculates the apparent temperature using temp, wind and rh.
+// ApparentTemperature calculates the apparent temperature using temp, wind and rh,
+// per the approximation of Steadman's model used by the Bureau of Meteorology, which excludes radiation.
 // See http://www.bom.gov.au/info/thermal_stress/#atapproximation
 func ApparentTemperature(temp unit.Temperature, wind unit.Speed, rh int) unit.Temperature {
 	tempC := temp.Celsius()
@@ -0,0 +1,83 @@
+package meteorology
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/martinlindhe/unit"
+)
+
+// DegreeDayMethod is the method used to estimate degree days from the daily
+// minimum and maximum temperatures.
+type DegreeDayMethod int
+
+const (
+	// DegreeDayMethodAveraging uses the difference between the mean of the minimum and
+	// maximum temperatures and the base temperature.
+	DegreeDayMethodAveraging DegreeDayMethod = iota
+	// DegreeDayMethodSingleSine assumes the temperature follows a sine curve between
+	// the minimum and maximum, which accounts for days which cross the base temperature.
+	DegreeDayMethodSingleSine
+)
+
+func (m *DegreeDayMethod) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "averaging":
+		*m = DegreeDayMethodAveraging
+	case "single-sine":
+		*m = DegreeDayMethodSingleSine
+	default:
+		return fmt.Errorf("invalid degree day method: %s", string(text))
+	}
+	return nil
+}
+
+// HeatingDegreeDays returns the degree days, in °C, below base for a day with the
+// temperatures tmin and tmax.
+func HeatingDegreeDays(tmin, tmax, base unit.Temperature, m DegreeDayMethod) float64 {
+	lo, hi, b := tmin.Celsius(), tmax.Celsius(), base.Celsius()
+	if m == DegreeDayMethodSingleSine {
+		// the degrees below base are the degrees above base, less the difference of the mean from base
+		return b - (lo+hi)/2 + singleSineDegreeDays(lo, hi, b)
+	}
+	return math.Max(0, b-(lo+hi)/2)
+}
+
+// CoolingDegreeDays returns the degree days, in °C, above base for a day with the
+// temperatures tmin and tmax.
+func CoolingDegreeDays(tmin, tmax, base unit.Temperature, m DegreeDayMethod) float64 {
+	lo, hi, b := tmin.Celsius(), tmax.Celsius(), base.Celsius()
+	if m == DegreeDayMethodSingleSine {
+		return singleSineDegreeDays(lo, hi, b)
+	}
+	return math.Max(0, (lo+hi)/2-b)
+}
+
+// GrowingDegreeDays returns the growing degree days, in °C, for a day with the temperatures
+// tmin and tmax, where plants develop above base and no faster above cutoff.
+//
+// The averaging method limits both temperatures to cutoff, and the single sine method uses
+// a horizontal cutoff, as described by the UC IPM degree day models, https://ipm.ucanr.edu/WEATHER/ddconcepts.html.
+func GrowingDegreeDays(tmin, tmax, base, cutoff unit.Temperature, m DegreeDayMethod) float64 {
+	lo, hi, b, c := tmin.Celsius(), tmax.Celsius(), base.Celsius(), cutoff.Celsius()
+	if m == DegreeDayMethodSingleSine {
+		return singleSineDegreeDays(lo, hi, b) - singleSineDegreeDays(lo, hi, c)
+	}
+	return math.Max(0, (math.Min(lo, c)+math.Min(hi, c))/2-b)
+}
+
+// singleSineDegreeDays returns the degree days above base, for a sine curve
+// between lo and hi, using the method of Baskerville and Emin, https://doi.org/10.2307/1934235.
+func singleSineDegreeDays(lo, hi, base float64) float64 {
+	mean := (lo + hi) / 2
+	switch {
+	case lo >= base:
+		return mean - base
+	case hi <= base:
+		return 0
+	}
+
+	amp := (hi - lo) / 2
+	theta := math.Asin((base - mean) / amp)
+	return ((mean-base)*(math.Pi/2-theta) + amp*math.Cos(theta)) / math.Pi
+}
@@ -0,0 +1,39 @@
+package meteorology
+
+import (
+	"testing"
+
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeatingDegreeDays(t *testing.T) {
+	base := unit.FromCelsius(18)
+	assert.InDelta(t, 8, HeatingDegreeDays(unit.FromCelsius(5), unit.FromCelsius(15), base, DegreeDayMethodAveraging), 0.001)
+	assert.Zero(t, HeatingDegreeDays(unit.FromCelsius(15), unit.FromCelsius(25), base, DegreeDayMethodAveraging))
+
+	assert.InDelta(t, 8, HeatingDegreeDays(unit.FromCelsius(5), unit.FromCelsius(15), base, DegreeDayMethodSingleSine), 0.001)
+	assert.InDelta(t, 0.72, HeatingDegreeDays(unit.FromCelsius(15), unit.FromCelsius(25), base, DegreeDayMethodSingleSine), 0.01,
+		"the day is below base in the morning")
+}
+
+func TestCoolingDegreeDays(t *testing.T) {
+	base := unit.FromCelsius(18)
+	assert.InDelta(t, 4, CoolingDegreeDays(unit.FromCelsius(17), unit.FromCelsius(27), base, DegreeDayMethodAveraging), 0.001)
+	assert.Zero(t, CoolingDegreeDays(unit.FromCelsius(5), unit.FromCelsius(25), base, DegreeDayMethodAveraging))
+
+	assert.InDelta(t, 4.14, CoolingDegreeDays(unit.FromCelsius(17), unit.FromCelsius(27), base, DegreeDayMethodSingleSine), 0.01)
+	assert.InDelta(t, 1.83, CoolingDegreeDays(unit.FromCelsius(5), unit.FromCelsius(25), base, DegreeDayMethodSingleSine), 0.01)
+	assert.Zero(t, CoolingDegreeDays(unit.FromCelsius(5), unit.FromCelsius(15), base, DegreeDayMethodSingleSine))
+}
+
+func TestGrowingDegreeDays(t *testing.T) {
+	base, cutoff := unit.FromCelsius(10), unit.FromCelsius(30)
+	assert.InDelta(t, 10, GrowingDegreeDays(unit.FromCelsius(15), unit.FromCelsius(25), base, cutoff, DegreeDayMethodAveraging), 0.001)
+	assert.InDelta(t, 12.5, GrowingDegreeDays(unit.FromCelsius(15), unit.FromCelsius(35), base, cutoff, DegreeDayMethodAveraging), 0.001)
+	assert.Zero(t, GrowingDegreeDays(unit.FromCelsius(0), unit.FromCelsius(10), base, cutoff, DegreeDayMethodAveraging))
+
+	assert.InDelta(t, 10, GrowingDegreeDays(unit.FromCelsius(15), unit.FromCelsius(25), base, cutoff, DegreeDayMethodSingleSine), 0.001)
+	assert.Less(t, GrowingDegreeDays(unit.FromCelsius(15), unit.FromCelsius(35), base, cutoff, DegreeDayMethodSingleSine), 15.0)
+	assert.Greater(t, GrowingDegreeDays(unit.FromCelsius(0), unit.FromCelsius(20), base, cutoff, DegreeDayMethodSingleSine), 0.0)
+}
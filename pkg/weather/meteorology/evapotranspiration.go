@@ -0,0 +1,182 @@
+package meteorology
+
+import (
+	"math"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+// Site describes the location of a weather station, as required to calculate
+// the radiation received at the surface.
+type Site struct {
+	Latitude         float64 // Latitude in degrees, positive north of the equator
+	Longitude        float64 // Longitude in degrees, positive east of Greenwich
+	Altitude         float64 // Altitude in metres above sea level
+	AnemometerHeight float64 // AnemometerHeight is the height of the wind measurement in metres above the ground
+}
+
+const (
+	solarConstant   = 0.0820   // MJ m-2 min-1
+	stefanBoltzmann = 4.903e-9 // MJ K-4 m-2 day-1
+)
+
+// saturationVapourPressure returns the saturation vapour pressure, in kPa, at temperature t °C.
+// See FAO-56, equation 11.
+func saturationVapourPressure(t float64) float64 {
+	return 0.6108 * math.Exp(17.27*t/(t+237.3))
+}
+
+// saturationVapourPressureSlope returns the slope of the saturation vapour pressure curve, in kPa °C-1.
+// See FAO-56, equation 13.
+func saturationVapourPressureSlope(t float64) float64 {
+	return 4098 * saturationVapourPressure(t) / math.Pow(t+237.3, 2)
+}
+
+// psychrometricConstant returns the psychrometric constant, in kPa °C-1, at altitude z metres.
+// See FAO-56, equations 7 and 8.
+func psychrometricConstant(z float64) float64 {
+	p := 101.3 * math.Pow((293-0.0065*z)/293, 5.26)
+	return 0.665e-3 * p
+}
+
+// windSpeedAt2m adjusts the wind speed u, measured at height z metres, to the standard height of 2 metres.
+// See FAO-56, equation 47.
+func windSpeedAt2m(u unit.Speed, z float64) float64 {
+	mps := u.MetersPerSecond()
+	if z <= 0 || z == 2 {
+		return mps
+	}
+	return mps * 4.87 / math.Log(67.8*z-5.42)
+}
+
+// inverseRelativeDistance returns the inverse relative distance of the Earth from the Sun for day of the year j.
+// See FAO-56, equation 23.
+func inverseRelativeDistance(j int) float64 {
+	return 1 + 0.033*math.Cos(2*math.Pi/365*float64(j))
+}
+
+// solarDeclination returns the solar declination, in radians, for day of the year j.
+// See FAO-56, equation 24.
+func solarDeclination(j int) float64 {
+	return 0.409 * math.Sin(2*math.Pi/365*float64(j)-1.39)
+}
+
+// solarTimeAngle returns the solar time angle, in radians, at longitude lon degrees and time t.
+// See FAO-56, equations 31 through 33.
+func solarTimeAngle(lon float64, t time.Time) float64 {
+	t = t.UTC()
+	j := t.YearDay()
+
+	// seasonal correction for solar time, in hours
+	b := 2 * math.Pi * float64(j-81) / 364
+	sc := 0.1645*math.Sin(2*b) - 0.1255*math.Cos(b) - 0.025*math.Sin(b)
+
+	hours := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+	return math.Pi / 12 * (hours + lon/15 + sc - 12)
+}
+
+// DailyExtraterrestrialRadiation returns the radiation received at the top of the atmosphere,
+// in MJ m-2 day-1, at latitude lat degrees on the date of t.
+// See FAO-56, equation 21.
+func DailyExtraterrestrialRadiation(lat float64, t time.Time) float64 {
+	j := t.YearDay()
+	phi := lat * math.Pi / 180
+	dr := inverseRelativeDistance(j)
+	delta := solarDeclination(j)
+	ws := math.Acos(math.Max(-1, math.Min(1, -math.Tan(phi)*math.Tan(delta))))
+
+	return 24 * 60 / math.Pi * solarConstant * dr * (ws*math.Sin(phi)*math.Sin(delta) + math.Cos(phi)*math.Cos(delta)*math.Sin(ws))
+}
+
+// ExtraterrestrialRadiation returns the radiation received at the top of the atmosphere, in MJ m-2,
+// at site s during the period starting at t, of duration d, which must not exceed one hour.
+// See FAO-56, equations 28 through 33.
+func ExtraterrestrialRadiation(s Site, t time.Time, d time.Duration) float64 {
+	mid := t.Add(d / 2).UTC()
+	j := mid.YearDay()
+	phi := s.Latitude * math.Pi / 180
+	dr := inverseRelativeDistance(j)
+	delta := solarDeclination(j)
+
+	// solar time angle at the midpoint of the period
+	w := solarTimeAngle(s.Longitude, mid)
+	half := math.Pi * d.Hours() / 24
+	w1, w2 := w-half, w+half
+
+	// limit the period to the hours of daylight
+	ws := math.Acos(math.Max(-1, math.Min(1, -math.Tan(phi)*math.Tan(delta))))
+	w1 = math.Max(-ws, math.Min(ws, w1))
+	w2 = math.Max(-ws, math.Min(ws, w2))
+
+	ra := 12 * 60 / math.Pi * solarConstant * dr * ((w2-w1)*math.Sin(phi)*math.Sin(delta) + math.Cos(phi)*math.Cos(delta)*(math.Sin(w2)-math.Sin(w1)))
+	return math.Max(0, ra)
+}
+
+// netRadiation returns the net radiation, in MJ m-2, for the measured solar radiation rs, clear sky
+// radiation rso and longwave radiation factor sigmaT4, for actual vapour pressure ea.
+// See FAO-56, equations 38 through 40.
+func netRadiation(rs, rso, sigmaT4, ea float64) float64 {
+	ratio := 0.5
+	if rso > 0 {
+		ratio = math.Max(0.3, math.Min(1.0, rs/rso))
+	}
+	rns := (1 - 0.23) * rs
+	rnl := sigmaT4 * (0.34 - 0.14*math.Sqrt(ea)) * (1.35*ratio - 0.35)
+	return rns - rnl
+}
+
+// HourlyReferenceEvapotranspiration calculates the FAO-56 Penman-Monteith reference evapotranspiration,
+// ET0, for the period starting at t, of duration d, using the mean temperature, relative humidity,
+// wind speed and solar radiation measured during the period. d must not exceed one hour.
+// See FAO-56, equation 53.
+func HourlyReferenceEvapotranspiration(s Site, t time.Time, d time.Duration, temp unit.Temperature, rh int, wind unit.Speed, solar xunit.Irradiance) unit.Length {
+	hours := d.Hours()
+	if hours <= 0 {
+		return 0
+	}
+
+	T := temp.Celsius()
+	es := saturationVapourPressure(T)
+	ea := es * float64(rh) / 100
+	delta := saturationVapourPressureSlope(T)
+	gamma := psychrometricConstant(s.Altitude)
+	u2 := windSpeedAt2m(wind, s.AnemometerHeight)
+
+	// radiation during the period in MJ m-2
+	rs := solar.WattsPerSquareMetre() * d.Seconds() / 1e6
+	rso := (0.75 + 2e-5*s.Altitude) * ExtraterrestrialRadiation(s, t, d)
+	rn := netRadiation(rs, rso, stefanBoltzmann/24*hours*math.Pow(T+273.16, 4), ea)
+
+	// soil heat flux density
+	g := 0.1 * rn
+	if rso <= 0 {
+		g = 0.5 * rn
+	}
+
+	et := (0.408*delta*(rn-g) + gamma*37*hours/(T+273)*u2*(es-ea)) / (delta + gamma*(1+0.34*u2))
+	return unit.Length(math.Max(0, et)) * unit.Millimeter
+}
+
+// DailyReferenceEvapotranspiration calculates the FAO-56 Penman-Monteith reference evapotranspiration,
+// ET0, for the date of t, using the minimum and maximum temperatures, and the mean relative humidity,
+// wind speed and solar radiation for the day.
+// See FAO-56, equation 6.
+func DailyReferenceEvapotranspiration(s Site, t time.Time, tmin, tmax unit.Temperature, rh int, wind unit.Speed, solar xunit.Irradiance) unit.Length {
+	Tmin, Tmax := tmin.Celsius(), tmax.Celsius()
+	T := (Tmin + Tmax) / 2
+	es := (saturationVapourPressure(Tmin) + saturationVapourPressure(Tmax)) / 2
+	ea := es * float64(rh) / 100
+	delta := saturationVapourPressureSlope(T)
+	gamma := psychrometricConstant(s.Altitude)
+	u2 := windSpeedAt2m(wind, s.AnemometerHeight)
+
+	rs := solar.WattsPerSquareMetre() * 0.0864
+	rso := (0.75 + 2e-5*s.Altitude) * DailyExtraterrestrialRadiation(s.Latitude, t)
+	sigmaT4 := stefanBoltzmann * (math.Pow(Tmax+273.16, 4) + math.Pow(Tmin+273.16, 4)) / 2
+	rn := netRadiation(rs, rso, sigmaT4, ea)
+
+	et := (0.408*delta*rn + gamma*900/(T+273)*u2*(es-ea)) / (delta + gamma*(1+0.34*u2))
+	return unit.Length(math.Max(0, et)) * unit.Millimeter
+}
@@ -0,0 +1,46 @@
+package meteorology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+// Examples from FAO Irrigation and drainage paper 56, http://www.fao.org/3/x0490e/x0490e00.htm
+
+func TestDailyReferenceEvapotranspiration(t *testing.T) {
+	// Example 18, Brussels on 6 July
+	site := Site{Latitude: 50.8, Altitude: 100, AnemometerHeight: 10}
+	date := time.Date(2001, 7, 6, 0, 0, 0, 0, time.UTC)
+
+	got := DailyReferenceEvapotranspiration(site, date, unit.FromCelsius(12.3), unit.FromCelsius(21.5), 71, 10*unit.KilometersPerHour, 255.4)
+	assert.InDelta(t, 3.9, got.Millimeters(), 0.1)
+}
+
+func TestExtraterrestrialRadiation(t *testing.T) {
+	// Example 19, N'Diaye, Senegal on 2 October between 14:00 and 15:00 (UTC-1)
+	site := Site{Latitude: 16.217, Longitude: -16.25, Altitude: 8}
+	start := time.Date(2001, 10, 2, 15, 0, 0, 0, time.UTC)
+
+	assert.InDelta(t, 3.543, ExtraterrestrialRadiation(site, start, time.Hour), 0.02)
+	assert.InDelta(t, 0, ExtraterrestrialRadiation(site, start.Add(-12*time.Hour), time.Hour), 0.001)
+
+	// Example 8, 20°S on 3 September
+	assert.InDelta(t, 32.2, DailyExtraterrestrialRadiation(-20, time.Date(2001, 9, 3, 0, 0, 0, 0, time.UTC)), 0.1)
+}
+
+func TestHourlyReferenceEvapotranspiration(t *testing.T) {
+	// Example 19, N'Diaye, Senegal on 2 October between 14:00 and 15:00 (UTC-1)
+	site := Site{Latitude: 16.217, Longitude: -16.25, Altitude: 8, AnemometerHeight: 2}
+	start := time.Date(2001, 10, 2, 15, 0, 0, 0, time.UTC)
+	solar := xunit.Irradiance(2.450e6/3600) * xunit.WattPerSquareMetre
+
+	got := HourlyReferenceEvapotranspiration(site, start, time.Hour, unit.FromCelsius(38), 52, 3.3*unit.MetersPerSecond, solar)
+	assert.InDelta(t, 0.63, got.Millimeters(), 0.02)
+
+	half := HourlyReferenceEvapotranspiration(site, start, 30*time.Minute, unit.FromCelsius(38), 52, 3.3*unit.MetersPerSecond, solar)
+	assert.InDelta(t, got.Millimeters()/2, half.Millimeters(), 0.02)
+}
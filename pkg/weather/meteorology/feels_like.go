@@ -0,0 +1,65 @@
+package meteorology
+
+import (
+	"fmt"
+
+	"github.com/martinlindhe/unit"
+)
+
+// FeelsLikeLocale selects the indices which make up the feels like temperature,
+// per the convention of a national weather service.
+type FeelsLikeLocale int
+
+const (
+	// FeelsLikeAustralia uses the apparent temperature of the Bureau of Meteorology.
+	FeelsLikeAustralia FeelsLikeLocale = iota
+	// FeelsLikeUS uses the JAG/TI wind chill when cold and windy, and the heat index when hot,
+	// per the US National Weather Service.
+	FeelsLikeUS
+	// FeelsLikeCanada uses the JAG/TI wind chill when cold and windy, and the humidex when warm,
+	// per Environment Canada.
+	FeelsLikeCanada
+)
+
+func (l *FeelsLikeLocale) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "australia":
+		*l = FeelsLikeAustralia
+	case "us":
+		*l = FeelsLikeUS
+	case "canada":
+		*l = FeelsLikeCanada
+	default:
+		return fmt.Errorf("invalid feels like locale: %s", string(text))
+	}
+	return nil
+}
+
+// FeelsLike calculates the feels like temperature using temp, wind and rh,
+// per the convention of locale l.
+func FeelsLike(l FeelsLikeLocale, temp unit.Temperature, wind unit.Speed, rh int) unit.Temperature {
+	tempC := temp.Celsius()
+
+	switch l {
+	case FeelsLikeUS:
+		switch {
+		case tempC <= 10:
+			return JAGTIWindChill(temp, wind)
+		case temp.Fahrenheit() >= 80:
+			return HeatIndex(temp, rh)
+		}
+	case FeelsLikeCanada:
+		switch {
+		case tempC <= 10:
+			return JAGTIWindChill(temp, wind)
+		case tempC >= 20:
+			if h := Humidex(temp, rh); h > temp {
+				return h
+			}
+		}
+	default:
+		return ApparentTemperature(temp, wind, rh)
+	}
+
+	return temp
+}
@@ -0,0 +1,51 @@
+package meteorology
+
+import (
+	"testing"
+
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumidex(t *testing.T) {
+	// a dew point of 15°C
+	assert.InDelta(t, 34, Humidex(unit.FromCelsius(30), 42).Celsius(), 0.5)
+}
+
+func TestHeatIndex(t *testing.T) {
+	// values from the NWS heat index chart
+	assert.InDelta(t, 95, HeatIndex(unit.FromFahrenheit(90), 50).Fahrenheit(), 1)
+	assert.InDelta(t, 129, HeatIndex(unit.FromFahrenheit(100), 60).Fahrenheit(), 1)
+	assert.InDelta(t, 80, HeatIndex(unit.FromFahrenheit(80), 40).Fahrenheit(), 1)
+
+	// the air temperature in cool weather
+	for _, c := range []float64{-20, 0, 10, 26} {
+		assert.Equal(t, unit.FromCelsius(c), HeatIndex(unit.FromCelsius(c), 50), c)
+	}
+
+	// adjustments for low and high humidity
+	assert.InDelta(t, 89.4, HeatIndex(unit.FromFahrenheit(95), 10).Fahrenheit(), 0.5)
+	assert.InDelta(t, 94, HeatIndex(unit.FromFahrenheit(82), 95).Fahrenheit(), 1)
+}
+
+func TestWindChill(t *testing.T) {
+	// values from the Environment Canada wind chill chart
+	assert.InDelta(t, -18, JAGTIWindChill(unit.FromCelsius(-10), 20*unit.KilometersPerHour).Celsius(), 0.5)
+	assert.InDelta(t, 2.7, JAGTIWindChill(unit.FromCelsius(5), 10*unit.KilometersPerHour).Celsius(), 0.5)
+	assert.Equal(t, unit.FromCelsius(15), JAGTIWindChill(unit.FromCelsius(15), 30*unit.KilometersPerHour))
+	assert.Equal(t, unit.FromCelsius(5), JAGTIWindChill(unit.FromCelsius(5), 3*unit.KilometersPerHour))
+
+	assert.Less(t, WindChill(unit.FromCelsius(-10), 20*unit.KilometersPerHour, WindChillLegacy).Celsius(), -18.0)
+	assert.Equal(t, unit.FromCelsius(5), LegacyWindChill(unit.FromCelsius(5), unit.MetersPerSecond))
+}
+
+func TestFeelsLike(t *testing.T) {
+	cold, hot := unit.FromCelsius(0), unit.FromCelsius(32)
+	wind := 20 * unit.KilometersPerHour
+
+	assert.Equal(t, ApparentTemperature(cold, wind, 50), FeelsLike(FeelsLikeAustralia, cold, wind, 50))
+	assert.Equal(t, JAGTIWindChill(cold, wind), FeelsLike(FeelsLikeUS, cold, wind, 50))
+	assert.Equal(t, HeatIndex(hot, 50), FeelsLike(FeelsLikeUS, hot, wind, 50))
+	assert.Equal(t, Humidex(hot, 50), FeelsLike(FeelsLikeCanada, hot, wind, 50))
+	assert.Equal(t, unit.FromCelsius(15), FeelsLike(FeelsLikeCanada, unit.FromCelsius(15), wind, 50))
+}
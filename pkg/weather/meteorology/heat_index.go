@@ -1,38 +1,51 @@
 package meteorology
 
 import (
+	"math"
+
 	"github.com/martinlindhe/unit"
 )
 
-// HeatIndex calculates the heat index (HI) using temp and rh.
-// Based on https://en.wikipedia.org/wiki/Heat_index#Formula
+// HeatIndex calculates the heat index using temp and rh, using the algorithm of the US National Weather Service,
+// including the adjustments for low and high humidity. The heat index is not defined below 80°F,
+// where temp is returned.
+// See https://www.wpc.ncep.noaa.gov/html/heatindex_equation.shtml
 func HeatIndex(temp unit.Temperature, rh int) unit.Temperature {
-	tempC := temp.Celsius()
-
-	if tempC < 27 {
+	T := temp.Fahrenheit()
+	R := float64(rh)
+	if T < 80 {
 		return temp
 	}
 
-	T := tempC
-	R := float64(rh)
-
-	T2 := tempC * tempC
-	R2 := float64(rh * rh)
+	// the simple formula is used when the heat index is below 80°F
+	hi := 0.5 * (T + 61.0 + (T-68.0)*1.2 + R*0.094)
+	if (hi+T)/2 < 80 {
+		return unit.FromFahrenheit(hi)
+	}
 
-	// coefficients for °C
+	// Rothfusz regression, for °F
 	const (
-		c1 = -8.78469475556
-		c2 = 1.61139411
-		c3 = 2.33854883889
-		c4 = -0.14611605
-		c5 = -0.012308094
-		c6 = -0.0164248277778
-		c7 = 0.002211732
-		c8 = 0.00072546
-		c9 = -0.000003582
+		c1 = -42.379
+		c2 = 2.04901523
+		c3 = 10.14333127
+		c4 = -0.22475541
+		c5 = -0.00683783
+		c6 = -0.05481717
+		c7 = 0.00122874
+		c8 = 0.00085282
+		c9 = -0.00000199
 	)
 
-	resultC := c1 + c2*T + c3*R + c4*T*R + c5*T2 + c6*R2 + c7*T2*R + c8*T*R2 + c9*T2*R2
+	T2 := T * T
+	R2 := R * R
+	hi = c1 + c2*T + c3*R + c4*T*R + c5*T2 + c6*R2 + c7*T2*R + c8*T*R2 + c9*T2*R2
+
+	switch {
+	case R < 13 && T >= 80 && T <= 112:
+		hi -= (13 - R) / 4 * math.Sqrt((17-math.Abs(T-95))/17)
+	case R > 85 && T >= 80 && T <= 87:
+		hi += (R - 85) / 10 * ((87 - T) / 5)
+	}
 
-	return unit.FromCelsius(resultC)
+	return unit.FromFahrenheit(hi)
 }
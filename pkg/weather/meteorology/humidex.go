@@ -12,6 +12,6 @@ func Humidex(temp unit.Temperature, rh int) unit.Temperature {
 	dew := DewPoint(temp, rh)
 
 	tempAir := temp.Celsius()
-	H := tempAir + 5/9*(6.11*math.Exp(5417.7530*(1/273.16-1/(273.15+dew.Celsius())))-10)
+	H := tempAir + 5.0/9.0*(6.11*math.Exp(5417.7530*(1/273.16-1/(273.15+dew.Celsius())))-10)
 	return unit.FromCelsius(H)
 }
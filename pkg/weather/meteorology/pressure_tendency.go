@@ -0,0 +1,151 @@
+package meteorology
+
+import (
+	"math"
+
+	"github.com/martinlindhe/unit"
+)
+
+// PressureCharacteristic is the characteristic of the pressure tendency during the three hours
+// preceding an observation, per WMO code table 0200, numbered 0 through 8.
+type PressureCharacteristic int
+
+const (
+	// PressureCharacteristicNone indicates the characteristic is not available.
+	PressureCharacteristicNone PressureCharacteristic = -1
+
+	PressureRisingThenFalling PressureCharacteristic = 0 // increasing, then decreasing; the same or higher
+	PressureRisingThenSteady  PressureCharacteristic = 1 // increasing, then steady, or increasing more slowly; higher
+	PressureRising            PressureCharacteristic = 2 // increasing steadily or unsteadily; higher
+	PressureSteadyThenRising  PressureCharacteristic = 3 // decreasing or steady, then increasing, or increasing more rapidly; higher
+	PressureSteady            PressureCharacteristic = 4 // steady; the same
+	PressureFallingThenRising PressureCharacteristic = 5 // decreasing, then increasing; the same or lower
+	PressureFallingThenSteady PressureCharacteristic = 6 // decreasing, then steady, or decreasing more slowly; lower
+	PressureFalling           PressureCharacteristic = 7 // decreasing steadily or unsteadily; lower
+	PressureSteadyThenFalling PressureCharacteristic = 8 // steady or increasing, then decreasing, or decreasing more rapidly; lower
+)
+
+var pressureCharacteristics = []string{
+	"Increasing, then decreasing",
+	"Increasing, then steady; or increasing, then increasing more slowly",
+	"Increasing steadily or unsteadily",
+	"Decreasing or steady, then increasing; or increasing, then increasing more rapidly",
+	"Steady",
+	"Decreasing, then increasing",
+	"Decreasing, then steady; or decreasing, then decreasing more slowly",
+	"Decreasing steadily or unsteadily",
+	"Steady or increasing, then decreasing; or decreasing, then decreasing more rapidly",
+}
+
+func (c PressureCharacteristic) valid() bool {
+	return c >= 0 && int(c) < len(pressureCharacteristics)
+}
+
+// Code returns the code of the characteristic, 0 through 8, or -1 when it is not available.
+func (c PressureCharacteristic) Code() int {
+	if !c.valid() {
+		return -1
+	}
+	return int(c)
+}
+
+// String returns the description of the characteristic.
+func (c PressureCharacteristic) String() string {
+	if !c.valid() {
+		return ""
+	}
+	return pressureCharacteristics[c]
+}
+
+func (c PressureCharacteristic) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// PressureSteadyThreshold is the smallest change in pressure which is not considered steady.
+const PressureSteadyThreshold = 0.1 * unit.Hectopascal
+
+// PressureTendency returns the characteristic of the pressure tendency from the pressures at
+// the start, middle and end of the three hour period.
+func PressureTendency(start, mid, end unit.Pressure) PressureCharacteristic {
+	sign := func(d unit.Pressure) int {
+		switch {
+		case d >= PressureSteadyThreshold:
+			return 1
+		case d <= -PressureSteadyThreshold:
+			return -1
+		default:
+			return 0
+		}
+	}
+
+	first, second := mid-start, end-mid
+	s1, s2 := sign(first), sign(second)
+	// compare the rate of the second half to the first
+	faster, slower := second-first, first-second
+
+	switch sign(end - start) {
+	case 1:
+		switch {
+		case s1 > 0 && s2 < 0:
+			return PressureRisingThenFalling
+		case s1 > 0 && s2 == 0:
+			return PressureRisingThenSteady
+		case s1 <= 0 && s2 > 0:
+			return PressureSteadyThenRising
+		case s1 > 0 && s2 > 0 && faster >= PressureSteadyThreshold:
+			return PressureSteadyThenRising
+		case s1 > 0 && s2 > 0 && slower >= PressureSteadyThreshold:
+			return PressureRisingThenSteady
+		default:
+			return PressureRising
+		}
+	case -1:
+		switch {
+		case s1 < 0 && s2 > 0:
+			return PressureFallingThenRising
+		case s1 < 0 && s2 == 0:
+			return PressureFallingThenSteady
+		case s1 >= 0 && s2 < 0:
+			return PressureSteadyThenFalling
+		case s1 < 0 && s2 < 0 && slower >= PressureSteadyThreshold:
+			return PressureSteadyThenFalling
+		case s1 < 0 && s2 < 0 && faster >= PressureSteadyThreshold:
+			return PressureFallingThenSteady
+		default:
+			return PressureFalling
+		}
+	default:
+		switch {
+		case s1 > 0 && s2 < 0:
+			return PressureRisingThenFalling
+		case s1 < 0 && s2 > 0:
+			return PressureFallingThenRising
+		default:
+			return PressureSteady
+		}
+	}
+}
+
+// PressureChangeText describes the change in pressure over three hours using the terms of
+// marine forecasts, such as "Falling quickly".
+func PressureChangeText(change unit.Pressure) string {
+	hpa := math.Abs(change.Hectopascals())
+	if hpa < PressureSteadyThreshold.Hectopascals() {
+		return "Steady"
+	}
+
+	dir := "Rising"
+	if change < 0 {
+		dir = "Falling"
+	}
+	switch {
+	case hpa < 1.6:
+		return dir + " slowly"
+	case hpa < 3.6:
+		return dir
+	case hpa <= 6:
+		return dir + " quickly"
+	default:
+		return dir + " very rapidly"
+	}
+}
@@ -0,0 +1,58 @@
+package meteorology
+
+import (
+	"testing"
+
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPressureTendency(t *testing.T) {
+	tests := []struct {
+		name          string
+		start, mid, e float64
+		want          PressureCharacteristic
+	}{
+		{"rising then falling, same", 1010, 1011, 1010, PressureRisingThenFalling},
+		{"rising then falling, higher", 1010, 1012, 1011, PressureRisingThenFalling},
+		{"rising then steady", 1010, 1011, 1011, PressureRisingThenSteady},
+		{"rising more slowly", 1010, 1012, 1012.5, PressureRisingThenSteady},
+		{"rising", 1010, 1011, 1012, PressureRising},
+		{"falling then rising, higher", 1010, 1009, 1011, PressureSteadyThenRising},
+		{"rising more rapidly", 1010, 1010.5, 1012, PressureSteadyThenRising},
+		{"steady", 1010, 1010.05, 1010, PressureSteady},
+		{"falling then rising, same", 1010, 1009, 1010, PressureFallingThenRising},
+		{"falling then steady", 1010, 1009, 1009, PressureFallingThenSteady},
+		{"falling", 1010, 1008, 1006, PressureFalling},
+		{"falling more rapidly", 1010, 1009.5, 1007, PressureSteadyThenFalling},
+		{"steady then falling", 1010, 1010, 1008, PressureSteadyThenFalling},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PressureTendency(
+				unit.Pressure(tt.start)*unit.Hectopascal,
+				unit.Pressure(tt.mid)*unit.Hectopascal,
+				unit.Pressure(tt.e)*unit.Hectopascal)
+			assert.Equal(t, tt.want, got, got.String())
+		})
+	}
+
+	assert.Equal(t, -1, PressureCharacteristicNone.Code())
+	assert.Equal(t, "", PressureCharacteristicNone.String())
+}
+
+func TestPressureChangeText(t *testing.T) {
+	tests := []struct {
+		change float64
+		want   string
+	}{
+		{0.05, "Steady"},
+		{1.0, "Rising slowly"},
+		{-2.0, "Falling"},
+		{-4.0, "Falling quickly"},
+		{7.0, "Rising very rapidly"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, PressureChangeText(unit.Pressure(tt.change)*unit.Hectopascal), tt.change)
+	}
+}
@@ -0,0 +1,62 @@
+package meteorology
+
+import (
+	"math"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+// solarConstantNREL is the solar constant in W m-2, as used by the NREL solar position algorithm.
+const solarConstantNREL = 1367.0
+
+// SolarElevation returns the elevation of the sun above the horizon at latitude lat and
+// longitude lon degrees, at time t. The elevation is negative when the sun is below the horizon.
+func SolarElevation(lat, lon float64, t time.Time) unit.Angle {
+	phi := lat * math.Pi / 180
+	delta := solarDeclination(t.UTC().YearDay())
+	w := solarTimeAngle(lon, t)
+
+	sinAlt := math.Sin(phi)*math.Sin(delta) + math.Cos(phi)*math.Cos(delta)*math.Cos(w)
+	return unit.Angle(math.Asin(sinAlt)) * unit.Radian
+}
+
+// airMass returns the relative optical air mass for the solar elevation el degrees,
+// adjusted for the altitude z metres.
+func airMass(el, z float64) float64 {
+	return math.Pow((288-0.0065*z)/288, 5.256) / (math.Sin(el*math.Pi/180) + 0.15*math.Pow(el+3.885, -1.253))
+}
+
+// topOfAtmosphereRadiation returns the solar radiation received at the top of the atmosphere,
+// in W m-2, on a horizontal surface for the solar elevation el degrees on the date of t.
+func topOfAtmosphereRadiation(el float64, t time.Time) float64 {
+	return solarConstantNREL * math.Sin(el*math.Pi/180) * inverseRelativeDistance(t.UTC().YearDay())
+}
+
+// RyanStolzenbachSolarRadiation returns the theoretical clear sky solar radiation at site s and time t,
+// using the Ryan-Stolzenbach model, where transmission is the atmospheric transmission coefficient,
+// typically 0.7 to 0.91.
+func RyanStolzenbachSolarRadiation(s Site, t time.Time, transmission float64) xunit.Irradiance {
+	el := SolarElevation(s.Latitude, s.Longitude, t).Degrees()
+	if el <= 0 {
+		return 0
+	}
+
+	rm := airMass(el, s.Altitude)
+	return xunit.Irradiance(topOfAtmosphereRadiation(el, t)*math.Pow(transmission, rm)) * xunit.WattPerSquareMetre
+}
+
+// BrasSolarRadiation returns the theoretical clear sky solar radiation at site s and time t,
+// using the Bras model, where turbidity is the atmospheric turbidity factor, from 2 for a
+// clear sky through 5 for smoggy urban areas.
+func BrasSolarRadiation(s Site, t time.Time, turbidity float64) xunit.Irradiance {
+	el := SolarElevation(s.Latitude, s.Longitude, t).Degrees()
+	if el <= 0 {
+		return 0
+	}
+
+	rm := airMass(el, s.Altitude)
+	al := 0.128 - 0.054*math.Log10(rm)
+	return xunit.Irradiance(topOfAtmosphereRadiation(el, t)*math.Exp(-turbidity*al*rm)) * xunit.WattPerSquareMetre
+}
@@ -0,0 +1,42 @@
+package meteorology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolarElevation(t *testing.T) {
+	// solar noon at Greenwich on the March equinox, accounting for the equation of time
+	noon := time.Date(2021, 3, 20, 12, 7, 0, 0, time.UTC)
+	assert.InDelta(t, 90, SolarElevation(0, 0, noon).Degrees(), 1)
+	assert.InDelta(t, 38.5, SolarElevation(51.5, 0, noon).Degrees(), 1)
+	assert.InDelta(t, 48.6, SolarElevation(-41.4, 0, noon).Degrees(), 1)
+
+	// midnight
+	assert.InDelta(t, -90, SolarElevation(0, 0, noon.Add(12*time.Hour)).Degrees(), 1)
+
+	// solar noon in Tasmania, during daylight saving time, on the December solstice
+	hobart := time.Date(2021, 12, 21, 13, 10, 0, 0, time.FixedZone("AEDT", 11*60*60))
+	assert.InDelta(t, 72.0, SolarElevation(-41.44, 147.23, hobart).Degrees(), 1)
+}
+
+func TestRyanStolzenbachSolarRadiation(t *testing.T) {
+	noon := time.Date(2021, 3, 20, 12, 7, 0, 0, time.UTC)
+	s := Site{}
+
+	assert.InDelta(t, 1095, RyanStolzenbachSolarRadiation(s, noon, 0.8).WattsPerSquareMetre(), 10)
+	assert.Greater(t, RyanStolzenbachSolarRadiation(Site{Altitude: 2000}, noon, 0.8), RyanStolzenbachSolarRadiation(s, noon, 0.8))
+	assert.Greater(t, RyanStolzenbachSolarRadiation(s, noon, 0.8), RyanStolzenbachSolarRadiation(s, noon.Add(3*time.Hour), 0.8))
+	assert.Zero(t, RyanStolzenbachSolarRadiation(s, noon.Add(12*time.Hour), 0.8))
+}
+
+func TestBrasSolarRadiation(t *testing.T) {
+	noon := time.Date(2021, 3, 20, 12, 7, 0, 0, time.UTC)
+	s := Site{}
+
+	assert.InDelta(t, 1060, BrasSolarRadiation(s, noon, 2).WattsPerSquareMetre(), 10)
+	assert.Greater(t, BrasSolarRadiation(s, noon, 2), BrasSolarRadiation(s, noon, 5))
+	assert.Zero(t, BrasSolarRadiation(s, noon.Add(12*time.Hour), 2))
+}
@@ -0,0 +1,18 @@
+package meteorology
+
+import (
+	"math"
+
+	"github.com/martinlindhe/unit"
+)
+
+// WetBulb calculates the wet-bulb temperature using temp and rh, at standard sea level pressure.
+// See https://doi.org/10.1175/JAMC-D-11-0143.1
+func WetBulb(temp unit.Temperature, rh int) unit.Temperature {
+	T, RH := temp.Celsius(), float64(rh)
+
+	tw := T*math.Atan(0.151977*math.Sqrt(RH+8.313659)) +
+		math.Atan(T+RH) - math.Atan(RH-1.676331) +
+		0.00391838*math.Pow(RH, 1.5)*math.Atan(0.023101*RH) - 4.686035
+	return unit.FromCelsius(tw)
+}
@@ -0,0 +1,13 @@
+package meteorology
+
+import (
+	"testing"
+
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWetBulb(t *testing.T) {
+	got := WetBulb(unit.FromCelsius(20), 50)
+	assert.InDelta(t, 13.7, got.Celsius(), 0.05)
+}
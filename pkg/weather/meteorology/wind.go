@@ -0,0 +1,161 @@
+package meteorology
+
+import (
+	"math"
+
+	"github.com/martinlindhe/unit"
+)
+
+// WindSample is a single measurement of the wind.
+type WindSample struct {
+	Speed     unit.Speed
+	Gust      unit.Speed
+	Direction unit.Angle
+}
+
+// WindStatistics summarises the wind for a number of samples.
+type WindStatistics struct {
+	Samples             int
+	ScalarMeanSpeed     unit.Speed // ScalarMeanSpeed is the mean of the wind speeds, regardless of direction
+	VectorMeanSpeed     unit.Speed // VectorMeanSpeed is the magnitude of the mean wind vector
+	VectorMeanDirection unit.Angle // VectorMeanDirection is the direction of the mean wind vector
+	MaxSpeed            unit.Speed
+	MaxGust             unit.Speed
+	GustFactor          float64    // GustFactor is the ratio of the maximum gust to the scalar mean speed
+	DirectionStdDev     unit.Angle // DirectionStdDev is the Yamartino estimate of the standard deviation of the direction
+	Calm                float64    // Calm is the fraction of samples which are calm
+}
+
+// CalculateWindStatistics calculates the wind statistics for samples.
+//
+// The vector mean direction is weighted by the wind speed, such that winds at 350° and 10° average
+// to 0°, rather than 180°. When all samples are calm, the direction is the mean of the unit vectors.
+// The direction variability uses the method of Yamartino, https://doi.org/10.1175/1520-0450(1984)023%3C1362:ACOSPE%3E2.0.CO;2,
+// excluding calm samples.
+func CalculateWindStatistics(samples []WindSample) WindStatistics {
+	ws := WindStatistics{Samples: len(samples)}
+	if len(samples) == 0 {
+		return ws
+	}
+
+	var (
+		sum, u, v  float64 // speeds, in m/s
+		su, sv     float64 // unit vectors
+		n, calm    int
+		maxS, maxG unit.Speed
+	)
+	for _, s := range samples {
+		mps := s.Speed.MetersPerSecond()
+		rad := s.Direction.Radians()
+		sum += mps
+		u += mps * math.Sin(rad)
+		v += mps * math.Cos(rad)
+
+		if SpeedToWindForce(s.Speed) == WindForceCalm {
+			calm++
+		} else {
+			su += math.Sin(rad)
+			sv += math.Cos(rad)
+			n++
+		}
+
+		if s.Speed > maxS {
+			maxS = s.Speed
+		}
+		if s.Gust > maxG {
+			maxG = s.Gust
+		}
+	}
+
+	count := float64(len(samples))
+	ws.ScalarMeanSpeed = unit.Speed(sum/count) * unit.MetersPerSecond
+	ws.VectorMeanSpeed = unit.Speed(math.Hypot(u, v)/count) * unit.MetersPerSecond
+	ws.MaxSpeed = maxS
+	ws.MaxGust = maxG
+	ws.Calm = float64(calm) / count
+
+	if u == 0 && v == 0 {
+		// all samples are calm, so use the mean of the directions
+		for _, s := range samples {
+			u += math.Sin(s.Direction.Radians())
+			v += math.Cos(s.Direction.Radians())
+		}
+	}
+	ws.VectorMeanDirection = unit.Angle(normaliseDegrees(math.Atan2(u, v)*180/math.Pi)) * unit.Degree
+
+	if ws.ScalarMeanSpeed > 0 {
+		ws.GustFactor = maxG.MetersPerSecond() / ws.ScalarMeanSpeed.MetersPerSecond()
+	}
+
+	if n > 0 {
+		sa, ca := su/float64(n), sv/float64(n)
+		eps := math.Sqrt(math.Max(0, 1-(sa*sa+ca*ca)))
+		sigma := math.Asin(eps) * (1 + (2/math.Sqrt(3)-1)*math.Pow(eps, 3))
+		ws.DirectionStdDev = unit.Angle(sigma) * unit.Radian
+	}
+
+	return ws
+}
+
+func normaliseDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// WindRose is a table of the frequency of the wind by direction and speed class.
+type WindRose struct {
+	// Classes are the upper bounds, exclusive, of each speed class. An additional class
+	// contains the speeds greater than or equal to the last bound.
+	Classes []unit.Speed
+	// Frequency is the fraction of samples for each of the 16 compass points, indexed as
+	// Directions, and for each speed class.
+	Frequency [16][]float64
+	// Calm is the fraction of samples which are calm, and excluded from Frequency.
+	Calm    float64
+	Samples int
+}
+
+// DefaultWindRoseClasses are the speed classes used by the Bureau of Meteorology wind roses.
+var DefaultWindRoseClasses = []unit.Speed{
+	10 * unit.KilometersPerHour,
+	20 * unit.KilometersPerHour,
+	30 * unit.KilometersPerHour,
+	40 * unit.KilometersPerHour,
+}
+
+// Directions returns the 16 compass points of the wind rose.
+func (WindRose) Directions() []Direction { return directions }
+
+// CalculateWindRose calculates the wind rose for samples, using the speed classes.
+func CalculateWindRose(samples []WindSample, classes []unit.Speed) WindRose {
+	wr := WindRose{Classes: classes, Samples: len(samples)}
+	for i := range wr.Frequency {
+		wr.Frequency[i] = make([]float64, len(classes)+1)
+	}
+	if len(samples) == 0 {
+		return wr
+	}
+
+	inc := 1 / float64(len(samples))
+	for _, s := range samples {
+		if SpeedToWindForce(s.Speed) == WindForceCalm {
+			wr.Calm += inc
+			continue
+		}
+
+		dir := int((normaliseDegrees(s.Direction.Degrees())+11.25)/22.5) % 16
+		class := len(classes)
+		for i, c := range classes {
+			if s.Speed < c {
+				class = i
+				break
+			}
+		}
+		wr.Frequency[dir][class] += inc
+	}
+
+	return wr
+}
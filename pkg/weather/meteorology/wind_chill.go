@@ -0,0 +1,67 @@
+package meteorology
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/martinlindhe/unit"
+)
+
+// WindChillFormula is the formula used to calculate the wind chill.
+type WindChillFormula int
+
+const (
+	// WindChillJAGTI is the formula of the Joint Action Group for Temperature Indices,
+	// adopted by the US and Canada in 2001.
+	WindChillJAGTI WindChillFormula = iota
+	// WindChillLegacy is the formula of Siple and Passel, used prior to 2001.
+	WindChillLegacy
+)
+
+func (f *WindChillFormula) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "jag-ti":
+		*f = WindChillJAGTI
+	case "legacy":
+		*f = WindChillLegacy
+	default:
+		return fmt.Errorf("invalid wind chill formula: %s", string(text))
+	}
+	return nil
+}
+
+// WindChill calculates the wind chill using temp, wind and the formula f.
+func WindChill(temp unit.Temperature, wind unit.Speed, f WindChillFormula) unit.Temperature {
+	if f == WindChillLegacy {
+		return LegacyWindChill(temp, wind)
+	}
+	return JAGTIWindChill(temp, wind)
+}
+
+// JAGTIWindChill calculates the wind chill using temp and wind, per the JAG/TI formula.
+// The wind chill is only defined for temperatures at or below 10°C and wind speeds above 4.8 km/h,
+// otherwise temp is returned.
+// See https://en.wikipedia.org/wiki/Wind_chill#North_American_and_United_Kingdom_wind_chill_index
+func JAGTIWindChill(temp unit.Temperature, wind unit.Speed) unit.Temperature {
+	T := temp.Celsius()
+	V := wind.KilometersPerHour()
+	if T > 10 || V <= 4.8 {
+		return temp
+	}
+
+	v := math.Pow(V, 0.16)
+	return unit.FromCelsius(13.12 + 0.6215*T - 11.37*v + 0.3965*T*v)
+}
+
+// LegacyWindChill calculates the wind chill using temp and wind, per the formula of Siple and Passel.
+// The wind chill is only defined for temperatures below 33°C and wind speeds above 1.79 m/s,
+// otherwise temp is returned.
+func LegacyWindChill(temp unit.Temperature, wind unit.Speed) unit.Temperature {
+	T := temp.Celsius()
+	v := wind.MetersPerSecond()
+	if T >= 33 || v <= 1.79 {
+		return temp
+	}
+
+	return unit.FromCelsius(33 - (10.45+10*math.Sqrt(v)-v)*(33-T)/22.034)
+}
@@ -22,7 +22,8 @@ const (
 	WindForceHurricane
 )
 
-func (wf WindForce) ToInt() int { return int(wf) }
+// ToInt returns the Beaufort number of the wind force, from 0 for calm to 12 for hurricane.
+func (wf WindForce) ToInt() int { return int(wf - WindForceCalm) }
 
 // SpeedToWindForce calculates the wind force of speed.
 // See https://en.wikipedia.org/wiki/Beaufort_scale#Modern_scale
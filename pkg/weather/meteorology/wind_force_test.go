@@ -0,0 +1,24 @@
+package meteorology
+
+import (
+	"testing"
+
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeedToWindForce(t *testing.T) {
+	tests := []struct {
+		speed unit.Speed
+		want  int
+	}{
+		{0, 0},
+		{5 * unit.KilometersPerHour, 1},
+		{12 * unit.KilometersPerHour, 3},
+		{40 * unit.KilometersPerHour, 6},
+		{150 * unit.KilometersPerHour, 12},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, SpeedToWindForce(tc.speed).ToInt(), tc.speed.KilometersPerHour())
+	}
+}
@@ -0,0 +1,72 @@
+package meteorology
+
+import (
+	"testing"
+
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateWindStatistics(t *testing.T) {
+	t.Run("north", func(t *testing.T) {
+		ws := CalculateWindStatistics([]WindSample{
+			{Speed: 10 * unit.KilometersPerHour, Gust: 15 * unit.KilometersPerHour, Direction: 350 * unit.Degree},
+			{Speed: 10 * unit.KilometersPerHour, Gust: 20 * unit.KilometersPerHour, Direction: 10 * unit.Degree},
+		})
+		assert.Equal(t, 2, ws.Samples)
+		assert.InDelta(t, 0, signedDegrees(ws.VectorMeanDirection.Degrees()), 0.01)
+		assert.InDelta(t, 10, ws.ScalarMeanSpeed.KilometersPerHour(), 0.01)
+		assert.InDelta(t, 9.848, ws.VectorMeanSpeed.KilometersPerHour(), 0.01)
+		assert.InDelta(t, 20, ws.MaxGust.KilometersPerHour(), 0.01)
+		assert.InDelta(t, 2, ws.GustFactor, 0.01)
+		assert.InDelta(t, 10, ws.DirectionStdDev.Degrees(), 0.1)
+	})
+
+	t.Run("opposing", func(t *testing.T) {
+		ws := CalculateWindStatistics([]WindSample{
+			{Speed: 10 * unit.KilometersPerHour, Direction: 90 * unit.Degree},
+			{Speed: 20 * unit.KilometersPerHour, Direction: 270 * unit.Degree},
+		})
+		assert.InDelta(t, 270, ws.VectorMeanDirection.Degrees(), 0.01)
+		assert.InDelta(t, 15, ws.ScalarMeanSpeed.KilometersPerHour(), 0.01)
+		assert.InDelta(t, 5, ws.VectorMeanSpeed.KilometersPerHour(), 0.01)
+	})
+
+	t.Run("calm", func(t *testing.T) {
+		ws := CalculateWindStatistics([]WindSample{
+			{Direction: 80 * unit.Degree},
+			{Direction: 100 * unit.Degree},
+		})
+		assert.InDelta(t, 90, ws.VectorMeanDirection.Degrees(), 0.01)
+		assert.Zero(t, ws.ScalarMeanSpeed)
+		assert.Zero(t, ws.GustFactor)
+		assert.Equal(t, 1.0, ws.Calm)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, WindStatistics{}, CalculateWindStatistics(nil))
+	})
+}
+
+// signedDegrees maps angles to the range -180° to 180°.
+func signedDegrees(deg float64) float64 {
+	if deg > 180 {
+		return deg - 360
+	}
+	return deg
+}
+
+func TestCalculateWindRose(t *testing.T) {
+	wr := CalculateWindRose([]WindSample{
+		{Speed: 0, Direction: 0},
+		{Speed: 5 * unit.KilometersPerHour, Direction: 355 * unit.Degree},
+		{Speed: 15 * unit.KilometersPerHour, Direction: 5 * unit.Degree},
+		{Speed: 50 * unit.KilometersPerHour, Direction: 180 * unit.Degree},
+	}, DefaultWindRoseClasses)
+
+	assert.Equal(t, 4, wr.Samples)
+	assert.Equal(t, 0.25, wr.Calm)
+	assert.Equal(t, []float64{0.25, 0.25, 0, 0, 0}, wr.Frequency[0])
+	assert.Equal(t, []float64{0, 0, 0, 0, 0.25}, wr.Frequency[8])
+	assert.Equal(t, Direction("S"), wr.Directions()[8])
+}
@@ -0,0 +1,143 @@
+package meteorology
+
+import (
+	"math"
+	"time"
+
+	"github.com/martinlindhe/unit"
+)
+
+// ZambrettiForecast is a forecast of the Zambretti algorithm, numbered 1 through 26,
+// corresponding to the letters A through Z. Zero indicates there is no forecast.
+type ZambrettiForecast int
+
+var zambrettiForecasts = []string{
+	"Settled fine",
+	"Fine weather",
+	"Becoming fine",
+	"Fine, becoming less settled",
+	"Fine, possible showers",
+	"Fairly fine, improving",
+	"Fairly fine, possible showers early",
+	"Fairly fine, showery later",
+	"Showery early, improving",
+	"Changeable, mending",
+	"Fairly fine, showers likely",
+	"Rather unsettled clearing later",
+	"Unsettled, probably improving",
+	"Showery, bright intervals",
+	"Showery, becoming less settled",
+	"Changeable, some rain",
+	"Unsettled, short fine intervals",
+	"Unsettled, rain later",
+	"Unsettled, some rain",
+	"Mostly very unsettled",
+	"Occasional rain, worsening",
+	"Rain at times, very unsettled",
+	"Rain at frequent intervals",
+	"Rain, very unsettled",
+	"Stormy, may improve",
+	"Stormy, much rain",
+}
+
+func (z ZambrettiForecast) ToInt() int { return int(z) }
+
+func (z ZambrettiForecast) valid() bool { return z >= 1 && int(z) <= len(zambrettiForecasts) }
+
+// Letter returns the letter, A through Z, of the forecast.
+func (z ZambrettiForecast) Letter() string {
+	if !z.valid() {
+		return ""
+	}
+	return string(rune('A' + z - 1))
+}
+
+// String returns the forecast text.
+func (z ZambrettiForecast) String() string {
+	if !z.valid() {
+		return ""
+	}
+	return zambrettiForecasts[z-1]
+}
+
+func (z ZambrettiForecast) MarshalText() ([]byte, error) {
+	return []byte(z.String()), nil
+}
+
+// ZambrettiTrendThreshold is the minimum change in pressure over three hours for the pressure
+// to be considered rising or falling, rather than steady.
+const ZambrettiTrendThreshold = 1.6 * unit.Hectopascal
+
+// Forecast letter indexes for each of the 22 divisions of the pressure range, by pressure trend.
+var (
+	zambrettiRising  = []int{25, 25, 25, 24, 24, 19, 16, 12, 11, 9, 8, 6, 5, 2, 1, 1, 0, 0, 0, 0, 0, 0}
+	zambrettiSteady  = []int{25, 25, 25, 25, 25, 25, 23, 23, 22, 18, 15, 13, 10, 4, 1, 1, 0, 0, 0, 0, 0, 0}
+	zambrettiFalling = []int{25, 25, 25, 25, 25, 25, 25, 25, 23, 23, 21, 20, 17, 14, 7, 3, 1, 1, 1, 0, 0, 0}
+)
+
+// Adjustment to the pressure, as a percentage of the pressure range, for each compass
+// point of the wind direction, starting with the wind from the pole (N in the
+// northern hemisphere, S in the southern hemisphere).
+var zambrettiWindAdjustment = []float64{6, 5, 5, 2, -0.5, -2, -5, -8.5, -12, -10, -6, -4.5, -3, -0.5, 1.5, 3}
+
+// Zambretti calculates the Zambretti forecast, using the sea level pressure, the change in
+// pressure over the last three hours and the wind bearing. When calm is true, no adjustment
+// is made for the wind direction. The wind direction adjustments are rotated by 180° for the
+// southern hemisphere and the seasonal adjustment is applied in the local summer.
+//
+// Based on the algorithm published by Beteljuice, http://www.beteljuice.co.uk/zambretti/forecast.html
+func Zambretti(pressure, change unit.Pressure, bearing unit.Angle, calm, southern bool, month time.Month) ZambrettiForecast {
+	const (
+		top    = 1050.0
+		bottom = 950.0
+		rng    = top - bottom
+	)
+
+	hpa := pressure.Hectopascals()
+	if hpa <= 0 {
+		return 0
+	}
+
+	if !calm {
+		deg := bearing.Degrees()
+		if southern {
+			deg += 180
+		}
+		point := int(math.Mod(deg+11.25, 360) / 22.5)
+		hpa += zambrettiWindAdjustment[point%16] / 100 * rng
+	}
+
+	summer := month >= time.April && month <= time.September
+	if southern {
+		summer = !summer
+	}
+
+	var options []int
+	switch {
+	case change >= ZambrettiTrendThreshold:
+		options = zambrettiRising
+		if summer {
+			hpa += 7.0 / 100 * rng
+		}
+	case change <= -ZambrettiTrendThreshold:
+		options = zambrettiFalling
+		if summer {
+			hpa -= 7.0 / 100 * rng
+		}
+	default:
+		options = zambrettiSteady
+	}
+
+	if hpa == top {
+		hpa = top - 1
+	}
+
+	option := int(math.Floor((hpa - bottom) / (rng / 22)))
+	if option < 0 {
+		option = 0
+	} else if option > 21 {
+		option = 21
+	}
+
+	return ZambrettiForecast(options[option] + 1)
+}
@@ -0,0 +1,48 @@
+package meteorology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZambretti(t *testing.T) {
+	tests := []struct {
+		name     string
+		pressure unit.Pressure
+		change   unit.Pressure
+		bearing  unit.Angle
+		calm     bool
+		southern bool
+		month    time.Month
+		want     string
+	}{
+		{"high rising northern summer", 1030 * unit.Hectopascal, 2 * unit.Hectopascal, 0, false, false, time.June, "A"},
+		{"high rising southern summer", 1030 * unit.Hectopascal, 2 * unit.Hectopascal, 180 * unit.Degree, false, true, time.December, "A"},
+		{"low falling northern winter", 970 * unit.Hectopascal, -2 * unit.Hectopascal, 180 * unit.Degree, false, false, time.January, "Z"},
+		{"low falling southern winter", 970 * unit.Hectopascal, -2 * unit.Hectopascal, 0, false, true, time.July, "Z"},
+		{"steady calm", 1013 * unit.Hectopascal, 0, 0, true, false, time.March, "E"},
+		{"exceptionally high", 1080 * unit.Hectopascal, 0, 0, true, false, time.March, "A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Zambretti(tt.pressure, tt.change, tt.bearing, tt.calm, tt.southern, tt.month)
+			assert.Equal(t, tt.want, got.Letter())
+		})
+	}
+
+	t.Run("no pressure", func(t *testing.T) {
+		got := Zambretti(0, 0, 0, true, false, time.March)
+		assert.Equal(t, ZambrettiForecast(0), got)
+		assert.Equal(t, "", got.String())
+	})
+}
+
+func TestZambrettiForecast_String(t *testing.T) {
+	assert.Equal(t, "Settled fine", ZambrettiForecast(1).String())
+	assert.Equal(t, "Fine, possible showers", ZambrettiForecast(5).String())
+	assert.Equal(t, "Stormy, much rain", ZambrettiForecast(26).String())
+	assert.Equal(t, "", ZambrettiForecast(27).String())
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/martinlindhe/unit"
+)
+
+// ClimateNormal contains the long-term statistics for a month, or a day of the month,
+// such as the averages over 30 years published by a national weather service.
+// A temperature is zero, absolute zero, when it is not available.
+type ClimateNormal struct {
+	Month        time.Month
+	Day          int              // Day is the day of the month, or zero for the normal of the whole month
+	TempHiMean   unit.Temperature // mean daily maximum temperature
+	TempLoMean   unit.Temperature // mean daily minimum temperature
+	TempMean     unit.Temperature // mean daily temperature
+	TempHiRecord unit.Temperature // highest temperature recorded
+	TempLoRecord unit.Temperature // lowest temperature recorded
+	Rain         unit.Length      // mean rainfall for the month, or the day
+}
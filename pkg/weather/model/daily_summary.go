@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+// DailySummary contains the statistics for a single day.
+type DailySummary struct {
+	Date               time.Time // Date is midnight of the day summarised
+	TempHi             unit.Temperature
+	TempHiTime         time.Time
+	TempLo             unit.Temperature
+	TempLoTime         time.Time
+	TempAvg            unit.Temperature
+	PressureHi         unit.Pressure
+	PressureHiTime     time.Time
+	PressureLo         unit.Pressure
+	PressureLoTime     time.Time
+	Rain               unit.Length
+	RainRateHi         unit.Length
+	RainRateHiTime     time.Time
+	WindGustHi         unit.Speed
+	WindGustHiTime     time.Time
+	WindGustHiDir      unit.Angle
+	WindSpeedHi        unit.Speed
+	WindSpeedHiTime    time.Time
+	WindRun            unit.Length
+	HumidityHi         int
+	HumidityLo         int
+	Evapotranspiration unit.Length
+	SunshineHours      time.Duration
+	DominantWindDir    unit.Angle
+	HeatingDegreeDays  float64
+	CoolingDegreeDays  float64
+	GrowingDegreeDays  float64
+	SolarRadiationHi   xunit.Irradiance
+	UltravioletIndexHi int
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/martinlindhe/unit"
+)
+
+// RainEvent is a period of rainfall, separated from other events by a dry period.
+type RainEvent struct {
+	ID             uint
+	Start          time.Time // Start is the time of the first observation to record rain
+	End            time.Time // End is the time of the last observation to record rain
+	Duration       time.Duration
+	Rain           unit.Length
+	RainRateHi     unit.Length // RainRateHi is the peak rain rate per hour
+	RainRateHiTime time.Time
+	InProgress     bool // InProgress is true until the dry period following the event has elapsed
+}
@@ -2,6 +2,12 @@ package reporting
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
 )
 
 type BarometricMeasurementType int
@@ -23,12 +29,159 @@ func (b *BarometricMeasurementType) UnmarshalText(text []byte) error {
 	return nil
 }
 
+type SolarModel int
+
+const (
+	SolarModelRyanStolzenbach SolarModel = iota
+	SolarModelBras
+)
+
+func (m *SolarModel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ryan-stolzenbach":
+		*m = SolarModelRyanStolzenbach
+	case "bras":
+		*m = SolarModelBras
+	default:
+		return fmt.Errorf("invalid solar model: %s", string(text))
+	}
+	return nil
+}
+
+type SolarConfig struct {
+	Model             SolarModel
+	Transmission      float64 // Transmission is the atmospheric transmission coefficient of the Ryan-Stolzenbach model
+	Turbidity         float64 // Turbidity is the atmospheric turbidity factor of the Bras model
+	SunshineThreshold float64 `toml:"sunshine_threshold" mapstructure:"sunshine_threshold"` // SunshineThreshold is the percentage of the theoretical maximum radiation considered sunny
+}
+
+type RainConfig struct {
+	// MaxIncrement is the largest increase of the total rain counter, in mm, between
+	// consecutive observations. Larger increments are considered a spike and ignored.
+	MaxIncrement float64 `toml:"max_increment" mapstructure:"max_increment"`
+	// YearStartMonth is the month, 1 through 12, which starts the rain year.
+	YearStartMonth time.Month `toml:"year_start_month" mapstructure:"year_start_month"`
+	// EventDryGap is the period without rain which ends a rain event.
+	EventDryGap time.Duration `toml:"event_dry_gap" mapstructure:"event_dry_gap"`
+}
+
+type PressureConfig struct {
+	// FallWarning is the fall of the pressure, in hPa, over three hours at which to warn of
+	// an approaching storm. A fall of 3.6 hPa is described as falling quickly.
+	FallWarning float64 `toml:"fall_warning" mapstructure:"fall_warning"`
+}
+
+type DegreeDaysConfig struct {
+	Method meteorology.DegreeDayMethod
+	// HeatingBase, CoolingBase and GrowingBase are the base temperatures, in °C, of each type of degree day.
+	HeatingBase float64 `toml:"heating_base" mapstructure:"heating_base"`
+	CoolingBase float64 `toml:"cooling_base" mapstructure:"cooling_base"`
+	GrowingBase float64 `toml:"growing_base" mapstructure:"growing_base"`
+	// GrowingCutoff is the temperature, in °C, above which growing degree days do not increase.
+	GrowingCutoff float64 `toml:"growing_cutoff" mapstructure:"growing_cutoff"`
+	// StartMonth and StartDay are the date which starts the season for the degree day totals.
+	StartMonth time.Month `toml:"start_month" mapstructure:"start_month"`
+	StartDay   int        `toml:"start_day" mapstructure:"start_day"`
+}
+
+type FeelsLikeConfig struct {
+	// WindChill is the formula of the wind chill.
+	WindChill meteorology.WindChillFormula `toml:"wind_chill" mapstructure:"wind_chill"`
+	// Locale selects the indices which make up the feels like temperature.
+	Locale meteorology.FeelsLikeLocale
+}
+
+type UnitsConfig struct {
+	// System is the unit system for reports; one of metric, metricwx or us.
+	System string
+	// Temperature, Wind, Pressure and Rain override the unit of System for each quantity, when specified.
+	Temperature units.Temperature
+	Wind        units.Speed
+	Pressure    units.Pressure
+	Rain        units.Rain
+}
+
+// Resolve returns the unit system, with the units specified for each quantity.
+func (c UnitsConfig) Resolve() (units.System, error) {
+	sys, err := units.ParseSystem(c.System)
+	if err != nil {
+		return sys, err
+	}
+	if c.Temperature != "" {
+		sys.Temperature = c.Temperature
+	}
+	if c.Wind != "" {
+		sys.Speed = c.Wind
+	}
+	if c.Pressure != "" {
+		sys.Pressure = c.Pressure
+	}
+	if c.Rain != "" {
+		sys.Rain = c.Rain
+	}
+	return sys, nil
+}
+
 type Config struct {
 	BarometricMeasurement BarometricMeasurementType `toml:"barometric_measurement" mapstructure:"barometric_measurement"`
+	AnemometerHeight      float64                   `toml:"anemometer_height" mapstructure:"anemometer_height"`
+	// SensorTimeout is the period without observations after which contact with the sensors is considered lost.
+	SensorTimeout time.Duration `toml:"sensor_timeout" mapstructure:"sensor_timeout"`
+	Solar         SolarConfig
+	Rain          RainConfig
+	Pressure      PressureConfig
+	DegreeDays    DegreeDaysConfig `toml:"degree_days" mapstructure:"degree_days"`
+	FeelsLike     FeelsLikeConfig  `toml:"feels_like" mapstructure:"feels_like"`
+	Units         UnitsConfig
 }
 
 func NewConfig() Config {
 	return Config{
 		BarometricMeasurement: BarometricMeasurementTypeRelative,
+		AnemometerHeight:      2,
+		SensorTimeout:         10 * time.Minute,
+		Solar: SolarConfig{
+			Model:             SolarModelRyanStolzenbach,
+			Transmission:      0.8,
+			Turbidity:         2,
+			SunshineThreshold: 75,
+		},
+		Rain: RainConfig{
+			MaxIncrement:   50,
+			YearStartMonth: time.January,
+			EventDryGap:    6 * time.Hour,
+		},
+		Pressure: PressureConfig{
+			FallWarning: 3.6,
+		},
+		DegreeDays: DegreeDaysConfig{
+			Method:        meteorology.DegreeDayMethodAveraging,
+			HeatingBase:   18,
+			CoolingBase:   18,
+			GrowingBase:   10,
+			GrowingCutoff: 30,
+			StartMonth:    time.January,
+			StartDay:      1,
+		},
+		FeelsLike: FeelsLikeConfig{
+			WindChill: meteorology.WindChillJAGTI,
+			Locale:    meteorology.FeelsLikeAustralia,
+		},
+		Units: UnitsConfig{
+			System: "metric",
+		},
+	}
+}
+
+// ReadConfig reads the reporting configuration from vp.
+func ReadConfig(vp *viper.Viper) (Config, error) {
+	cfg := NewConfig()
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToTimeDurationHookFunc(),
+	))
+	if err := vp.UnmarshalKey("reporting", &cfg, hook); err != nil {
+		return cfg, fmt.Errorf("config: %w", err)
 	}
+	return cfg, nil
 }
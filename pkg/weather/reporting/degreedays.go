@@ -0,0 +1,125 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+)
+
+func (r *Reporter) calcDegreeDays(ts time.Time, snap *snapshot, prior *priorStatistics, s *Statistics) {
+	s.HeatingDegreeDaysToDate, s.CoolingDegreeDaysToDate, s.GrowingDegreeDaysToDate = prior.hdd, prior.cdd, prior.gdd
+
+	if lo, hi, ok := r.todayTempRange(ts, snap); ok {
+		s.HeatingDegreeDaysToday, s.CoolingDegreeDaysToday, s.GrowingDegreeDaysToday = r.degreeDaysFor(lo, hi)
+		s.HeatingDegreeDaysToDate += s.HeatingDegreeDaysToday
+		s.CoolingDegreeDaysToDate += s.CoolingDegreeDaysToday
+		s.GrowingDegreeDaysToDate += s.GrowingDegreeDaysToday
+	}
+}
+
+// degreeDaysFor returns the heating, cooling and growing degree days for a day
+// with the temperatures tmin and tmax.
+func (r *Reporter) degreeDaysFor(tmin, tmax unit.Temperature) (hdd, cdd, gdd float64) {
+	cfg := r.degreeDays
+	hdd = meteorology.HeatingDegreeDays(tmin, tmax, unit.FromCelsius(cfg.HeatingBase), cfg.Method)
+	cdd = meteorology.CoolingDegreeDays(tmin, tmax, unit.FromCelsius(cfg.CoolingBase), cfg.Method)
+	gdd = meteorology.GrowingDegreeDays(tmin, tmax, unit.FromCelsius(cfg.GrowingBase), unit.FromCelsius(cfg.GrowingCutoff), cfg.Method)
+	return hdd, cdd, gdd
+}
+
+// degreeDaysSince returns the total heating, cooling and growing degree days from the start
+// of a meteorological day up to, but excluding, end. Archived days use the daily summaries.
+func (r *Reporter) degreeDaysSince(start, end time.Time) (hdd, cdd, gdd float64) {
+	r.eachDaySince(start, end, func(ds *model.DailySummary) {
+		hdd += ds.HeatingDegreeDays
+		cdd += ds.CoolingDegreeDays
+		gdd += ds.GrowingDegreeDays
+	}, func(day, next time.Time) {
+		if lo, hi, ok := r.tempRange(day, next); ok {
+			h, c, g := r.degreeDaysFor(lo, hi)
+			hdd, cdd, gdd = hdd+h, cdd+c, gdd+g
+		}
+	})
+
+	return hdd, cdd, gdd
+}
+
+// eachDaySince calls summary for each archived day from the start of a meteorological day up to,
+// but excluding, end, and then day with the start and end of each remaining day, from the day of
+// the first observation. The end of the last day is limited to end.
+func (r *Reporter) eachDaySince(start, end time.Time, summary func(ds *model.DailySummary), day func(start, end time.Time)) {
+	summaries, err := r.store.DailySummaries(r.cal.Date(start), r.cal.Date(end))
+	if err != nil {
+		r.log.Warn("Unable to read daily summaries.")
+	}
+	for _, ds := range summaries {
+		summary(ds)
+	}
+	if n := len(summaries); n > 0 {
+		start = r.cal.Start(summaries[n-1].Date.AddDate(0, 0, 1))
+	}
+
+	// skip the days before the first observation
+	var first []sqlite.Timestamp
+	r.store.DB().Model(&store.Observation{}).
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Order("timestamp").Limit(1).
+		Pluck("timestamp", &first)
+	if len(first) == 0 {
+		return
+	}
+	if d := r.cal.BeginningOfDay(first[0].In(start.Location())); d.After(start) {
+		start = d
+	}
+
+	for d := start; d.Before(end); {
+		next := r.cal.Start(r.cal.Date(d).AddDate(0, 0, 1))
+		if next.After(end) {
+			next = end
+		}
+		day(d, next)
+		d = next
+	}
+}
+
+// tempRange returns the minimum and maximum outdoor temperatures from start up to, but excluding, end,
+// and false when there are no observations.
+func (r *Reporter) tempRange(start, end time.Time) (lo, hi unit.Temperature, ok bool) {
+	var res struct {
+		Count    int64
+		Min, Max float64
+	}
+	r.store.DB().Model(&store.Observation{}).
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Select("COUNT(*) AS count, COALESCE(MIN(temp_outdoor_c), 0) AS min, COALESCE(MAX(temp_outdoor_c), 0) AS max").
+		Scan(&res)
+
+	return unit.FromCelsius(res.Min), unit.FromCelsius(res.Max), res.Count > 0
+}
+
+// todayTempRange returns the minimum and maximum outdoor temperatures of the meteorological day
+// up to ts, and false when there are no observations.
+func (r *Reporter) todayTempRange(ts time.Time, snap *snapshot) (lo, hi unit.Temperature, ok bool) {
+	if snap != nil {
+		if !snap.tempHi.valid {
+			return 0, 0, false
+		}
+		return unit.FromCelsius(snap.tempLo.v), unit.FromCelsius(snap.tempHi.v), true
+	}
+
+	today, _ := r.cal.Day(ts)
+	return r.tempRange(today, ts.Add(time.Second))
+}
+
+// beginningOfDegreeDaySeason returns the first day of the degree day season containing t.
+func (r *Reporter) beginningOfDegreeDaySeason(t time.Time) time.Time {
+	y, m, d := t.Date()
+	if m < r.degreeDays.StartMonth || (m == r.degreeDays.StartMonth && d < r.degreeDays.StartDay) {
+		y--
+	}
+	return time.Date(y, r.degreeDays.StartMonth, r.degreeDays.StartDay, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,174 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+// TempRange is the lowest and highest of a temperature, and the times they occurred.
+// The times are zero when there are no observations.
+type TempRange struct {
+	Lo, Hi         unit.Temperature
+	LoTime, HiTime time.Time
+}
+
+func (tr *TempRange) add(t unit.Temperature, ts time.Time) {
+	if tr.LoTime.IsZero() || t < tr.Lo {
+		tr.Lo, tr.LoTime = t, ts
+	}
+	if tr.HiTime.IsZero() || t > tr.Hi {
+		tr.Hi, tr.HiTime = t, ts
+	}
+}
+
+// Sample contains the statistics of the observations of an interval. The statistics
+// are zero when there are no observations.
+type Sample struct {
+	Time         time.Time // Time is the start of the interval
+	Observations int
+
+	Temp         unit.Temperature // mean outdoor temperature
+	TempRange    TempRange
+	IndoorTemp   unit.Temperature // mean indoor temperature
+	IndoorRange  TempRange
+	DewPoint     TempRange
+	WindChill    TempRange
+	Humidex      TempRange
+	HeatIndex    TempRange
+	ApparentTemp TempRange
+
+	Humidity       int // mean outdoor humidity
+	HumidityHi     int
+	HumidityHiTime time.Time
+	HumidityLo     int
+	HumidityLoTime time.Time
+
+	Pressure unit.Pressure // mean barometric pressure
+
+	WindSpeed    unit.Speed // scalar mean wind speed
+	WindDir      unit.Angle // vector mean wind direction
+	WindGust     unit.Speed // highest gust
+	WindGustTime time.Time
+	WindGustDir  unit.Angle // direction of the highest gust
+
+	Rain           unit.Length // rain during the interval
+	RainTime       time.Time   // time of the last rain during the interval
+	RainRateHi     unit.Length
+	RainRateHiTime time.Time
+
+	SolarRadiation   xunit.Irradiance // mean solar radiation
+	SolarRadiationHi xunit.Irradiance
+	UVIndexHi        int
+}
+
+// History returns the statistics of each interval from start up to, but excluding, end. The rain
+// of the first interval is relative to the last observation prior to start, when available.
+func (r *Reporter) History(start, end time.Time, interval time.Duration) ([]Sample, error) {
+	if interval <= 0 || !end.After(start) {
+		return nil, nil
+	}
+
+	var rows []store.Observation
+	tx := r.store.DB().
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Order("timestamp").
+		Find(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	n := int((end.Sub(start) + interval - 1) / interval)
+	res := make([]Sample, n)
+	sums := make([]struct {
+		temp, indoor, humidity, pressure, solar float64
+		wind                                    []meteorology.WindSample
+	}, n)
+	for i := range res {
+		res[i].Time = start.Add(time.Duration(i) * interval)
+	}
+
+	maxIncrement := unit.Length(r.rain.MaxIncrement) * unit.Millimeter
+	prev := r.store.LastObservation(start.Add(-time.Second))
+	for i := range rows {
+		o := rows[i].ToObservation()
+		ts := o.Timestamp.In(start.Location())
+		idx := int(ts.Sub(start) / interval)
+		s, sum := &res[idx], &sums[idx]
+
+		s.Observations++
+		sum.temp += o.TempOutdoor.Celsius()
+		sum.indoor += o.TempIndoor.Celsius()
+		sum.humidity += float64(o.HumidityOutdoor)
+		sum.pressure += r.barometer(o).Hectopascals()
+		sum.solar += o.SolarRadiation.WattsPerSquareMetre()
+		sum.wind = append(sum.wind, meteorology.WindSample{Speed: o.WindSpeed, Gust: o.WindGust, Direction: o.WindDir})
+
+		s.TempRange.add(o.TempOutdoor, ts)
+		s.IndoorRange.add(o.TempIndoor, ts)
+		s.DewPoint.add(meteorology.DewPoint(o.TempOutdoor, o.HumidityOutdoor), ts)
+		s.WindChill.add(meteorology.WindChill(o.TempOutdoor, o.WindSpeed, r.feelsLike.WindChill), ts)
+		s.Humidex.add(meteorology.Humidex(o.TempOutdoor, o.HumidityOutdoor), ts)
+		s.HeatIndex.add(meteorology.HeatIndex(o.TempOutdoor, o.HumidityOutdoor), ts)
+		s.ApparentTemp.add(meteorology.ApparentTemperature(o.TempOutdoor, o.WindSpeed, o.HumidityOutdoor), ts)
+
+		if s.Observations == 1 || o.HumidityOutdoor > s.HumidityHi {
+			s.HumidityHi, s.HumidityHiTime = o.HumidityOutdoor, ts
+		}
+		if s.Observations == 1 || o.HumidityOutdoor < s.HumidityLo {
+			s.HumidityLo, s.HumidityLoTime = o.HumidityOutdoor, ts
+		}
+		if s.WindGustTime.IsZero() || o.WindGust > s.WindGust {
+			s.WindGust, s.WindGustTime, s.WindGustDir = o.WindGust, ts, o.WindDir
+		}
+		if o.RainRatePerHour > s.RainRateHi {
+			s.RainRateHi, s.RainRateHiTime = o.RainRatePerHour, ts
+		}
+		if o.SolarRadiation > s.SolarRadiationHi {
+			s.SolarRadiationHi = o.SolarRadiation
+		}
+		if o.UltravioletIndex > s.UVIndexHi {
+			s.UVIndexHi = o.UltravioletIndex
+		}
+
+		// as for rainfall, ignore decreases of the counter and spikes
+		if prev != nil {
+			if inc := o.TotalRain - prev.TotalRain; inc > 0 && inc <= maxIncrement {
+				s.Rain += inc
+				s.RainTime = ts
+			}
+		}
+		prev = o
+	}
+
+	for i := range res {
+		s, sum := &res[i], &sums[i]
+		if s.Observations == 0 {
+			continue
+		}
+		n := float64(s.Observations)
+		s.Temp = unit.FromCelsius(sum.temp / n)
+		s.IndoorTemp = unit.FromCelsius(sum.indoor / n)
+		s.Humidity = int(sum.humidity/n + 0.5)
+		s.Pressure = unit.Pressure(sum.pressure/n) * unit.Hectopascal
+		s.SolarRadiation = xunit.Irradiance(sum.solar/n) * xunit.WattPerSquareMetre
+
+		ws := meteorology.CalculateWindStatistics(sum.wind)
+		s.WindSpeed, s.WindDir = ws.ScalarMeanSpeed, ws.VectorMeanDirection
+	}
+
+	return res, nil
+}
+
+// barometer returns the barometric pressure of o, per the configured measurement.
+func (r *Reporter) barometer(o *model.Observation) unit.Pressure {
+	if r.barometricType == BarometricMeasurementTypeAbsolute {
+		return o.BarometricAbs
+	}
+	return o.BarometricRel
+}
@@ -0,0 +1,325 @@
+// Package noaa writes monthly and yearly climatological summaries
+// in the text format of the US National Oceanic and Atmospheric Administration,
+// as produced by Cumulus and WeeWX.
+package noaa
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/martinlindhe/unit"
+)
+
+// Station describes the weather station in the report header.
+type Station struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+	Altitude  float64 // Altitude of the station in metres above sea level
+}
+
+type Options struct {
+	Station Station
+	Units   units.System
+}
+
+// thresholds are the temperatures and rainfall counted by the reports.
+type thresholds struct {
+	maxHot, maxCold, minCold, minVeryCold float64
+	rain                                  [3]float64
+}
+
+func (o Options) thresholds() thresholds {
+	t := thresholds{maxHot: 30, maxCold: 0, minCold: 0, minVeryCold: -18, rain: [3]float64{0.2, 2, 20}}
+	if o.Units.Temperature == units.Fahrenheit {
+		t.maxHot, t.maxCold, t.minCold, t.minVeryCold = 90, 32, 32, 0
+	}
+	switch o.Units.Rain {
+	case units.Inches:
+		t.rain = [3]float64{0.01, 0.1, 1}
+	case units.Centimeters:
+		t.rain = [3]float64{0.02, 0.2, 2}
+	}
+	return t
+}
+
+func (o Options) rainPrec() int {
+	if o.Units.Rain == units.Millimeters {
+		return 1
+	}
+	return 2
+}
+
+// summary accumulates the statistics of a number of days.
+type summary struct {
+	days                    int
+	meanSum, maxSum, minSum float64
+	high, low               float64
+	highDay, lowDay         time.Time
+	hdd, cdd                float64
+	rain, rainHi            float64
+	rainHiDay               time.Time
+	rainDays                [3]int
+	maxHot, maxCold         int
+	minCold, minVeryCold    int
+	windRun                 unit.Length
+	windHi                  float64
+	windHiDay               time.Time
+	windU, windV            float64
+}
+
+func (s *summary) add(ds *model.DailySummary, o Options, th thresholds) {
+	temp := o.Units.Temperature
+	mean, hi, lo := temp.To(ds.TempAvg), temp.To(ds.TempHi), temp.To(ds.TempLo)
+	rain := o.Units.Rain.To(ds.Rain)
+	gust := o.Units.Speed.To(ds.WindGustHi)
+
+	if s.days == 0 || hi > s.high {
+		s.high, s.highDay = hi, ds.Date
+	}
+	if s.days == 0 || lo < s.low {
+		s.low, s.lowDay = lo, ds.Date
+	}
+	if s.days == 0 || rain > s.rainHi {
+		s.rainHi, s.rainHiDay = rain, ds.Date
+	}
+	if s.days == 0 || gust > s.windHi {
+		s.windHi, s.windHiDay = gust, ds.Date
+	}
+
+	s.days++
+	s.meanSum += mean
+	s.maxSum += hi
+	s.minSum += lo
+	// degree days are stored in °C
+	s.hdd += temp.Delta(unit.Temperature(ds.HeatingDegreeDays))
+	s.cdd += temp.Delta(unit.Temperature(ds.CoolingDegreeDays))
+	s.rain += rain
+	for i, v := range th.rain {
+		if rain >= v {
+			s.rainDays[i]++
+		}
+	}
+	if hi >= th.maxHot {
+		s.maxHot++
+	}
+	if hi <= th.maxCold {
+		s.maxCold++
+	}
+	if lo <= th.minCold {
+		s.minCold++
+	}
+	if lo <= th.minVeryCold {
+		s.minVeryCold++
+	}
+
+	// the dominant direction of the period is weighted by the wind run of each day
+	s.windRun += ds.WindRun
+	rad := ds.DominantWindDir.Radians()
+	s.windU += ds.WindRun.Kilometers() * math.Sin(rad)
+	s.windV += ds.WindRun.Kilometers() * math.Cos(rad)
+}
+
+func (s *summary) mean() float64 { return s.meanSum / float64(s.days) }
+func (s *summary) max() float64  { return s.maxSum / float64(s.days) }
+func (s *summary) min() float64  { return s.minSum / float64(s.days) }
+
+// avgWind returns the average wind speed, in units u, of the days.
+func (s *summary) avgWind(u units.Speed) float64 {
+	return u.To(unit.Speed(s.windRun.Meters() / (float64(s.days) * 24 * 60 * 60)))
+}
+
+func (s *summary) dominantDir() string {
+	if s.windU == 0 && s.windV == 0 {
+		return ""
+	}
+	deg := math.Atan2(s.windU, s.windV) * 180 / math.Pi
+	if deg < 0 {
+		deg += 360
+	}
+	return meteorology.CardinalDirection(deg).String()
+}
+
+// WriteMonth writes the summary for the month of month, using the summaries of the days in the month.
+// Times are reported in the location of month.
+func WriteMonth(w io.Writer, month time.Time, days []*model.DailySummary, o Options) error {
+	bw := bufio.NewWriter(w)
+	th := o.thresholds()
+	u := o.Units
+	rp := o.rainPrec()
+
+	title := fmt.Sprintf("MONTHLY CLIMATOLOGICAL SUMMARY for %s", month.Format("Jan 2006"))
+	writeHeader(bw, title, o)
+
+	fmt.Fprintf(bw, "%s\n\n", center(fmt.Sprintf("TEMPERATURE (°%s), RAIN (%s), WIND SPEED (%s)", u.Temperature, u.Rain, u.Speed)))
+	fmt.Fprintln(bw, "                                      HEAT   COOL           AVG")
+	fmt.Fprintln(bw, "       MEAN                            DEG    DEG          WIND                  DOM")
+	fmt.Fprintln(bw, "DAY    TEMP   HIGH   TIME    LOW   TIME   DAYS   DAYS   RAIN  SPEED   HIGH   TIME  DIR")
+	fmt.Fprintln(bw, rule)
+
+	loc := month.Location()
+	byDay := make(map[int]*model.DailySummary, len(days))
+	for _, ds := range days {
+		byDay[ds.Date.Day()] = ds
+	}
+
+	var s summary
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		ds, ok := byDay[d.Day()]
+		if !ok {
+			fmt.Fprintf(bw, "%3d\n", d.Day())
+			continue
+		}
+		s.add(ds, o, th)
+
+		var day summary
+		day.add(ds, o, th)
+		fmt.Fprintf(bw, "%3d %7.1f %6.1f %6s %6.1f %6s %6.1f %6.1f %6.*f %6.1f %6.1f %6s %4s\n",
+			d.Day(), day.mean(), day.high, clock(ds.TempHiTime, loc), day.low, clock(ds.TempLoTime, loc),
+			day.hdd, day.cdd, rp, day.rain,
+			day.avgWind(u.Speed), day.windHi, clock(ds.WindGustHiTime, loc), day.dominantDir())
+	}
+
+	fmt.Fprintln(bw, rule)
+	if s.days > 0 {
+		fmt.Fprintf(bw, "    %7.1f %6.1f %6d %6.1f %6d %6.1f %6.1f %6.*f %6.1f %6.1f %6d %4s\n",
+			s.mean(), s.high, s.highDay.Day(), s.low, s.lowDay.Day(), s.hdd, s.cdd, rp, s.rain,
+			s.avgWind(u.Speed), s.windHi, s.windHiDay.Day(), s.dominantDir())
+
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "Max >= %5.1f: %3d\n", th.maxHot, s.maxHot)
+		fmt.Fprintf(bw, "Max <= %5.1f: %3d\n", th.maxCold, s.maxCold)
+		fmt.Fprintf(bw, "Min <= %5.1f: %3d\n", th.minCold, s.minCold)
+		fmt.Fprintf(bw, "Min <= %5.1f: %3d\n", th.minVeryCold, s.minVeryCold)
+		fmt.Fprintf(bw, "Max Rain: %.*f on day %d\n", rp, s.rainHi, s.rainHiDay.Day())
+		fmt.Fprintf(bw, "Days of Rain: %d (>= %g %s) %d (>= %g %s) %d (>= %g %s)\n",
+			s.rainDays[0], th.rain[0], u.Rain, s.rainDays[1], th.rain[1], u.Rain, s.rainDays[2], th.rain[2], u.Rain)
+	}
+
+	return bw.Flush()
+}
+
+// WriteYear writes the summary for the year of year, using the summaries of the days in the year.
+func WriteYear(w io.Writer, year time.Time, days []*model.DailySummary, o Options) error {
+	bw := bufio.NewWriter(w)
+	th := o.thresholds()
+	u := o.Units
+	rp := o.rainPrec()
+
+	var (
+		total  summary
+		months [12]summary
+	)
+	for _, ds := range days {
+		total.add(ds, o, th)
+		months[ds.Date.Month()-1].add(ds, o, th)
+	}
+
+	writeHeader(bw, fmt.Sprintf("ANNUAL CLIMATOLOGICAL SUMMARY for %d", year.Year()), o)
+
+	fmt.Fprintf(bw, "%s\n\n", center(fmt.Sprintf("TEMPERATURE (°%s)", u.Temperature)))
+	fmt.Fprintln(bw, "                              HEAT   COOL                          MAX    MAX    MIN    MIN")
+	fmt.Fprintln(bw, "         MEAN   MEAN           DEG    DEG                          >=     <=     <=     <=")
+	fmt.Fprintf(bw, " YR  MO   MAX    MIN   MEAN   DAYS   DAYS   HIGH  DATE    LOW  DATE %6.1f %6.1f %6.1f %6.1f\n",
+		th.maxHot, th.maxCold, th.minCold, th.minVeryCold)
+	fmt.Fprintln(bw, rule)
+	for i, s := range months {
+		fmt.Fprintf(bw, "%3d %3d", year.Year()%100, i+1)
+		if s.days > 0 {
+			fmt.Fprintf(bw, " %6.1f %6.1f %6.1f %6.1f %6.1f %6.1f %5d %6.1f %5d %6d %6d %6d %6d",
+				s.max(), s.min(), s.mean(), s.hdd, s.cdd, s.high, s.highDay.Day(), s.low, s.lowDay.Day(),
+				s.maxHot, s.maxCold, s.minCold, s.minVeryCold)
+		}
+		fmt.Fprintln(bw)
+	}
+	fmt.Fprintln(bw, rule)
+	if total.days > 0 {
+		fmt.Fprintf(bw, "        %6.1f %6.1f %6.1f %6.1f %6.1f %6.1f %5s %6.1f %5s %6d %6d %6d %6d\n",
+			total.max(), total.min(), total.mean(), total.hdd, total.cdd, total.high, total.highDay.Format("Jan"),
+			total.low, total.lowDay.Format("Jan"), total.maxHot, total.maxCold, total.minCold, total.minVeryCold)
+	}
+
+	fmt.Fprintf(bw, "\n\n%s\n\n", center(fmt.Sprintf("PRECIPITATION (%s)", u.Rain)))
+	fmt.Fprintln(bw, "                   MAX          ---DAYS OF RAIN---")
+	fmt.Fprintln(bw, "                   OBS.                OVER")
+	fmt.Fprintf(bw, " YR  MO  TOTAL    DAY  DATE %6g %6g %6g\n", th.rain[0], th.rain[1], th.rain[2])
+	fmt.Fprintln(bw, rule)
+	for i, s := range months {
+		fmt.Fprintf(bw, "%3d %3d", year.Year()%100, i+1)
+		if s.days > 0 {
+			fmt.Fprintf(bw, " %6.*f %6.*f %5d %6d %6d %6d", rp, s.rain, rp, s.rainHi, s.rainHiDay.Day(),
+				s.rainDays[0], s.rainDays[1], s.rainDays[2])
+		}
+		fmt.Fprintln(bw)
+	}
+	fmt.Fprintln(bw, rule)
+	if total.days > 0 {
+		fmt.Fprintf(bw, "        %6.*f %6.*f %5s %6d %6d %6d\n", rp, total.rain, rp, total.rainHi, total.rainHiDay.Format("Jan"),
+			total.rainDays[0], total.rainDays[1], total.rainDays[2])
+	}
+
+	fmt.Fprintf(bw, "\n\n%s\n\n", center(fmt.Sprintf("WIND SPEED (%s)", u.Speed)))
+	fmt.Fprintln(bw, "                                 DOM")
+	fmt.Fprintln(bw, " YR  MO    AVG   HIGH  DATE       DIR")
+	fmt.Fprintln(bw, rule)
+	for i, s := range months {
+		fmt.Fprintf(bw, "%3d %3d", year.Year()%100, i+1)
+		if s.days > 0 {
+			fmt.Fprintf(bw, " %6.1f %6.1f %5d %9s", s.avgWind(u.Speed), s.windHi, s.windHiDay.Day(), s.dominantDir())
+		}
+		fmt.Fprintln(bw)
+	}
+	fmt.Fprintln(bw, rule)
+	if total.days > 0 {
+		fmt.Fprintf(bw, "        %6.1f %6.1f %5s %9s\n", total.avgWind(u.Speed), total.windHi, total.windHiDay.Format("Jan"), total.dominantDir())
+	}
+
+	return bw.Flush()
+}
+
+const (
+	width = 88
+)
+
+var rule = strings.Repeat("-", width)
+
+func center(s string) string {
+	n := (width - len([]rune(s))) / 2
+	if n < 0 {
+		n = 0
+	}
+	return strings.Repeat(" ", n) + s
+}
+
+func writeHeader(w io.Writer, title string, o Options) {
+	st := o.Station
+	fmt.Fprintf(w, "%s\n\n\n", center(title))
+	fmt.Fprintf(w, "NAME: %s\n", st.Name)
+	fmt.Fprintf(w, "ELEV: %.0f m    LAT: %s    LONG: %s\n\n\n", st.Altitude, dms(st.Latitude, "N", "S"), dms(st.Longitude, "E", "W"))
+}
+
+// dms formats deg as degrees and decimal minutes, followed by the hemisphere.
+func dms(deg float64, pos, neg string) string {
+	h := pos
+	if deg < 0 {
+		h, deg = neg, -deg
+	}
+	d, m := math.Modf(deg)
+	return fmt.Sprintf("%03.0f-%05.2f %s", d, m*60, h)
+}
+
+// clock returns the time of day of t in loc, or an empty string for a zero time.
+func clock(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format("15:04")
+}
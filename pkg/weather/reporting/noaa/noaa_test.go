@@ -0,0 +1,74 @@
+package noaa
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSummaries(month time.Time, n int) []*model.DailySummary {
+	var days []*model.DailySummary
+	for i := 0; i < n; i++ {
+		date := month.AddDate(0, 0, i)
+		days = append(days, &model.DailySummary{
+			Date:              date,
+			TempHi:            unit.FromCelsius(15 + float64(i)),
+			TempHiTime:        date.Add(14 * time.Hour),
+			TempLo:            unit.FromCelsius(5 - float64(i)),
+			TempLoTime:        date.Add(6 * time.Hour),
+			TempAvg:           unit.FromCelsius(10),
+			Rain:              unit.Length(i) * unit.Millimeter,
+			WindGustHi:        unit.Speed(40+i) * unit.KilometersPerHour,
+			WindGustHiTime:    date.Add(15*time.Hour + 30*time.Minute),
+			WindRun:           240 * unit.Kilometer,
+			DominantWindDir:   315 * unit.Degree,
+			HeatingDegreeDays: 8,
+		})
+	}
+	return days
+}
+
+var testOptions = Options{
+	Station: Station{Name: "Launceston", Latitude: -41.44, Longitude: 147.23, Altitude: 180},
+	Units:   units.Metric,
+}
+
+func TestWriteMonth(t *testing.T) {
+	month := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMonth(&buf, month, testSummaries(month, 3), testOptions))
+	got := buf.String()
+
+	assert.Contains(t, got, "MONTHLY CLIMATOLOGICAL SUMMARY for Sep 2026")
+	assert.Contains(t, got, "LAT: 041-26.40 S    LONG: 147-13.80 E")
+	assert.Contains(t, got, "\n  1    10.0   15.0  14:00    5.0  06:00    8.0    0.0    0.0   10.0   40.0  15:30   NW\n")
+	assert.Contains(t, got, "\n 30\n")
+	assert.Contains(t, got, "\n       10.0   17.0      3    3.0      3   24.0    0.0    3.0   10.0   42.0      3   NW\n")
+	assert.Contains(t, got, "Days of Rain: 2 (>= 0.2 mm) 1 (>= 2 mm) 0 (>= 20 mm)")
+	assert.NotContains(t, got, "\n 31\n")
+}
+
+func TestWriteYear(t *testing.T) {
+	year := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := append(testSummaries(year, 2), testSummaries(year.AddDate(0, 6, 0), 1)...)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteYear(&buf, year, days, Options{Units: units.US}))
+	got := buf.String()
+
+	assert.Contains(t, got, "ANNUAL CLIMATOLOGICAL SUMMARY for 2026")
+	assert.Contains(t, got, "TEMPERATURE (°F)")
+	assert.Contains(t, got, "PRECIPITATION (in)")
+	assert.Contains(t, got, "WIND SPEED (mph)")
+	assert.Equal(t, 3, strings.Count(got, "\n 26   7 "), "one row for each section")
+	assert.Contains(t, got, "\n 26   2\n")
+	assert.Contains(t, got, "   59.6   40.4   50.0   43.2    0.0   60.8   Jan", "degree days in °F")
+}
@@ -0,0 +1,201 @@
+package reporting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+)
+
+// Anomalies are the departures of a day from the climate normals, and the percentile ranks of the
+// day amongst the archived days of the same month in any year. Temperature departures are encoded
+// as for Statistics.TempTrend. A value is NaN when it is not available.
+type Anomalies struct {
+	Normal *model.ClimateNormal // Normal is the normal of the day, or of its month, or nil when there is none
+
+	TempHi      unit.Temperature // maximum temperature above the mean maximum
+	TempLo      unit.Temperature // minimum temperature above the mean minimum
+	TempMean    unit.Temperature // mean temperature, the average of the maximum and minimum, above the normal mean
+	RainPercent float64          // rain as a percentage of the mean rain for the day
+
+	TempHiPercentile float64 // percentage of the days with a lower maximum temperature, counting equal days as half
+	TempLoPercentile float64 // percentage of the days with a lower minimum temperature, counting equal days as half
+	RainPercentile   float64 // percentage of the days with less rain, counting equal days as half
+}
+
+// Anomalies returns the anomalies of the maximum and minimum temperatures and rain of ds. The
+// normal of the day is preferred, and the normal of the month is used when there is none.
+func (r *Reporter) Anomalies(ds *model.DailySummary) (*Anomalies, error) {
+	normal, err := r.climateNormal(ds.Date)
+	if err != nil {
+		return nil, err
+	}
+	ranks, err := r.dailyRanks(ds.Date)
+	if err != nil {
+		return nil, err
+	}
+	return anomalies(ds, normal, ranks), nil
+}
+
+// anomalies returns the anomalies of ds from normal, and its percentile ranks amongst ranks.
+// Either may be nil, when the values which depend on it are NaN.
+func anomalies(ds *model.DailySummary, normal *model.ClimateNormal, ranks *dailyRanks) *Anomalies {
+	nan := math.NaN()
+	a := &Anomalies{
+		TempHi:           unit.FromCelsius(nan),
+		TempLo:           unit.FromCelsius(nan),
+		TempMean:         unit.FromCelsius(nan),
+		RainPercent:      nan,
+		TempHiPercentile: nan,
+		TempLoPercentile: nan,
+		RainPercentile:   nan,
+	}
+
+	if normal != nil {
+		a.Normal = normal
+
+		if normal.TempHiMean != 0 {
+			a.TempHi = unit.FromCelsius(ds.TempHi.Celsius() - normal.TempHiMean.Celsius())
+		}
+		if normal.TempLoMean != 0 {
+			a.TempLo = unit.FromCelsius(ds.TempLo.Celsius() - normal.TempLoMean.Celsius())
+		}
+		if normal.TempMean != 0 {
+			a.TempMean = unit.FromCelsius((ds.TempHi.Celsius()+ds.TempLo.Celsius())/2 - normal.TempMean.Celsius())
+		}
+
+		rain := normal.Rain
+		if normal.Day == 0 {
+			rain /= unit.Length(daysIn(ds.Date))
+		}
+		if rain > 0 {
+			a.RainPercent = 100 * float64(ds.Rain/rain)
+		}
+	}
+
+	if ranks != nil {
+		a.TempHiPercentile = percentileRank(ranks.tempHi, ds.TempHi.Celsius())
+		a.TempLoPercentile = percentileRank(ranks.tempLo, ds.TempLo.Celsius())
+		a.RainPercentile = percentileRank(ranks.rain, ds.Rain.Millimeters())
+	}
+
+	return a
+}
+
+// climateNormal returns the normal of the day of date, or of its month, or nil when there is none.
+func (r *Reporter) climateNormal(date time.Time) (*model.ClimateNormal, error) {
+	cn, err := r.store.ClimateNormal(date.Month(), date.Day())
+	if err != nil || cn != nil {
+		return cn, err
+	}
+	return r.store.ClimateNormal(date.Month(), 0)
+}
+
+// dailyRanks are the sorted maximum and minimum temperatures, in °C, and rain, in mm, of the
+// archived days of a month in any year, for the percentile ranks of a day.
+type dailyRanks struct {
+	tempHi, tempLo, rain []float64
+}
+
+// dailyRanks returns the ranks of the archived days of the month of date, excluding date.
+func (r *Reporter) dailyRanks(date time.Time) (*dailyRanks, error) {
+	var rows []struct {
+		TempHi, TempLo, Rain float64
+	}
+	tx := r.store.DB().Model(&store.DailySummary{}).
+		Where("strftime('%m', date) = ? AND date <> ?", fmt.Sprintf("%02d", date.Month()), sqlite.DateFromTime(date)).
+		Select("temp_hi_c AS temp_hi, temp_lo_c AS temp_lo, rain_mm AS rain").
+		Scan(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ranks := &dailyRanks{
+		tempHi: make([]float64, 0, len(rows)),
+		tempLo: make([]float64, 0, len(rows)),
+		rain:   make([]float64, 0, len(rows)),
+	}
+	for _, row := range rows {
+		ranks.tempHi = append(ranks.tempHi, row.TempHi)
+		ranks.tempLo = append(ranks.tempLo, row.TempLo)
+		ranks.rain = append(ranks.rain, row.Rain)
+	}
+	sort.Float64s(ranks.tempHi)
+	sort.Float64s(ranks.tempLo)
+	sort.Float64s(ranks.rain)
+
+	return ranks, nil
+}
+
+// percentileRank returns the percentage of the sorted values which are less than v, counting
+// the values equal to v as half, or NaN when there are none.
+func percentileRank(values []float64, v float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	below := sort.SearchFloat64s(values, v)
+	equal := sort.Search(len(values), func(i int) bool { return values[i] > v }) - below
+	return 100 * (float64(below) + float64(equal)/2) / float64(len(values))
+}
+
+func (r *Reporter) calcAnomalies(ts time.Time, snap *snapshot, prior *priorStatistics, s *Statistics) {
+	nan := math.NaN()
+	s.TodayTempHiAnomaly, s.TodayTempLoAnomaly = unit.FromCelsius(nan), unit.FromCelsius(nan)
+	s.TodayTempHiPercentile, s.TodayTempLoPercentile = nan, nan
+	s.MonthTempAnomaly, s.MonthRainfallPercent = unit.FromCelsius(nan), nan
+
+	if !s.TodayTempHiTime.IsZero() {
+		a := anomalies(&model.DailySummary{
+			Date:   r.cal.Date(ts),
+			TempHi: s.TodayTempHi,
+			TempLo: s.TodayTempLo,
+			Rain:   s.RainfallToday,
+		}, prior.dayNormal, prior.ranks)
+		s.TodayTempHiAnomaly, s.TodayTempLoAnomaly = a.TempHi, a.TempLo
+		s.TodayTempHiPercentile, s.TodayTempLoPercentile = a.TempHiPercentile, a.TempLoPercentile
+	}
+
+	normal := prior.monthNormal
+	if normal == nil {
+		return
+	}
+	if normal.TempMean != 0 {
+		sum, n := prior.monthTempSum, prior.monthTempDays
+		if lo, hi, ok := r.todayTempRange(ts, snap); ok {
+			sum += (hi.Celsius() + lo.Celsius()) / 2
+			n++
+		}
+		if n > 0 {
+			s.MonthTempAnomaly = unit.FromCelsius(sum/float64(n) - normal.TempMean.Celsius())
+		}
+	}
+	if normal.Rain > 0 {
+		s.MonthRainfallPercent = 100 * float64(s.MonthlyRainfall/normal.Rain)
+	}
+}
+
+// dailyMeanTempsSince returns the sum of the daily mean temperatures, the average of the maximum and minimum,
+// in °C, from the start of a meteorological day up to, but excluding, end, and the number of days.
+func (r *Reporter) dailyMeanTempsSince(start, end time.Time) (sum float64, n int) {
+	r.eachDaySince(start, end, func(ds *model.DailySummary) {
+		sum += (ds.TempHi.Celsius() + ds.TempLo.Celsius()) / 2
+		n++
+	}, func(day, next time.Time) {
+		if lo, hi, ok := r.tempRange(day, next); ok {
+			sum += (hi.Celsius() + lo.Celsius()) / 2
+			n++
+		}
+	})
+
+	return sum, n
+}
+
+// daysIn returns the number of days in the month of t.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,55 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+// priorStatistics are the statistics of the days prior to a meteorological day, which the reports
+// of the day add to the statistics of the day itself. When the statistics are maintained incrementally,
+// they are read once, as the day starts.
+type priorStatistics struct {
+	yesterdayRain                   unit.Length
+	monthRain, seasonRain, yearRain unit.Length
+
+	// degree days of the degree day season
+	hdd, cdd, gdd float64
+
+	// sum of the daily mean temperatures of the month, in °C, and the number of days
+	monthTempSum  float64
+	monthTempDays int
+
+	monthNormal *model.ClimateNormal // normal of the month, or nil
+	dayNormal   *model.ClimateNormal // normal of the day, or of its month, or nil
+	ranks       *dailyRanks          // archived days of the month, or nil
+}
+
+// priorStatistics reads the statistics of the days prior to the meteorological day starting at today.
+func (r *Reporter) priorStatistics(today time.Time) *priorStatistics {
+	date := r.cal.Date(today)
+	month := r.cal.Start(beginningOfMonth(date))
+
+	p := &priorStatistics{
+		yesterdayRain: r.rainfallSince(r.cal.BeginningOfDay(today.Add(-time.Second)), today),
+		monthRain:     r.rainfallSince(month, today),
+		seasonRain:    r.rainfallSince(r.cal.Start(beginningOfSeason(date)), today),
+		yearRain:      r.rainfallSince(r.cal.Start(r.beginningOfRainYear(date)), today),
+	}
+	p.hdd, p.cdd, p.gdd = r.degreeDaysSince(r.cal.Start(r.beginningOfDegreeDaySeason(date)), today)
+	p.monthTempSum, p.monthTempDays = r.dailyMeanTempsSince(month, today)
+
+	var err error
+	if p.monthNormal, err = r.store.ClimateNormal(date.Month(), 0); err != nil {
+		r.log.Warn("Unable to read climate normals.")
+	}
+	if p.dayNormal, err = r.climateNormal(date); err != nil {
+		r.log.Warn("Unable to read climate normals.")
+	}
+	if p.ranks, err = r.dailyRanks(date); err != nil {
+		r.log.Warn("Unable to read daily summaries.")
+	}
+
+	return p
+}
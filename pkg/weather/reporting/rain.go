@@ -0,0 +1,110 @@
+package reporting
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+)
+
+func (r *Reporter) calcRainfall(ts time.Time, snap *snapshot, prior *priorStatistics, s *Statistics) {
+	if snap != nil {
+		s.RainfallLastHour = snap.rainLastHour
+		s.RainfallLast24Hours = snap.rainLast24Hours
+		s.RainfallToday = snap.rainToday
+	} else {
+		// include the observation at ts
+		end := ts.Add(time.Second)
+		s.RainfallLastHour = r.rainfall(ts.Add(-time.Hour), end)
+		s.RainfallLast24Hours = r.rainfall(ts.Add(-24*time.Hour), end)
+		s.RainfallToday = r.rainfall(r.cal.BeginningOfDay(ts), end)
+	}
+	s.YesterdayRainfall = prior.yesterdayRain
+	s.MonthlyRainfall = prior.monthRain + s.RainfallToday
+	s.SeasonRainfall = prior.seasonRain + s.RainfallToday
+	s.YearlyRainfall = prior.yearRain + s.RainfallToday
+}
+
+// Rainfall returns the rain of the last hour and of the meteorological day up to, and including, ts,
+// from the increments of the total rain counter, as for the reports, rather than the counters of the console.
+func (r *Reporter) Rainfall(ts time.Time) (lastHour, today unit.Length) {
+	ts = r.cal.In(ts)
+	start := r.cal.BeginningOfDay(ts)
+	if r.rolling != nil {
+		if lastHour, today, ok := r.rolling.Rainfall(ts, start); ok {
+			return lastHour, today
+		}
+	}
+
+	end := ts.Add(time.Second)
+	return r.rainfall(ts.Add(-time.Hour), end), r.rainfall(start, end)
+}
+
+// rainfall returns the rain from start up to, but excluding, end, as the sum of the increments
+// of the total rain counter between consecutive observations. The first increment is relative
+// to the last observation prior to start, when available.
+//
+// An increment is ignored when the counter decreases, such as when the console is restarted or the
+// rain gauge is replaced, or when it exceeds the maximum increment, which is considered a spike.
+func (r *Reporter) rainfall(start, end time.Time) unit.Length {
+	db := r.store.DB()
+
+	prev := db.Model(&store.Observation{}).
+		Where("timestamp < ?", sqlite.FromTime(start)).
+		Select("MAX(timestamp)")
+
+	increments := db.Model(&store.Observation{}).
+		Where("timestamp >= COALESCE((?), ?) AND timestamp < ?", prev, sqlite.FromTime(start), sqlite.FromTime(end)).
+		Select("timestamp, total_rain_mm - lag(total_rain_mm) over (order by timestamp) as increment")
+
+	var res sql.NullFloat64
+	db.Table("(?) as increments", increments).
+		Where("timestamp >= ? AND increment > 0 AND increment <= ?", sqlite.FromTime(start), r.rain.MaxIncrement).
+		Select("SUM(increment)").
+		Find(&res)
+
+	return unit.Length(res.Float64) * unit.Millimeter
+}
+
+// rainfallSince returns the rain from the start of a meteorological day up to, but excluding,
+// end. The daily summaries are used for the complete days, as the observations are removed
+// once archived, and the observations for the remainder.
+func (r *Reporter) rainfallSince(start, end time.Time) unit.Length {
+	var total unit.Length
+
+	summaries, err := r.store.DailySummaries(r.cal.Date(start), r.cal.Date(end))
+	if err != nil {
+		r.log.Warn("Unable to read daily summaries.")
+	}
+	for _, ds := range summaries {
+		total += ds.Rain
+	}
+	if n := len(summaries); n > 0 {
+		start = r.cal.Start(summaries[n-1].Date.AddDate(0, 0, 1))
+	}
+
+	return total + r.rainfall(start, end)
+}
+
+func beginningOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// beginningOfSeason returns the first day of the meteorological season containing t,
+// which start on the first of March, June, September and December.
+func beginningOfSeason(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m-m%3, 1, 0, 0, 0, 0, t.Location())
+}
+
+// beginningOfRainYear returns the first day of the rain year containing t.
+func (r *Reporter) beginningOfRainYear(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	if m < r.rain.YearStartMonth {
+		y--
+	}
+	return time.Date(y, r.rain.YearStartMonth, 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,72 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/martinlindhe/unit"
+)
+
+// Records contains the extremes of a period, and the times they occurred.
+// A time is zero when the record is not available.
+type Records struct {
+	TempHi          unit.Temperature
+	TempHiTime      time.Time
+	TempLo          unit.Temperature
+	TempLoTime      time.Time
+	PressureHi      unit.Pressure
+	PressureHiTime  time.Time
+	PressureLo      unit.Pressure
+	PressureLoTime  time.Time
+	WindSpeedHi     unit.Speed
+	WindSpeedHiTime time.Time
+	WindGustHi      unit.Speed
+	WindGustHiTime  time.Time
+	RainRateHi      unit.Length
+	RainRateHiTime  time.Time
+	RainHi          unit.Length // RainHi is the highest daily rainfall
+	RainHiDate      time.Time
+}
+
+// Records returns the records of the archived daily summaries for the dates from start
+// up to, but excluding, end. Use Include to include the statistics of the current day.
+func (r *Reporter) Records(start, end time.Time) (*Records, error) {
+	summaries, err := r.store.DailySummaries(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Records{}
+	for _, ds := range summaries {
+		rec.includeHi(&rec.TempHiTime, ds.TempHiTime, ds.TempHi > rec.TempHi, func() { rec.TempHi = ds.TempHi })
+		rec.includeHi(&rec.TempLoTime, ds.TempLoTime, ds.TempLo < rec.TempLo, func() { rec.TempLo = ds.TempLo })
+		rec.includeHi(&rec.PressureHiTime, ds.PressureHiTime, ds.PressureHi > rec.PressureHi, func() { rec.PressureHi = ds.PressureHi })
+		rec.includeHi(&rec.PressureLoTime, ds.PressureLoTime, ds.PressureLo < rec.PressureLo, func() { rec.PressureLo = ds.PressureLo })
+		rec.includeHi(&rec.WindSpeedHiTime, ds.WindSpeedHiTime, ds.WindSpeedHi > rec.WindSpeedHi, func() { rec.WindSpeedHi = ds.WindSpeedHi })
+		rec.includeHi(&rec.WindGustHiTime, ds.WindGustHiTime, ds.WindGustHi > rec.WindGustHi, func() { rec.WindGustHi = ds.WindGustHi })
+		rec.includeHi(&rec.RainRateHiTime, ds.RainRateHiTime, ds.RainRateHi > rec.RainRateHi, func() { rec.RainRateHi = ds.RainRateHi })
+		rec.includeHi(&rec.RainHiDate, ds.Date, ds.Rain > rec.RainHi, func() { rec.RainHi = ds.Rain })
+	}
+	return rec, nil
+}
+
+// Include updates the records with the extremes of the current day of s.
+func (rec *Records) Include(s *Statistics) {
+	rec.includeHi(&rec.TempHiTime, s.TodayTempHiTime, s.TodayTempHi > rec.TempHi, func() { rec.TempHi = s.TodayTempHi })
+	rec.includeHi(&rec.TempLoTime, s.TodayTempLoTime, s.TodayTempLo < rec.TempLo, func() { rec.TempLo = s.TodayTempLo })
+	rec.includeHi(&rec.PressureHiTime, s.TodayPressureHiTime, s.TodayPressureHi > rec.PressureHi, func() { rec.PressureHi = s.TodayPressureHi })
+	rec.includeHi(&rec.PressureLoTime, s.TodayPressureLoTime, s.TodayPressureLo < rec.PressureLo, func() { rec.PressureLo = s.TodayPressureLo })
+	rec.includeHi(&rec.WindSpeedHiTime, s.TodayWindHiTime, s.TodayWindHi > rec.WindSpeedHi, func() { rec.WindSpeedHi = s.TodayWindHi })
+	rec.includeHi(&rec.WindGustHiTime, s.TodayWindGustHiTime, s.TodayWindGustHi > rec.WindGustHi, func() { rec.WindGustHi = s.TodayWindGustHi })
+	rec.includeHi(&rec.RainRateHiTime, s.TodayRainRateHiTime, s.TodayRainRateHi > rec.RainRateHi, func() { rec.RainRateHi = s.TodayRainRateHi })
+	rec.includeHi(&rec.RainHiDate, s.Date, s.RainfallToday > rec.RainHi, func() { rec.RainHi = s.RainfallToday })
+}
+
+// includeHi sets the record, using set, and its time when the record is not available or beaten.
+// Values without a time are ignored.
+func (rec *Records) includeHi(recTime *time.Time, t time.Time, beaten bool, set func()) {
+	if t.IsZero() || (!recTime.IsZero() && !beaten) {
+		return
+	}
+	set()
+	*recTime = t
+}
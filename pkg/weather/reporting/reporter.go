@@ -6,15 +6,18 @@ import (
 	"math"
 	"time"
 
-	"github.com/jinzhu/now"
 	"github.com/kelvins/sunrisesunset"
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
 	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
 	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/lmacrc/weather/pkg/xunit"
 	"github.com/martinlindhe/unit"
-	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -24,28 +27,87 @@ type Reporter struct {
 	barometricType BarometricMeasurementType
 	barometricCol  string
 	lat, long      float64
+	site           meteorology.Site
+	solar          SolarConfig
+	rain           RainConfig
+	pressure       PressureConfig
+	degreeDays     DegreeDaysConfig
+	feelsLike      FeelsLikeConfig
+	sensorTimeout  time.Duration
+	cal            *calendar.Calendar
+	units          units.System
+	rolling        *rolling
 }
 
-func New(log *zap.Logger, vp *viper.Viper, store *store.Store) (*Reporter, error) {
-	var cfg Config
-	if err := vp.UnmarshalKey("reporting", &cfg, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
-		return nil, fmt.Errorf("config: %w", err)
+type optionFn func(r *Reporter)
+
+// WithRollingStatistics maintains the statistics of the realtime report incrementally,
+// from the observations published to bus, rather than querying the observations for each report.
+func WithRollingStatistics(bus *event.Bus) optionFn {
+	if bus == nil {
+		panic("bus == nil")
+	}
+
+	return func(r *Reporter) {
+		r.rolling = newRolling(r)
+		// subscribe first, so no observation is missed whilst warming
+		bus.MustSubscribe(store.NewObservation, r.rolling.HandleObservation)
+		r.rolling.Warm(time.Now())
+	}
+}
+
+func New(log *zap.Logger, vp *viper.Viper, store *store.Store, opts ...optionFn) (*Reporter, error) {
+	cfg, err := ReadConfig(vp)
+	if err != nil {
+		return nil, err
 	}
 
 	loc := struct {
-		Latitude, Longitude float64
+		Latitude, Longitude, Altitude float64
 	}{}
 
 	if err := vp.UnmarshalKey("location", &loc); err != nil {
 		return nil, fmt.Errorf("config: %w", err)
 	}
 
+	if cfg.Rain.YearStartMonth < time.January || cfg.Rain.YearStartMonth > time.December {
+		return nil, fmt.Errorf("config: invalid rain year_start_month %d: expect 1 through 12", cfg.Rain.YearStartMonth)
+	}
+
+	if dd := cfg.DegreeDays; dd.StartMonth < time.January || dd.StartMonth > time.December || dd.StartDay < 1 || dd.StartDay > 28 {
+		return nil, fmt.Errorf("config: invalid degree_days start %d-%d: expect month 1 through 12 and day 1 through 28", dd.StartMonth, dd.StartDay)
+	}
+
+	sys, err := cfg.Units.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	cal, err := calendar.New(vp)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &Reporter{
 		log:            log.With(zap.String("service", "reporter")),
 		store:          store,
 		barometricType: cfg.BarometricMeasurement,
 		lat:            loc.Latitude,
 		long:           loc.Longitude,
+		site: meteorology.Site{
+			Latitude:         loc.Latitude,
+			Longitude:        loc.Longitude,
+			Altitude:         loc.Altitude,
+			AnemometerHeight: cfg.AnemometerHeight,
+		},
+		solar:         cfg.Solar,
+		rain:          cfg.Rain,
+		pressure:      cfg.Pressure,
+		degreeDays:    cfg.DegreeDays,
+		feelsLike:     cfg.FeelsLike,
+		sensorTimeout: cfg.SensorTimeout,
+		cal:           cal,
+		units:         sys,
 	}
 
 	switch cfg.BarometricMeasurement {
@@ -57,52 +119,92 @@ func New(log *zap.Logger, vp *viper.Viper, store *store.Store) (*Reporter, error
 		r.barometricCol = "barometric_rel_hpa"
 	}
 
+	for _, fn := range opts {
+		fn(r)
+	}
+
 	return r, nil
 }
 
+// Units returns the unit system for rendering reports.
+func (r *Reporter) Units() units.System { return r.units }
+
+// Location returns the time zone of the station.
+func (r *Reporter) Location() *time.Location { return r.cal.Location() }
+
 func (r *Reporter) Generate(ts time.Time) *Statistics {
 	r.log.Info("Starting report generation.")
 
+	// report the times of the statistics in the time zone of the station
+	ts = r.cal.In(ts)
+
 	s := &Statistics{
 		Timestamp:          ts,
-		WindUnits:          "km/h",
-		TempUnits:          "C",
-		PressureUnits:      "hPa",
-		RainUnits:          "mm",
+		Date:               r.cal.Date(ts),
+		WindUnits:          string(r.units.Speed),
+		TempUnits:          string(r.units.Temperature),
+		PressureUnits:      r.units.Pressure.Label(),
+		RainUnits:          string(r.units.Rain),
 		CloudBaseUnits:     "m",
 		CumulusVersion:     "1.8.2",
 		CumulusBuildNumber: 1,
 	}
 
-	r.calcLastObservation(ts, s)
+	// the statistics which are maintained incrementally, when available
+	var snap *snapshot
+	if r.rolling != nil {
+		snap = r.rolling.Snapshot(ts, r.cal.BeginningOfDay(ts))
+	}
+	// the statistics of the days prior to the meteorological day, which are read once for the day when available
+	var prior *priorStatistics
+	if snap != nil {
+		prior = snap.prior
+	} else {
+		prior = r.priorStatistics(r.cal.BeginningOfDay(ts))
+	}
+
+	r.calcLastObservation(ts, snap, s)
 	r.calcDewPoint(ts, s)
 	r.calcWindDirection(ts, s)
+	r.calcWindRun(ts, snap, s)
+	r.calcTrends(ts, snap, s)
+	r.calcPressureTendency(ts, snap, s)
+	r.calcLimitsForCurrent24HourPeriod(ts, snap, s)
+	r.calcTenMinuteStats(ts, snap, s)
 	r.calcWindForce(ts, s)
-	r.calcWindRun(ts, s)
-	r.calcTrends(ts, s)
-	r.calcLimitsForCurrent24HourPeriod(ts, s)
-	r.calcTenMinuteStats(ts, s)
 	r.calcIndices(ts, s)
-	r.calcRainfall(ts, s)
+	r.calcRainfall(ts, snap, prior, s)
+	r.calcDegreeDays(ts, snap, prior, s)
+	r.calcAnomalies(ts, snap, prior, s)
 	r.calcIsDaylight(ts, s)
+	r.calcSolar(ts, snap, s)
 	r.calcApparentTemp(ts, s)
+	r.calcEvapotranspiration(ts, snap, s)
+	r.calcForecast(ts, s)
 
 	r.log.Info("Completed report generation.")
 
 	return s
 }
 
-func (r *Reporter) calcLastObservation(ts time.Time, s *Statistics) {
-	o := r.store.LastObservation(ts.UTC())
+func (r *Reporter) calcLastObservation(ts time.Time, snap *snapshot, s *Statistics) {
+	var o *model.Observation
+	if snap != nil {
+		o = snap.last
+	} else {
+		o = r.store.LastObservation(ts.UTC())
+	}
+	if o == nil {
+		s.SensorContactLost = true
+		return
+	}
+
+	s.SensorContactLost = ts.Sub(o.Timestamp) > r.sensorTimeout
 	if r.barometricType == BarometricMeasurementTypeAbsolute {
 		s.BarometricPressure = o.BarometricAbs
 	} else {
 		s.BarometricPressure = o.BarometricRel
 	}
-	s.RainfallLastHour = o.HourlyRain
-	s.RainfallToday = o.DailyRain
-	s.MonthlyRainfall = o.MonthlyRain
-	s.YearlyRainfall = o.TotalRain
 	s.RainRate = o.RainRatePerHour
 	s.OutdoorHumidity = o.HumidityOutdoor
 	s.IndoorHumidity = o.HumidityIndoor
@@ -126,10 +228,19 @@ func (r *Reporter) calcWindForce(_ time.Time, s *Statistics) {
 	s.WindForce = meteorology.SpeedToWindForce(s.WindSpeedAvg).ToInt()
 }
 
-func (r *Reporter) calcWindRun(ts time.Time, s *Statistics) {
+func (r *Reporter) calcWindRun(ts time.Time, snap *snapshot, s *Statistics) {
+	if snap != nil {
+		s.WindRun = snap.windRun
+		return
+	}
+	s.WindRun = r.windRun(r.cal.Day(ts))
+}
+
+// windRun returns the distance travelled by the wind for the observations from start up to, but excluding, end.
+func (r *Reporter) windRun(start, end time.Time) unit.Length {
 	db := r.store.DB()
-	subQuery := db.Scopes(last24Hours(ts)).
-		Model(&store.Observation{}).
+	subQuery := db.Model(&store.Observation{}).
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
 		Select(
 			"strftime('%s', timestamp) - lag(strftime('%s', timestamp), -1) over (order by timestamp desc) as diff_secs",
 			"wind_speed_kph * 0.277778 as \"wind_speed_mps\"",
@@ -139,12 +250,62 @@ func (r *Reporter) calcWindRun(ts time.Time, s *Statistics) {
 		Where("diff_secs IS NOT NULL").
 		Select("SUM(diff_secs * wind_speed_mps) as wind_run_m").
 		Find(&windRunMetres)
-	s.WindRun = unit.Length(windRunMetres) * unit.Meter
+	return unit.Length(windRunMetres) * unit.Meter
+}
+
+func (r *Reporter) calcTrends(ts time.Time, snap *snapshot, s *Statistics) {
+	var pressure, temp float64
+	if snap != nil {
+		pressure, temp = snap.pressureTrend, snap.tempTrend
+	} else {
+		pressure = r.calcLinearRegression(r.barometricCol, ts, -trendPeriod)
+		temp = r.calcLinearRegression("temp_outdoor_c", ts, -trendPeriod)
+	}
+	s.PressureTrend = unit.Pressure(pressure) * unit.Hectopascal
+	s.TempTrend = unit.FromCelsius(temp)
+}
+
+// calcPressureTendency classifies the pressure tendency over the last three hours from the mean
+// pressures of the ten minutes prior to the start, middle and end of the period.
+func (r *Reporter) calcPressureTendency(ts time.Time, snap *snapshot, s *Statistics) {
+	s.PressureTendency = meteorology.PressureCharacteristicNone
+
+	var p [3]unit.Pressure
+	if snap != nil {
+		if !snap.pressureTendencyValid {
+			return
+		}
+		for i, v := range snap.pressureTendency {
+			p[i] = unit.Pressure(v) * unit.Hectopascal
+		}
+	} else {
+		for i := range p {
+			end := pressureTendencyEnd(ts, i)
+			var res struct {
+				Count int64
+				Mean  float64
+			}
+			r.store.DB().Model(&store.Observation{}).
+				Where("timestamp > ? AND timestamp <= ?", sqlite.FromTime(end.Add(-tenMinutePeriod)), sqlite.FromTime(end)).
+				Select("COUNT(" + r.barometricCol + ") AS count, COALESCE(AVG(" + r.barometricCol + "), 0) AS mean").
+				Scan(&res)
+			if res.Count == 0 {
+				return
+			}
+			p[i] = unit.Pressure(res.Mean) * unit.Hectopascal
+		}
+	}
+
+	s.PressureChange = p[2] - p[0]
+	s.PressureTendency = meteorology.PressureTendency(p[0], p[1], p[2])
+	s.PressureTendencyText = meteorology.PressureChangeText(s.PressureChange)
+	s.PressureFallWarning = r.pressure.FallWarning > 0 && -s.PressureChange.Hectopascals() >= r.pressure.FallWarning
 }
 
-func (r *Reporter) calcTrends(ts time.Time, s *Statistics) {
-	s.PressureTrend = unit.Pressure(r.calcLinearRegression(r.barometricCol, ts, -3*time.Hour)) * unit.Hectopascal
-	s.TempTrend = unit.FromCelsius(r.calcLinearRegression("temp_outdoor_c", ts, -3*time.Hour))
+// pressureTendencyEnd returns the end of the i-th of the periods of the pressure tendency at ts,
+// which are at the start, middle and end of the trend period.
+func pressureTendencyEnd(ts time.Time, i int) time.Time {
+	return ts.Add(-trendPeriod * time.Duration(2-i) / 2)
 }
 
 func (r *Reporter) calcLinearRegression(col string, now time.Time, d time.Duration) float64 {
@@ -156,7 +317,7 @@ func (r *Reporter) calcLinearRegression(col string, now time.Time, d time.Durati
 	// independent variable: timestamp (seconds)
 
 	subQuery := db.Model(&store.Observation{}).
-		Where("timestamp >= ? AND timestamp <= ?", start.UTC(), end.UTC()).
+		Where("timestamp >= ? AND timestamp <= ?", sqlite.FromTime(start), sqlite.FromTime(end)).
 		Select("AVG("+col+") over () as ybar, "+col+" as y, AVG((STRFTIME('%s', timestamp) - @start)) OVER () as xbar, (STRFTIME('%s', timestamp) - @start) as x",
 			sql.Named("start", start.Unix()))
 
@@ -173,15 +334,29 @@ func (r *Reporter) calcLinearRegression(col string, now time.Time, d time.Durati
 	return 0
 }
 
-func (r *Reporter) calcRainfall(ts time.Time, s *Statistics) {
-	// limit for previous hour, as hourly_rain_mm resets to zero after each hour
-	_, val := r.calcLimitAndTimeForPeriod("hourly_rain_mm", limitMax, now.With(ts).BeginningOfHour(), -1*time.Hour)
-	s.RainfallLastHour = unit.Length(val) * unit.Millimeter
-}
+func (r *Reporter) calcLimitsForCurrent24HourPeriod(ts time.Time, snap *snapshot, s *Statistics) {
+	if snap != nil {
+		loc := ts.Location()
+		var val float64
+		s.TodayTempHiTime, val = snap.tempHi.value(loc)
+		s.TodayTempHi = unit.FromCelsius(val)
+		s.TodayTempLoTime, val = snap.tempLo.value(loc)
+		s.TodayTempLo = unit.FromCelsius(val)
+		s.TodayWindHiTime, val = snap.windHi.value(loc)
+		s.TodayWindHi = unit.Speed(val) * unit.KilometersPerHour
+		s.TodayWindGustHiTime, val = snap.gustHi.value(loc)
+		s.TodayWindGustHi = unit.Speed(val) * unit.KilometersPerHour
+		s.TodayPressureHiTime, val = snap.pressureHi.value(loc)
+		s.TodayPressureHi = unit.Pressure(val) * unit.Hectopascal
+		s.TodayPressureLoTime, val = snap.pressureLo.value(loc)
+		s.TodayPressureLo = unit.Pressure(val) * unit.Hectopascal
+		s.TodayRainRateHiTime, val = snap.rainRateHi.value(loc)
+		s.TodayRainRateHi = unit.Length(val) * unit.Millimeter
+		return
+	}
 
-func (r *Reporter) calcLimitsForCurrent24HourPeriod(ts time.Time, s *Statistics) {
-	start := now.With(ts).BeginningOfDay()
-	dur := 24 * time.Hour
+	start, end := r.cal.Day(ts)
+	dur := end.Sub(start)
 	var val float64
 
 	s.TodayTempHiTime, val = r.calcLimitAndTimeForPeriod("temp_outdoor_c", limitMax, start, dur)
@@ -196,6 +371,8 @@ func (r *Reporter) calcLimitsForCurrent24HourPeriod(ts time.Time, s *Statistics)
 	s.TodayPressureHi = unit.Pressure(val) * unit.Hectopascal
 	s.TodayPressureLoTime, val = r.calcLimitAndTimeForPeriod(r.barometricCol, limitMin, start, dur)
 	s.TodayPressureLo = unit.Pressure(val) * unit.Hectopascal
+	s.TodayRainRateHiTime, val = r.calcLimitAndTimeForPeriod("rain_rate_per_hour_mm", limitMax, start, dur)
+	s.TodayRainRateHi = unit.Length(val) * unit.Millimeter
 }
 
 type limit int
@@ -219,7 +396,7 @@ func (r *Reporter) calcLimitAndTimeForPeriod(col string, limit limit, now time.T
 	}
 
 	var res struct {
-		Timestamp time.Time
+		Timestamp sqlite.Timestamp
 		Value     float64
 	}
 	order := clause.OrderByColumn{Column: clause.Column{Name: col}}
@@ -228,19 +405,29 @@ func (r *Reporter) calcLimitAndTimeForPeriod(col string, limit limit, now time.T
 	}
 
 	r.store.DB().Model(&store.Observation{}).
-		Where("timestamp >= ? AND timestamp <= ?", start.UTC(), end.UTC()).
+		Where("timestamp >= ? AND timestamp <= ?", sqlite.FromTime(start), sqlite.FromTime(end)).
 		Select("timestamp, " + col + " as value").
 		Order(order).Order("timestamp").
 		Limit(1).
 		Find(&res)
 
+	if res.Timestamp.IsZero() {
+		return time.Time{}, res.Value
+	}
 	return res.Timestamp.In(now.Location()), res.Value
 }
 
-func (r *Reporter) calcTenMinuteStats(ts time.Time, s *Statistics) {
-	s.TenMinGustHi = unit.Speed(r.calcStatForPeriod("wind_gust_kph", "MAX", ts, -10*time.Minute)) * unit.KilometersPerHour
-	s.TenMinWindBearingAvg = unit.Angle(r.calcStatForPeriod("wind_dir_deg", "AVG", ts, -10*time.Minute)) * unit.Degree
-	s.WindSpeedAvg = unit.Speed(r.calcStatForPeriod("wind_speed_kph", "MAX", ts, -10*time.Minute)) * unit.KilometersPerHour
+func (r *Reporter) calcTenMinuteStats(ts time.Time, snap *snapshot, s *Statistics) {
+	var ws meteorology.WindStatistics
+	if snap != nil {
+		ws = snap.tenMinWind
+	} else {
+		// include the observation at ts
+		ws = r.WindStatistics(ts.Add(-tenMinutePeriod), ts.Add(time.Second))
+	}
+	s.TenMinGustHi = ws.MaxGust
+	s.TenMinWindBearingAvg = ws.VectorMeanDirection
+	s.WindSpeedAvg = ws.ScalarMeanSpeed
 	s.WindDirectionAvg = meteorology.CardinalDirection(s.TenMinWindBearingAvg.Degrees())
 }
 
@@ -257,14 +444,14 @@ func (r *Reporter) calcStatForPeriod(col, stat string, now time.Time, d time.Dur
 		end = now.Add(d)
 	}
 
-	var res float64
+	var res sql.NullFloat64
 
 	r.store.DB().Model(&store.Observation{}).
-		Where("timestamp >= ? AND timestamp <= ?", start.UTC(), end.UTC()).
+		Where("timestamp >= ? AND timestamp <= ?", sqlite.FromTime(start), sqlite.FromTime(end)).
 		Select(stat + "(" + col + ") as value").
 		Find(&res)
 
-	return res
+	return res.Float64
 }
 
 func (r *Reporter) calcIndices(_ time.Time, s *Statistics) {
@@ -289,18 +476,154 @@ func (r *Reporter) calcIsDaylight(ts time.Time, s *Statistics) {
 	s.IsDaylight = ts.After(sunrise) && ts.Before(sunset)
 }
 
+func (r *Reporter) calcSolar(ts time.Time, snap *snapshot, s *Statistics) {
+	s.CurrentSolarMax = r.solarMax(ts)
+	s.IsSunny = r.isSunny(s.SolarRadiation, s.CurrentSolarMax)
+	if snap != nil {
+		s.SunshineHoursToday = snap.sunshine
+	} else {
+		s.SunshineHoursToday = r.sunshine(r.cal.BeginningOfDay(ts), ts)
+	}
+}
+
+// solarMax returns the theoretical maximum solar radiation at time t, using the configured solar model.
+func (r *Reporter) solarMax(t time.Time) xunit.Irradiance {
+	if r.solar.Model == SolarModelBras {
+		return meteorology.BrasSolarRadiation(r.site, t, r.solar.Turbidity)
+	}
+	return meteorology.RyanStolzenbachSolarRadiation(r.site, t, r.solar.Transmission)
+}
+
+// isSunny returns true if the measured solar radiation is at least the sunshine threshold
+// of the theoretical maximum.
+func (r *Reporter) isSunny(solar, max xunit.Irradiance) bool {
+	return max > 0 && solar >= max*xunit.Irradiance(r.solar.SunshineThreshold/100)
+}
+
+// maxSunshineInterval limits the period attributed to a single observation,
+// so that missing observations are not counted as sunshine.
+const maxSunshineInterval = 10 * time.Minute
+
+// sunshine returns the duration of sunshine for the observations from start up to, but excluding, end.
+// Each observation counts until the following observation, or end.
+func (r *Reporter) sunshine(start, end time.Time) time.Duration {
+	var rows []struct {
+		Timestamp sqlite.Timestamp
+		Solar     float64
+	}
+
+	r.store.DB().Model(&store.Observation{}).
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Select("timestamp, solar_radiation_wm2 as solar").
+		Order("timestamp").
+		Find(&rows)
+
+	var total time.Duration
+	for i, row := range rows {
+		next := end
+		if i+1 < len(rows) {
+			next = rows[i+1].Timestamp.Time
+		}
+
+		solar := xunit.Irradiance(row.Solar) * xunit.WattPerSquareMetre
+		if r.isSunny(solar, r.solarMax(row.Timestamp.Time)) {
+			total += sunshineInterval(row.Timestamp.Time, next)
+		}
+	}
+
+	return total
+}
+
+// sunshineInterval returns the period attributed to a sunny observation at t, which is followed by next.
+func sunshineInterval(t, next time.Time) time.Duration {
+	if d := next.Sub(t); d < maxSunshineInterval {
+		return d
+	}
+	return maxSunshineInterval
+}
+
 func (r *Reporter) calcApparentTemp(_ time.Time, s *Statistics) {
-	// Wind chill in Australia also uses the apparent temperature
-	//   per https://en.wikipedia.org/wiki/Wind_chill
-	s.WindChill = meteorology.ApparentTemperature(s.OutdoorTemperature, s.WindSpeedLast, s.OutdoorHumidity)
+	s.WindChill = meteorology.WindChill(s.OutdoorTemperature, s.WindSpeedLast, r.feelsLike.WindChill)
 	s.ApparentTemp = meteorology.ApparentTemperature(s.OutdoorTemperature, s.WindSpeedLast, s.OutdoorHumidity)
-	s.TempFeelsLike = meteorology.ApparentTemperature(s.OutdoorTemperature, s.WindSpeedLast, s.OutdoorHumidity)
+	s.TempFeelsLike = meteorology.FeelsLike(r.feelsLike.Locale, s.OutdoorTemperature, s.WindSpeedLast, s.OutdoorHumidity)
+}
+
+func (r *Reporter) calcEvapotranspiration(ts time.Time, snap *snapshot, s *Statistics) {
+	if snap != nil {
+		s.Evapotranspiration = snap.evapotranspiration
+		return
+	}
+	s.Evapotranspiration = r.evapotranspiration(r.cal.BeginningOfDay(ts), ts)
 }
 
-func last24Hours(d time.Time) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		lo := now.With(d).BeginningOfDay()
-		hi := now.With(d).BeginningOfDay().Add(24 * time.Hour)
-		return db.Where("timestamp >= ? AND timestamp < ?", lo.UTC(), hi.UTC())
+// evapotranspiration returns the reference evapotranspiration for the observations from start
+// up to, but excluding, end, by summing the hourly ET0 calculated from the mean values of each hour.
+// The final hour may be a partial hour.
+func (r *Reporter) evapotranspiration(start, end time.Time) unit.Length {
+	var rows []struct {
+		Hour     int
+		Temp     float64 // °C
+		Humidity float64 // fraction
+		Wind     float64 // km/h
+		Solar    float64 // W/m2
+	}
+
+	r.store.DB().Model(&store.Observation{}).
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Select("(strftime('%s', timestamp) - @start) / 3600 as hour, "+
+			"AVG(temp_outdoor_c) as temp, AVG(humidity_outdoor_pct) as humidity, "+
+			"AVG(wind_speed_kph) as wind, AVG(solar_radiation_wm2) as solar",
+			sql.Named("start", start.Unix())).
+		Group("hour").
+		Find(&rows)
+
+	var et unit.Length
+	for _, row := range rows {
+		m := hourlyMeans{n: 1, temp: row.Temp, humidity: row.Humidity, wind: row.Wind, solar: row.Solar}
+		et += m.evapotranspiration(r.site, start.Add(time.Duration(row.Hour)*time.Hour), end)
 	}
+
+	return et
+}
+
+// hourlyMeans are the sums of the values of the observations of an hour, for the hourly
+// reference evapotranspiration from the mean values.
+type hourlyMeans struct {
+	n        float64
+	temp     float64 // °C
+	humidity float64 // fraction, as stored
+	wind     float64 // km/h
+	solar    float64 // W/m2
+}
+
+func (m *hourlyMeans) add(o *model.Observation) {
+	m.n++
+	m.temp += o.TempOutdoor.Celsius()
+	m.humidity += float64(o.HumidityOutdoor) / 100
+	m.wind += o.WindSpeed.KilometersPerHour()
+	m.solar += o.SolarRadiation.WattsPerSquareMetre()
+}
+
+// evapotranspiration returns the reference evapotranspiration of the hour starting at t,
+// which is a partial hour when it is limited by end.
+func (m hourlyMeans) evapotranspiration(site meteorology.Site, t, end time.Time) unit.Length {
+	if m.n == 0 {
+		return 0
+	}
+	d := time.Hour
+	if rem := end.Sub(t); rem < d {
+		d = rem
+	}
+	return meteorology.HourlyReferenceEvapotranspiration(
+		site, t, d,
+		unit.FromCelsius(m.temp/m.n),
+		int(math.Round(m.humidity/m.n*100)),
+		unit.Speed(m.wind/m.n)*unit.KilometersPerHour,
+		xunit.Irradiance(m.solar/m.n)*xunit.WattPerSquareMetre,
+	)
+}
+
+func (r *Reporter) calcForecast(ts time.Time, s *Statistics) {
+	calm := meteorology.SpeedToWindForce(s.WindSpeedAvg) == meteorology.WindForceCalm
+	s.ZambrettiForecast = meteorology.Zambretti(s.BarometricPressure, s.PressureTrend, s.TenMinWindBearingAvg, calm, r.lat < 0, ts.Month())
 }
@@ -0,0 +1,517 @@
+package reporting
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func mustCreateReporter(t *testing.T, obs []model.Observation) *Reporter {
+	return mustCreateReporterWithConfig(t, viper.New(), obs)
+}
+
+func mustCreateReporterWithConfig(t *testing.T, vp *viper.Viper, obs []model.Observation) *Reporter {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "weather.db")), &gorm.Config{})
+	require.NoError(t, err)
+
+	st, err := store.New(db, event.New())
+	require.NoError(t, err)
+	_, err = st.WriteObservations(obs)
+	require.NoError(t, err)
+
+	vp.Set("location.latitude", -41.44)
+	vp.Set("location.longitude", 147.23)
+	vp.Set("location.altitude", 180)
+
+	r, err := New(zap.NewNop(), vp, st)
+	require.NoError(t, err)
+
+	return r
+}
+
+// observationsForDay returns observations every 5 minutes for the day of start,
+// with a temperature following the sun, solar radiation during the middle of the day
+// and 0.1 mm of rain each hour.
+func observationsForDay(start time.Time) []model.Observation {
+	var obs []model.Observation
+	for ts := start; ts.Before(start.AddDate(0, 0, 1)); ts = ts.Add(5 * time.Minute) {
+		h := ts.Sub(start).Hours()
+		var solar float64
+		if h >= 7 && h < 17 {
+			solar = 600
+		}
+		obs = append(obs, model.Observation{
+			Timestamp:       ts,
+			BarometricRel:   unit.Pressure(1010+h/2) * unit.Hectopascal,
+			DailyRain:       unit.Length(h/10) * unit.Millimeter,
+			TotalRain:       unit.Length(float64(ts.Unix())/36000) * unit.Millimeter,
+			HumidityOutdoor: 80 - int(h),
+			WindSpeed:       10 * unit.KilometersPerHour,
+			WindGust:        unit.Speed(10+h) * unit.KilometersPerHour,
+			SolarRadiation:  xunit.Irradiance(solar) * xunit.WattPerSquareMetre,
+			TempOutdoor:     unit.FromCelsius(5 + h/2),
+		})
+	}
+	return obs
+}
+
+func TestReporter_Summarize(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, loc)
+	r := mustCreateReporter(t, append(observationsForDay(day), observationsForDay(day.AddDate(0, 0, 1))...))
+
+	ds, err := r.Summarize(day.Add(13 * time.Hour))
+	require.NoError(t, err)
+
+	assert.True(t, day.Equal(ds.Date))
+	assert.InDelta(t, 16.958, ds.TempHi.Celsius(), 0.01)
+	assert.True(t, day.Add(23*time.Hour+55*time.Minute).Equal(ds.TempHiTime))
+	assert.InDelta(t, 5, ds.TempLo.Celsius(), 0.01)
+	assert.InDelta(t, 2.39, ds.Rain.Millimeters(), 0.01)
+	assert.InDelta(t, 240, ds.WindRun.Kilometers(), 1)
+	assert.Equal(t, 80, ds.HumidityHi)
+	assert.Equal(t, 57, ds.HumidityLo)
+	assert.InDelta(t, 600, ds.SolarRadiationHi.WattsPerSquareMetre(), 0.01)
+	assert.Greater(t, ds.Evapotranspiration.Millimeters(), 1.0)
+	assert.Less(t, ds.Evapotranspiration.Millimeters(), 8.0)
+
+	_, err = r.Summarize(day.AddDate(0, 0, 5))
+	assert.ErrorIs(t, err, ErrNoObservations)
+
+	ds, err = r.Summarize(day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.InDelta(t, 2.4, ds.Rain.Millimeters(), 0.01, "includes rain since the last observation of the previous day")
+}
+
+func TestReporter_DayStartHour(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, loc)
+	r := mustCreateReporter(t, append(observationsForDay(day), observationsForDay(day.AddDate(0, 0, 1))...))
+	r.cal, _ = calendar.New(viperWithDayStartHour(9))
+
+	s := r.Generate(day.Add(24*time.Hour + 8*time.Hour))
+	assert.InDelta(t, 2.3, s.RainfallToday.Millimeters(), 0.01, "rain since 9am")
+	assert.InDelta(t, 0.89, s.YesterdayRainfall.Millimeters(), 0.01, "rain from the first observation to 9am")
+	assert.True(t, day.Add(24*time.Hour).Equal(s.TodayTempLoTime))
+	assert.True(t, day.Add(23*time.Hour+55*time.Minute).Equal(s.TodayTempHiTime))
+
+	ds, err := r.Summarize(day.Add(24*time.Hour + 8*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, day.Equal(ds.Date))
+	assert.InDelta(t, 2.4, ds.Rain.Millimeters(), 0.01)
+}
+
+func viperWithDayStartHour(hour int) *viper.Viper {
+	vp := viper.New()
+	vp.Set("calendar.day_start_hour", hour)
+	return vp
+}
+
+func TestReporter_Generate_Evapotranspiration(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, loc)
+	r := mustCreateReporter(t, observationsForDay(day))
+
+	morning := r.Generate(day.Add(6 * time.Hour))
+	noon := r.Generate(day.Add(12 * time.Hour))
+	ds, err := r.Summarize(day)
+	require.NoError(t, err)
+
+	assert.Greater(t, noon.Evapotranspiration, morning.Evapotranspiration)
+	assert.Greater(t, ds.Evapotranspiration, noon.Evapotranspiration)
+}
+
+func TestReporter_Generate_Solar(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, loc)
+	obs := observationsForDay(day)
+	for i := range obs {
+		obs[i].SolarRadiation = 1200 * xunit.WattPerSquareMetre
+	}
+	r := mustCreateReporter(t, obs)
+
+	noon := r.Generate(day.Add(12 * time.Hour))
+	assert.InDelta(t, 1050, noon.CurrentSolarMax.WattsPerSquareMetre(), 30)
+	assert.True(t, noon.IsSunny)
+
+	night := r.Generate(day.Add(23 * time.Hour))
+	assert.Zero(t, night.CurrentSolarMax)
+	assert.False(t, night.IsSunny)
+
+	// the sun rises at 04:50 and sets at 19:30
+	assert.InDelta(t, 14.7, night.SunshineHoursToday.Hours(), 0.3)
+
+	ds, err := r.Summarize(day)
+	require.NoError(t, err)
+	assert.Equal(t, night.SunshineHoursToday, ds.SunshineHours)
+}
+
+func TestReporter_Generate_Rainfall(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	start := time.Date(2021, 12, 3, 12, 0, 0, 0, loc)
+
+	var obs []model.Observation
+	for i, total := range []float64{10, 10.2, 10.4, 0, 0.2, 200, 200.2} {
+		obs = append(obs, model.Observation{
+			Timestamp: start.Add(time.Duration(i) * 10 * time.Minute),
+			TotalRain: unit.Length(total) * unit.Millimeter,
+		})
+	}
+	r := mustCreateReporter(t, obs)
+
+	for _, date := range []time.Time{
+		time.Date(2021, 11, 30, 0, 0, 0, 0, loc),
+		time.Date(2021, 12, 1, 0, 0, 0, 0, loc),
+		time.Date(2021, 12, 2, 0, 0, 0, 0, loc),
+	} {
+		require.NoError(t, r.store.WriteDailySummary(model.DailySummary{Date: date, Rain: 5 * unit.Millimeter}))
+	}
+
+	s := r.Generate(start.Add(90 * time.Minute))
+	assert.InDelta(t, 0.8, s.RainfallToday.Millimeters(), 0.01, "ignores reset and spike")
+	assert.InDelta(t, 0.4, s.RainfallLastHour.Millimeters(), 0.01)
+	assert.InDelta(t, 0.8, s.RainfallLast24Hours.Millimeters(), 0.01)
+	assert.InDelta(t, 5, s.YesterdayRainfall.Millimeters(), 0.01)
+	assert.InDelta(t, 10.8, s.MonthlyRainfall.Millimeters(), 0.01)
+	assert.InDelta(t, 10.8, s.SeasonRainfall.Millimeters(), 0.01, "season starts 1 December")
+	assert.InDelta(t, 15.8, s.YearlyRainfall.Millimeters(), 0.01)
+}
+
+func TestReporter_Generate_SensorContactLost(t *testing.T) {
+	ts := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	r := mustCreateReporter(t, nil)
+	assert.True(t, r.Generate(ts).SensorContactLost, "no observations")
+
+	r = mustCreateReporter(t, []model.Observation{{Timestamp: ts, TempOutdoor: unit.FromCelsius(20)}})
+	s := r.Generate(ts.Add(5 * time.Minute))
+	assert.False(t, s.SensorContactLost)
+	assert.InDelta(t, 20, s.OutdoorTemperature.Celsius(), 0.01)
+
+	s = r.Generate(ts.Add(15 * time.Minute))
+	assert.True(t, s.SensorContactLost)
+}
+
+func TestReporter_Generate_Wind(t *testing.T) {
+	ts := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	var obs []model.Observation
+	for i, dir := range []float64{350, 10, 350, 10, 350} {
+		obs = append(obs, model.Observation{
+			Timestamp: ts.Add(time.Duration(i-4) * 2 * time.Minute),
+			WindSpeed: unit.Speed(10+i) * unit.KilometersPerHour,
+			WindGust:  unit.Speed(20+i) * unit.KilometersPerHour,
+			WindDir:   unit.Angle(dir) * unit.Degree,
+		})
+	}
+	r := mustCreateReporter(t, obs)
+
+	s := r.Generate(ts)
+	assert.Equal(t, meteorology.Direction("N"), s.WindDirectionAvg)
+	assert.InDelta(t, 12, s.WindSpeedAvg.KilometersPerHour(), 0.01)
+	assert.InDelta(t, 24, s.TenMinGustHi.KilometersPerHour(), 0.01)
+	assert.Equal(t, 3, s.WindForce, "Beaufort force of the average speed")
+}
+
+func TestReporter_Generate_Units(t *testing.T) {
+	ts := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	obs := []model.Observation{{Timestamp: ts, TempOutdoor: unit.FromCelsius(20)}}
+
+	s := mustCreateReporter(t, obs).Generate(ts)
+	assert.Equal(t, []string{"km/h", "C", "hPa", "mm"}, []string{s.WindUnits, s.TempUnits, s.PressureUnits, s.RainUnits})
+
+	vp := viper.New()
+	vp.Set("reporting.units.system", "us")
+	vp.Set("reporting.units.wind", "kts")
+	s = mustCreateReporterWithConfig(t, vp, obs).Generate(ts)
+	assert.Equal(t, []string{"kts", "F", "in", "in"}, []string{s.WindUnits, s.TempUnits, s.PressureUnits, s.RainUnits})
+	assert.InDelta(t, 20, s.OutdoorTemperature.Celsius(), 0.01, "statistics are independent of the units")
+
+	vp = viper.New()
+	vp.Set("reporting.units.pressure", "furlongs")
+	_, err := New(zap.NewNop(), vp, nil)
+	assert.Error(t, err)
+}
+
+func TestReporter_Generate_DegreeDays(t *testing.T) {
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	r := mustCreateReporter(t, append(observationsForDay(day), observationsForDay(day.AddDate(0, 0, 1))...))
+	require.NoError(t, r.store.WriteDailySummary(model.DailySummary{
+		Date:              day.AddDate(0, 0, -1),
+		HeatingDegreeDays: 1,
+		CoolingDegreeDays: 2,
+		GrowingDegreeDays: 3,
+	}))
+
+	// the temperature is 5°C at midnight, rising to 11°C at noon, with a high of 16.96°C
+	s := r.Generate(day.Add(36 * time.Hour))
+	assert.InDelta(t, 10, s.HeatingDegreeDaysToday, 0.01)
+	assert.Zero(t, s.CoolingDegreeDaysToday)
+	assert.Zero(t, s.GrowingDegreeDaysToday)
+	assert.InDelta(t, 1+7.02+10, s.HeatingDegreeDaysToDate, 0.01)
+	assert.InDelta(t, 2, s.CoolingDegreeDaysToDate, 0.01)
+	assert.InDelta(t, 3+0.98, s.GrowingDegreeDaysToDate, 0.01)
+
+	ds, err := r.Summarize(day)
+	require.NoError(t, err)
+	assert.InDelta(t, 7.02, ds.HeatingDegreeDays, 0.01)
+	assert.InDelta(t, 0.98, ds.GrowingDegreeDays, 0.01)
+
+	vp := viper.New()
+	vp.Set("reporting.degree_days.method", "single-sine")
+	vp.Set("reporting.degree_days.start_month", 12)
+	vp.Set("reporting.degree_days.start_day", 2)
+	r = mustCreateReporterWithConfig(t, vp, append(observationsForDay(day), observationsForDay(day.AddDate(0, 0, 1))...))
+	s = r.Generate(day.Add(36 * time.Hour))
+	assert.Equal(t, s.HeatingDegreeDaysToday, s.HeatingDegreeDaysToDate, "season starts today")
+	assert.Greater(t, s.GrowingDegreeDaysToday, 0.0, "morning is above the base temperature")
+}
+
+func TestReporter_Generate_FeelsLike(t *testing.T) {
+	ts := time.Date(2021, 7, 1, 6, 0, 0, 0, time.UTC)
+	obs := []model.Observation{{
+		Timestamp:       ts,
+		TempOutdoor:     unit.FromCelsius(0),
+		HumidityOutdoor: 80,
+		WindSpeed:       20 * unit.KilometersPerHour,
+	}}
+
+	s := mustCreateReporter(t, obs).Generate(ts)
+	assert.InDelta(t, -5.2, s.WindChill.Celsius(), 0.1)
+	assert.InDelta(t, -6.3, s.ApparentTemp.Celsius(), 0.1)
+	assert.Equal(t, s.ApparentTemp, s.TempFeelsLike)
+
+	vp := viper.New()
+	vp.Set("reporting.feels_like.wind_chill", "legacy")
+	vp.Set("reporting.feels_like.locale", "us")
+	s = mustCreateReporterWithConfig(t, vp, obs).Generate(ts)
+	assert.Less(t, s.WindChill.Celsius(), -5.2)
+	assert.InDelta(t, -5.2, s.TempFeelsLike.Celsius(), 0.1, "JAG/TI wind chill")
+}
+
+func TestReporter_DailySummaries(t *testing.T) {
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local)
+	r := mustCreateReporter(t, observationsForDay(day))
+	require.NoError(t, r.store.WriteDailySummary(model.DailySummary{Date: day.AddDate(0, 0, -1), Rain: 5 * unit.Millimeter}))
+
+	days, err := r.DailySummaries(day.AddDate(0, 0, -2), day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.InDelta(t, 5, days[0].Rain.Millimeters(), 0.01, "archived summary")
+	assert.True(t, day.Equal(days[1].Date))
+	assert.InDelta(t, 16.958, days[1].TempHi.Celsius(), 0.01, "summarized from the observations")
+}
+
+func TestReporter_Generate_Timezone(t *testing.T) {
+	loc, err := time.LoadLocation("Australia/Hobart")
+	require.NoError(t, err)
+	day := time.Date(2021, 12, 2, 0, 0, 0, 0, loc)
+
+	vp := viper.New()
+	vp.Set("location.timezone", "Australia/Hobart")
+	r := mustCreateReporterWithConfig(t, vp, append(observationsForDay(day.AddDate(0, 0, -1)), observationsForDay(day)...))
+
+	s := r.Generate(day.Add(13 * time.Hour).UTC())
+	assert.Equal(t, loc, s.Timestamp.Location())
+	assert.InDelta(t, 5, s.TodayTempLo.Celsius(), 0.01, "day starts at midnight in Hobart")
+	assert.True(t, day.Equal(s.TodayTempLoTime))
+	assert.Equal(t, loc, s.TodayTempLoTime.Location())
+	assert.InDelta(t, 16.958, s.TodayTempHi.Celsius(), 0.01)
+}
+
+func TestReporter_Generate_Anomalies(t *testing.T) {
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	r := mustCreateReporter(t, append(observationsForDay(day), observationsForDay(day.AddDate(0, 0, 1))...))
+
+	ts := day.Add(36 * time.Hour)
+	s := r.Generate(ts)
+	assert.True(t, math.IsNaN(s.TodayTempHiAnomaly.Celsius()), "no normals")
+	assert.True(t, math.IsNaN(s.TodayTempHiPercentile), "no archived days")
+	assert.True(t, math.IsNaN(s.MonthRainfallPercent))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, r.store.WriteDailySummary(model.DailySummary{
+			Date:   time.Date(2020, 12, i+1, 0, 0, 0, 0, time.UTC),
+			TempHi: unit.FromCelsius(float64(10 + i)),
+			TempLo: unit.FromCelsius(float64(i)),
+			Rain:   unit.Length(i) * unit.Millimeter,
+		}))
+	}
+	require.NoError(t, r.store.WriteClimateNormals([]model.ClimateNormal{
+		{Month: time.December, TempHiMean: unit.FromCelsius(20), TempLoMean: unit.FromCelsius(8), TempMean: unit.FromCelsius(14), Rain: 62 * unit.Millimeter},
+		{Month: time.December, Day: 3, TempHiMean: unit.FromCelsius(18), Rain: 3 * unit.Millimeter},
+	}))
+
+	// today's high is 16.96°C and low is 5°C, and the month so far has the daily means 10.98°C and 8°C
+	s = r.Generate(ts)
+	assert.InDelta(t, -3.04, s.TodayTempHiAnomaly.Celsius(), 0.01)
+	assert.InDelta(t, -3, s.TodayTempLoAnomaly.Celsius(), 0.01)
+	assert.InDelta(t, 70, s.TodayTempHiPercentile, 0.01)
+	assert.InDelta(t, 55, s.TodayTempLoPercentile, 0.01, "equal days count as half")
+	assert.InDelta(t, -4.51, s.MonthTempAnomaly.Celsius(), 0.01)
+	assert.InDelta(t, 100*s.MonthlyRainfall.Millimeters()/62, s.MonthRainfallPercent, 0.01)
+
+	a, err := r.Anomalies(&model.DailySummary{
+		Date:   day.AddDate(0, 0, 2),
+		TempHi: unit.FromCelsius(19),
+		TempLo: unit.FromCelsius(9),
+		Rain:   6 * unit.Millimeter,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, a.Normal)
+	assert.Equal(t, 3, a.Normal.Day, "normal of the day is preferred")
+	assert.InDelta(t, 1, a.TempHi.Celsius(), 0.01)
+	assert.True(t, math.IsNaN(a.TempLo.Celsius()), "not in the normal of the day")
+	assert.InDelta(t, 200, a.RainPercent, 0.01)
+	assert.InDelta(t, 95, a.TempHiPercentile, 0.01)
+	assert.InDelta(t, 65, a.RainPercentile, 0.01)
+}
+
+func TestReporter_Generate_PressureTendency(t *testing.T) {
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	obs := observationsForDay(day)
+
+	// the pressure rises 0.5 hPa each hour
+	s := mustCreateReporter(t, obs).Generate(day.Add(12 * time.Hour))
+	assert.InDelta(t, 1.5, s.PressureChange.Hectopascals(), 0.01)
+	assert.Equal(t, meteorology.PressureRising, s.PressureTendency)
+	assert.Equal(t, "Rising slowly", s.PressureTendencyText)
+	assert.False(t, s.PressureFallWarning)
+
+	s = mustCreateReporter(t, obs).Generate(day.Add(1 * time.Hour))
+	assert.Equal(t, meteorology.PressureCharacteristicNone, s.PressureTendency, "less than three hours of observations")
+
+	// the pressure falls steadily, then rapidly
+	for i := range obs {
+		h := obs[i].Timestamp.Sub(day).Hours()
+		obs[i].BarometricRel = unit.Pressure(1010-h/2-math.Max(0, h-10.5)*3) * unit.Hectopascal
+	}
+	s = mustCreateReporter(t, obs).Generate(day.Add(12 * time.Hour))
+	assert.InDelta(t, -5.88, s.PressureChange.Hectopascals(), 0.01, "mean of the last ten minutes")
+	assert.Equal(t, meteorology.PressureSteadyThenFalling, s.PressureTendency, "decreasing more rapidly")
+	assert.Equal(t, "Falling quickly", s.PressureTendencyText)
+	assert.True(t, s.PressureFallWarning)
+}
+
+func TestReporter_History(t *testing.T) {
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local)
+	r := mustCreateReporter(t, observationsForDay(day))
+
+	samples, err := r.History(day.Add(time.Hour), day.Add(3*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+
+	s := samples[0]
+	assert.True(t, day.Add(time.Hour).Equal(s.Time))
+	assert.Equal(t, 12, s.Observations)
+	assert.InDelta(t, 5.729, s.Temp.Celsius(), 0.001)
+	assert.InDelta(t, 5.5, s.TempRange.Lo.Celsius(), 0.001)
+	assert.True(t, day.Add(time.Hour).Equal(s.TempRange.LoTime))
+	assert.InDelta(t, 5.958, s.TempRange.Hi.Celsius(), 0.001)
+	assert.True(t, day.Add(time.Hour+55*time.Minute).Equal(s.TempRange.HiTime))
+	assert.Equal(t, 79, s.HumidityHi)
+	assert.InDelta(t, 1010.729, s.Pressure.Hectopascals(), 0.001)
+	assert.InDelta(t, 11.917, s.WindGust.KilometersPerHour(), 0.001)
+	assert.InDelta(t, 10, s.WindSpeed.KilometersPerHour(), 0.001)
+	assert.InDelta(t, 0.1, s.Rain.Millimeters(), 0.001, "including the increment from the prior observation")
+	assert.True(t, day.Add(time.Hour+55*time.Minute).Equal(s.RainTime))
+
+	samples, err = r.History(day.Add(-time.Hour), day.Add(time.Hour), 45*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	assert.Equal(t, 0, samples[0].Observations, "no observations prior to the day")
+	assert.Equal(t, 6, samples[1].Observations)
+	assert.Equal(t, 6, samples[2].Observations, "a partial interval")
+}
+
+func TestReporter_Records(t *testing.T) {
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local)
+	r := mustCreateReporter(t, nil)
+	for i, ds := range []model.DailySummary{
+		{TempHi: unit.FromCelsius(25), TempLo: unit.FromCelsius(10), Rain: 4 * unit.Millimeter},
+		{TempHi: unit.FromCelsius(31), TempLo: unit.FromCelsius(12), Rain: 12 * unit.Millimeter},
+		{TempHi: unit.FromCelsius(22), TempLo: unit.FromCelsius(8), Rain: 0},
+	} {
+		ds.Date = day.AddDate(0, 0, i)
+		ds.TempHiTime = ds.Date.Add(15 * time.Hour)
+		ds.TempLoTime = ds.Date.Add(6 * time.Hour)
+		require.NoError(t, r.store.WriteDailySummary(ds))
+	}
+
+	rec, err := r.Records(day, day.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.InDelta(t, 31, rec.TempHi.Celsius(), 0.01)
+	assert.True(t, day.AddDate(0, 0, 1).Add(15*time.Hour).Equal(rec.TempHiTime))
+	assert.InDelta(t, 8, rec.TempLo.Celsius(), 0.01)
+	assert.InDelta(t, 12, rec.RainHi.Millimeters(), 0.01)
+	assert.True(t, day.AddDate(0, 0, 1).Equal(rec.RainHiDate))
+	assert.True(t, rec.WindGustHiTime.IsZero(), "not available")
+
+	ts := day.AddDate(0, 0, 3).Add(14 * time.Hour)
+	rec.Include(&Statistics{
+		Timestamp:           ts,
+		Date:                day.AddDate(0, 0, 3),
+		TodayTempHi:         unit.FromCelsius(33),
+		TodayTempHiTime:     ts,
+		TodayTempLo:         unit.FromCelsius(9),
+		TodayTempLoTime:     ts.Add(-8 * time.Hour),
+		RainRate:            0,
+		TodayRainRateHi:     30 * unit.Millimeter,
+		TodayRainRateHiTime: ts.Add(-2 * time.Hour),
+		RainfallToday:       15 * unit.Millimeter,
+	})
+	assert.InDelta(t, 33, rec.TempHi.Celsius(), 0.01, "beaten today")
+	assert.True(t, ts.Equal(rec.TempHiTime))
+	assert.InDelta(t, 8, rec.TempLo.Celsius(), 0.01)
+	assert.True(t, rec.WindGustHiTime.IsZero(), "no gust today")
+	assert.InDelta(t, 30, rec.RainRateHi.Millimeters(), 0.01, "today's high, not the current rate")
+	assert.True(t, ts.Add(-2*time.Hour).Equal(rec.RainRateHiTime))
+	assert.InDelta(t, 15, rec.RainHi.Millimeters(), 0.01)
+	assert.True(t, day.AddDate(0, 0, 3).Equal(rec.RainHiDate), "the date of the day, not the time of the report")
+}
+
+// TestReporter_QueryBoundaries tests the times of the queries are compared with the timestamps as stored,
+// in UTC to the second, so an observation at the start of a period is included, and one at the end of a
+// half-open period is excluded.
+func TestReporter_QueryBoundaries(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*60*60)
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, loc)
+	next := day.AddDate(0, 0, 1)
+	r := mustCreateReporter(t, []model.Observation{
+		{Timestamp: day, TempOutdoor: unit.FromCelsius(30), WindSpeed: 36 * unit.KilometersPerHour},
+		{Timestamp: day.Add(time.Hour), TempOutdoor: unit.FromCelsius(20), WindSpeed: 36 * unit.KilometersPerHour},
+		{Timestamp: next, TempOutdoor: unit.FromCelsius(10), WindSpeed: 36 * unit.KilometersPerHour},
+		{Timestamp: next.Add(time.Hour), TempOutdoor: unit.FromCelsius(10), WindSpeed: 36 * unit.KilometersPerHour},
+	})
+
+	ts, val := r.calcLimitAndTimeForPeriod("temp_outdoor_c", limitMax, day, time.Hour)
+	assert.True(t, day.Equal(ts), "observation at the start")
+	assert.Equal(t, 30.0, val)
+	assert.Equal(t, loc, ts.Location())
+
+	ts, _ = r.calcLimitAndTimeForPeriod("temp_outdoor_c", limitMin, day, time.Hour)
+	assert.True(t, day.Add(time.Hour).Equal(ts), "observation at the end")
+
+	ts, val = r.calcLimitAndTimeForPeriod("temp_outdoor_c", limitMax, day.Add(2*time.Hour), time.Hour)
+	assert.True(t, ts.IsZero(), "no observations")
+	assert.Zero(t, val)
+
+	// an hour at 10 m/s between the first two observations, excluding the observation at the end of the day
+	assert.InDelta(t, 36, r.windRun(day, next).Kilometers(), 0.01)
+	assert.InDelta(t, 36, r.windRun(next, next.AddDate(0, 0, 1)).Kilometers(), 0.01)
+}
@@ -0,0 +1,611 @@
+package reporting
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+	"go.uber.org/zap"
+)
+
+const (
+	tenMinutePeriod = 10 * time.Minute
+	trendPeriod     = 3 * time.Hour
+)
+
+// rolling maintains the statistics of the realtime report incrementally, as each observation
+// is received, rather than querying the observations for each report.
+//
+// The windows are relative to the time of the report, and are advanced as each report is generated,
+// so the statistics are only available for a report at or after both the last observation and the
+// previous report. Otherwise, the report falls back to querying the observations.
+//
+// The statistics of the days prior to the meteorological day, such as the rain of the month, are
+// read once, with the first observation of the day.
+type rolling struct {
+	log              *zap.Logger
+	store            *store.Store
+	day              func(t time.Time) time.Time
+	pressure         func(o *model.Observation) unit.Pressure
+	sunny            func(o *model.Observation) bool
+	prior            func(today time.Time) *priorStatistics
+	site             meteorology.Site
+	maxRainIncrement float64
+
+	mu        sync.Mutex
+	ready     bool
+	last      *model.Observation
+	watermark time.Time
+
+	// statistics for the meteorological day starting at today
+	today                  time.Time
+	tempHi, tempLo         extreme
+	windHi, gustHi         extreme
+	pressureHi, pressureLo extreme
+	rainRateHi             extreme
+	windRun                float64 // m
+	rainToday              float64 // mm
+	sunshine               time.Duration
+	lastSunny              bool
+	evapotranspiration     unit.Length // of the completed hours
+	hour                   int         // of the day, of hourMeans
+	hourMeans              hourlyMeans
+	priorStatistics        *priorStatistics
+
+	wind                          windWindow
+	pressureTrend, tempTrend      regressionWindow
+	pressureMeans                 meanWindow
+	rainLastHour, rainLast24Hours sumWindow
+}
+
+func newRolling(r *Reporter) *rolling {
+	pressure := func(o *model.Observation) unit.Pressure { return o.BarometricRel }
+	if r.barometricType == BarometricMeasurementTypeAbsolute {
+		pressure = func(o *model.Observation) unit.Pressure { return o.BarometricAbs }
+	}
+
+	return &rolling{
+		log:   r.log,
+		store: r.store,
+		day: func(t time.Time) time.Time {
+			return r.cal.BeginningOfDay(t.In(r.cal.Location()))
+		},
+		pressure: pressure,
+		sunny: func(o *model.Observation) bool {
+			return r.isSunny(o.SolarRadiation, r.solarMax(o.Timestamp))
+		},
+		prior:            r.priorStatistics,
+		site:             r.site,
+		maxRainIncrement: r.rain.MaxIncrement,
+	}
+}
+
+// HandleObservation adds o to the statistics.
+func (r *rolling) HandleObservation(o *model.Observation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case !r.ready:
+		r.warm(o.Timestamp)
+	case o.Timestamp.Before(r.last.Timestamp):
+		// the windows cannot be rewound, so start over
+		r.log.Info("Observation received out of order.", zap.Time("timestamp", o.Timestamp))
+		r.warm(r.last.Timestamp)
+	default:
+		r.add(o)
+	}
+}
+
+// Warm reads the observations for the windows as of now.
+func (r *rolling) Warm(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.warm(now)
+}
+
+func (r *rolling) warm(now time.Time) {
+	r.reset()
+
+	start := r.day(now)
+	if t := now.Add(-24 * time.Hour); t.Before(start) {
+		start = t
+	}
+
+	db := r.store.DB()
+
+	// include the prior observation for the first rain increment
+	prev := db.Model(&store.Observation{}).
+		Where("timestamp < ?", sqlite.FromTime(start)).
+		Select("MAX(timestamp)")
+
+	var rows []store.Observation
+	tx := db.Where("timestamp >= COALESCE((?), ?)", prev, sqlite.FromTime(start)).
+		Order("timestamp").
+		Find(&rows)
+	if tx.Error != nil {
+		r.log.Error("Failed to read observations for rolling statistics.", zap.Error(tx.Error))
+		return
+	}
+
+	for i := range rows {
+		r.add(rows[i].ToObservation())
+	}
+	if !r.today.IsZero() {
+		r.priorStatistics = r.prior(r.today)
+	}
+
+	r.ready = true
+	r.log.Info("Rolling statistics ready.", zap.Int("observations", len(rows)))
+}
+
+func (r *rolling) reset() {
+	r.ready = false
+	r.last = nil
+	r.watermark = time.Time{}
+	r.resetToday(time.Time{})
+	r.wind = windWindow{}
+	r.pressureTrend = regressionWindow{}
+	r.tempTrend = regressionWindow{}
+	r.pressureMeans = meanWindow{}
+	r.rainLastHour = sumWindow{}
+	r.rainLast24Hours = sumWindow{}
+}
+
+func (r *rolling) resetToday(today time.Time) {
+	r.today = today
+	r.tempHi = extreme{limit: limitMax}
+	r.tempLo = extreme{limit: limitMin}
+	r.windHi = extreme{limit: limitMax}
+	r.gustHi = extreme{limit: limitMax}
+	r.pressureHi = extreme{limit: limitMax}
+	r.pressureLo = extreme{limit: limitMin}
+	r.rainRateHi = extreme{limit: limitMax}
+	r.windRun = 0
+	r.rainToday = 0
+	r.sunshine = 0
+	r.lastSunny = false
+	r.evapotranspiration = 0
+	r.hour = 0
+	r.hourMeans = hourlyMeans{}
+	r.priorStatistics = nil
+}
+
+func (r *rolling) add(o *model.Observation) {
+	t := o.Timestamp
+	if r.last != nil && !t.After(r.last.Timestamp) {
+		// already added whilst warming
+		return
+	}
+
+	var rain float64
+	if r.last != nil {
+		if inc := o.TotalRain.Millimeters() - r.last.TotalRain.Millimeters(); inc > 0 && inc <= r.maxRainIncrement {
+			rain = inc
+		}
+	}
+
+	if day := r.day(t); day.After(r.today) {
+		r.resetToday(day)
+		// the statistics of the prior days are read with the first observation of the day, except whilst warming
+		if r.ready {
+			r.priorStatistics = r.prior(day)
+		}
+	}
+	sunny := r.sunny(o)
+	if !t.Before(r.today) {
+		temp := o.TempOutdoor.Celsius()
+		pressure := r.pressure(o).Hectopascals()
+		r.tempHi.add(t, temp)
+		r.tempLo.add(t, temp)
+		r.windHi.add(t, o.WindSpeed.KilometersPerHour())
+		r.gustHi.add(t, o.WindGust.KilometersPerHour())
+		r.pressureHi.add(t, pressure)
+		r.pressureLo.add(t, pressure)
+		r.rainRateHi.add(t, o.RainRatePerHour.Millimeters())
+		// as the reporter, the interval since the previous observation is attributed to o
+		if r.last != nil && !r.last.Timestamp.Before(r.today) {
+			r.windRun += o.WindSpeed.MetersPerSecond() * t.Sub(r.last.Timestamp).Seconds()
+		}
+		r.rainToday += rain
+		// as the reporter, the interval until o is attributed to the previous observation
+		if r.last != nil && !r.last.Timestamp.Before(r.today) && r.lastSunny {
+			r.sunshine += sunshineInterval(r.last.Timestamp, t)
+		}
+		// the hours are relative to the start of the day, to the second, as the reporter
+		if hour := int(t.Unix()-r.today.Unix()) / 3600; hour != r.hour {
+			r.evapotranspiration += r.hourMeans.evapotranspiration(r.site, r.hourStart(), t)
+			r.hour, r.hourMeans = hour, hourlyMeans{}
+		}
+		r.hourMeans.add(o)
+	}
+	r.lastSunny = sunny
+
+	r.wind.add(windEntry{t: t, speed: o.WindSpeed, gust: o.WindGust, direction: o.WindDir})
+	r.pressureTrend.add(t, r.pressure(o).Hectopascals())
+	r.tempTrend.add(t, o.TempOutdoor.Celsius())
+	r.pressureMeans.add(t, r.pressure(o).Hectopascals())
+	r.rainLastHour.add(t, rain)
+	r.rainLast24Hours.add(t, rain)
+	r.last = o
+
+	// observations which precede the windows are never required by a later report
+	r.evict(t)
+}
+
+// hourStart returns the start of the hour of hourMeans.
+func (r *rolling) hourStart() time.Time {
+	return r.today.Add(time.Duration(r.hour) * time.Hour)
+}
+
+// evict removes the observations which precede the windows of a report at ts.
+// Timestamps are compared to the second, as they are stored.
+func (r *rolling) evict(ts time.Time) {
+	r.wind.evict(ts.Add(-tenMinutePeriod).Truncate(time.Second))
+	r.pressureTrend.evict(ts.Add(-trendPeriod).Truncate(time.Second))
+	r.tempTrend.evict(ts.Add(-trendPeriod).Truncate(time.Second))
+	r.pressureMeans.evict(ts.Add(-trendPeriod - tenMinutePeriod).Truncate(time.Second))
+	r.rainLastHour.evict(ts.Add(-time.Hour).Truncate(time.Second))
+	r.rainLast24Hours.evict(ts.Add(-24 * time.Hour).Truncate(time.Second))
+}
+
+// snapshot contains the statistics of a report, as calculated from the observations by the reporter.
+type snapshot struct {
+	last *model.Observation
+
+	tempHi, tempLo         extreme
+	windHi, gustHi         extreme
+	pressureHi, pressureLo extreme
+	rainRateHi             extreme
+	windRun                unit.Length
+
+	tenMinWind                    meteorology.WindStatistics
+	pressureTrend, tempTrend      float64
+	pressureTendency              [3]float64 // hPa
+	pressureTendencyValid         bool
+	rainLastHour, rainLast24Hours unit.Length
+	rainToday                     unit.Length
+	sunshine                      time.Duration
+	evapotranspiration            unit.Length
+	prior                         *priorStatistics
+}
+
+// Snapshot returns the statistics for a report at ts, whose meteorological day starts at today,
+// or nil if the statistics are unavailable.
+func (r *rolling) Snapshot(ts, today time.Time) *snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.ready || r.last == nil || ts.Before(r.last.Timestamp) || ts.Before(r.watermark) {
+		return nil
+	}
+
+	s := &snapshot{last: r.last}
+
+	if r.last.Timestamp.Before(today) && today.After(r.today) {
+		// no observations yet today
+		r.resetToday(today)
+	}
+	if !r.today.Equal(today) {
+		// the days are in different locations
+		return nil
+	}
+	if r.priorStatistics == nil {
+		// the day started without an observation, or whilst warming
+		r.priorStatistics = r.prior(today)
+	}
+
+	s.tempHi, s.tempLo = r.tempHi, r.tempLo
+	s.windHi, s.gustHi = r.windHi, r.gustHi
+	s.pressureHi, s.pressureLo = r.pressureHi, r.pressureLo
+	s.rainRateHi = r.rainRateHi
+	s.windRun = unit.Length(r.windRun) * unit.Meter
+	s.rainToday = unit.Length(r.rainToday) * unit.Millimeter
+	s.sunshine = r.sunshine
+	if !r.last.Timestamp.Before(today) && r.lastSunny {
+		s.sunshine += sunshineInterval(r.last.Timestamp, ts)
+	}
+	s.evapotranspiration = r.evapotranspiration + r.hourMeans.evapotranspiration(r.site, r.hourStart(), ts)
+	s.prior = r.priorStatistics
+
+	r.watermark = ts
+	r.evict(ts)
+
+	s.tenMinWind = r.wind.statistics()
+	s.pressureTrend = r.pressureTrend.slope() * trendPeriod.Seconds()
+	s.tempTrend = r.tempTrend.slope() * trendPeriod.Seconds()
+	s.pressureTendencyValid = true
+	for i := range s.pressureTendency {
+		end := pressureTendencyEnd(ts, i)
+		mean, ok := r.pressureMeans.mean(end.Add(-tenMinutePeriod), end)
+		s.pressureTendency[i] = mean
+		s.pressureTendencyValid = s.pressureTendencyValid && ok
+	}
+	s.rainLastHour = unit.Length(r.rainLastHour.sum) * unit.Millimeter
+	s.rainLast24Hours = unit.Length(r.rainLast24Hours.sum) * unit.Millimeter
+
+	return s
+}
+
+// Rainfall returns the rain of the last hour and of the meteorological day starting at today,
+// for a report at ts, and false if the statistics are unavailable.
+func (r *rolling) Rainfall(ts, today time.Time) (lastHour, rainToday unit.Length, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.ready || r.last == nil || ts.Before(r.last.Timestamp) || ts.Before(r.watermark) {
+		return 0, 0, false
+	}
+	switch {
+	case r.last.Timestamp.Before(today):
+		// no observations yet today
+	case r.today.Equal(today):
+		rainToday = unit.Length(r.rainToday) * unit.Millimeter
+	default:
+		return 0, 0, false
+	}
+
+	r.watermark = ts
+	r.evict(ts)
+
+	return unit.Length(r.rainLastHour.sum) * unit.Millimeter, rainToday, true
+}
+
+// extreme is the minimum or maximum value of a series, and the time it first occurred.
+type extreme struct {
+	limit limit
+	valid bool
+	t     time.Time
+	v     float64
+}
+
+func (e *extreme) add(t time.Time, v float64) {
+	if !e.valid || (e.limit == limitMax && v > e.v) || (e.limit == limitMin && v < e.v) {
+		e.valid, e.t, e.v = true, t, v
+	}
+}
+
+// value returns the time and value of the extreme, or the zero time when there are no values.
+func (e extreme) value(loc *time.Location) (time.Time, float64) {
+	if !e.valid {
+		return time.Time{}, 0
+	}
+	return e.t.In(loc), e.v
+}
+
+// The windows accumulate sums as observations are added and removed. To bound the rounding error,
+// the sums are recalculated once as many observations have been removed as remain in the window,
+// which is amortised over the removals.
+
+type point struct {
+	t time.Time
+	v float64
+}
+
+// sumWindow is the sum of the values of a window.
+type sumWindow struct {
+	points  []point
+	sum     float64
+	evicted int
+}
+
+func (w *sumWindow) add(t time.Time, v float64) {
+	if v == 0 {
+		return
+	}
+	w.points = append(w.points, point{t, v})
+	w.sum += v
+}
+
+// evict removes the values prior to cutoff.
+func (w *sumWindow) evict(cutoff time.Time) {
+	for len(w.points) > 0 && w.points[0].t.Before(cutoff) {
+		w.sum -= w.points[0].v
+		w.points = w.points[1:]
+		w.evicted++
+	}
+
+	if w.evicted > 0 && w.evicted >= len(w.points) {
+		w.evicted, w.sum = 0, 0
+		for _, p := range w.points {
+			w.sum += p.v
+		}
+	}
+}
+
+// meanWindow is the mean of the values of a window over an interval, from the cumulative sums of the values.
+type meanWindow struct {
+	points  []point
+	sums    []float64 // sums[i] is the sum of the values of points[:i+1]
+	evicted int
+}
+
+func (w *meanWindow) add(t time.Time, v float64) {
+	sum := v
+	if n := len(w.sums); n > 0 {
+		sum += w.sums[n-1]
+	}
+	w.points = append(w.points, point{t, v})
+	w.sums = append(w.sums, sum)
+}
+
+// evict removes the values prior to cutoff.
+func (w *meanWindow) evict(cutoff time.Time) {
+	for len(w.points) > 0 && w.points[0].t.Before(cutoff) {
+		w.points = w.points[1:]
+		w.sums = w.sums[1:]
+		w.evicted++
+	}
+
+	if w.evicted > 0 && w.evicted >= len(w.points) {
+		w.evicted = 0
+		sum := 0.0
+		for i, p := range w.points {
+			sum += p.v
+			w.sums[i] = sum
+		}
+	}
+}
+
+// mean returns the mean of the values after start and up to and including end, as compared
+// to the second, or false when there are no values.
+func (w *meanWindow) mean(start, end time.Time) (float64, bool) {
+	search := func(t time.Time) int {
+		t = t.Truncate(time.Second).Add(time.Second)
+		return sort.Search(len(w.points), func(i int) bool { return !w.points[i].t.Before(t) })
+	}
+
+	i, j := search(start), search(end)
+	if i >= j {
+		return 0, false
+	}
+	sum := w.sums[j-1] - w.sums[i] + w.points[i].v
+	return sum / float64(j-i), true
+}
+
+// regressionWindow is the least squares regression of the values of a window over time.
+// The times are relative to the first value, to preserve precision.
+type regressionWindow struct {
+	points              []point
+	origin              time.Time
+	n, sx, sy, sxx, sxy float64
+	evicted             int
+}
+
+func (w *regressionWindow) add(t time.Time, v float64) {
+	if len(w.points) == 0 {
+		w.origin = t
+	}
+	w.points = append(w.points, point{t, v})
+	w.accumulate(t, v, 1)
+}
+
+func (w *regressionWindow) accumulate(t time.Time, v, sign float64) {
+	x := t.Sub(w.origin).Seconds()
+	w.n += sign
+	w.sx += sign * x
+	w.sy += sign * v
+	w.sxx += sign * x * x
+	w.sxy += sign * x * v
+}
+
+// evict removes the values prior to cutoff.
+func (w *regressionWindow) evict(cutoff time.Time) {
+	for len(w.points) > 0 && w.points[0].t.Before(cutoff) {
+		w.accumulate(w.points[0].t, w.points[0].v, -1)
+		w.points = w.points[1:]
+		w.evicted++
+	}
+
+	if w.evicted > 0 && w.evicted >= len(w.points) {
+		w.evicted, w.n, w.sx, w.sy, w.sxx, w.sxy = 0, 0, 0, 0, 0, 0
+		for i, p := range w.points {
+			if i == 0 {
+				w.origin = p.t
+			}
+			w.accumulate(p.t, p.v, 1)
+		}
+	}
+}
+
+// slope returns the slope of the regression, in units per second, or 0 when it is undefined.
+func (w *regressionWindow) slope() float64 {
+	den := w.n*w.sxx - w.sx*w.sx
+	if w.n < 2 || den <= 0 {
+		return 0
+	}
+	return (w.n*w.sxy - w.sx*w.sy) / den
+}
+
+type windEntry struct {
+	t         time.Time
+	speed     unit.Speed
+	gust      unit.Speed
+	direction unit.Angle
+}
+
+// windWindow is the statistics of the wind samples of a window, as calculated by
+// meteorology.CalculateWindStatistics, limited to the mean speeds and direction, and the maximum gust.
+type windWindow struct {
+	entries []windEntry
+	// gusts are the entries which may become the maximum gust, in descending order of gust
+	gusts []windEntry
+	// sums of the speeds and the wind vectors, in m/s, and of the unit vectors for when all samples are calm
+	sum, u, v, su, sv float64
+	moving            int
+	evicted           int
+}
+
+func (w *windWindow) add(e windEntry) {
+	w.entries = append(w.entries, e)
+	w.accumulate(e, 1)
+
+	for len(w.gusts) > 0 && w.gusts[len(w.gusts)-1].gust <= e.gust {
+		w.gusts = w.gusts[:len(w.gusts)-1]
+	}
+	w.gusts = append(w.gusts, e)
+}
+
+func (w *windWindow) accumulate(e windEntry, sign float64) {
+	mps := e.speed.MetersPerSecond()
+	sin, cos := math.Sincos(e.direction.Radians())
+	w.sum += sign * mps
+	w.u += sign * mps * sin
+	w.v += sign * mps * cos
+	w.su += sign * sin
+	w.sv += sign * cos
+	if mps != 0 {
+		w.moving += int(sign)
+	}
+}
+
+// evict removes the samples prior to cutoff.
+func (w *windWindow) evict(cutoff time.Time) {
+	for len(w.entries) > 0 && w.entries[0].t.Before(cutoff) {
+		w.accumulate(w.entries[0], -1)
+		w.entries = w.entries[1:]
+		w.evicted++
+	}
+	for len(w.gusts) > 0 && w.gusts[0].t.Before(cutoff) {
+		w.gusts = w.gusts[1:]
+	}
+
+	if w.evicted > 0 && w.evicted >= len(w.entries) {
+		w.evicted, w.sum, w.u, w.v, w.su, w.sv, w.moving = 0, 0, 0, 0, 0, 0, 0
+		for _, e := range w.entries {
+			w.accumulate(e, 1)
+		}
+	}
+}
+
+func (w *windWindow) statistics() meteorology.WindStatistics {
+	ws := meteorology.WindStatistics{Samples: len(w.entries)}
+	if len(w.entries) == 0 {
+		return ws
+	}
+
+	count := float64(len(w.entries))
+	ws.ScalarMeanSpeed = unit.Speed(w.sum/count) * unit.MetersPerSecond
+	ws.VectorMeanSpeed = unit.Speed(math.Hypot(w.u, w.v)/count) * unit.MetersPerSecond
+	ws.MaxGust = w.gusts[0].gust
+
+	u, v := w.u, w.v
+	if w.moving == 0 {
+		// all samples are calm, so use the mean of the directions
+		u, v = w.su, w.sv
+	}
+	deg := math.Mod(math.Atan2(u, v)*180/math.Pi, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	ws.VectorMeanDirection = unit.Angle(deg) * unit.Degree
+
+	return ws
+}
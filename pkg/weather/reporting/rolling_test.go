@@ -0,0 +1,164 @@
+package reporting
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// variableObservations returns observations every 5 minutes for two days from start, with
+// varying and occasionally calm wind, a counter reset and spike of the total rain, and missing observations.
+func variableObservations(start time.Time) []model.Observation {
+	obs := append(observationsForDay(start), observationsForDay(start.AddDate(0, 0, 1))...)
+	res := obs[:0]
+	for i, o := range obs {
+		if i%47 == 13 {
+			continue
+		}
+		f := float64(i)
+		o.Timestamp = o.Timestamp.Add(time.Duration(i%7) * time.Second)
+		o.WindSpeed = unit.Speed(math.Max(0, 12*math.Sin(f/11))) * unit.KilometersPerHour
+		o.WindGust = o.WindSpeed + unit.Speed(i%5)*unit.KilometersPerHour
+		o.WindDir = unit.Angle(math.Mod(f*37, 360)) * unit.Degree
+		o.TempOutdoor = unit.FromCelsius(10 + 8*math.Sin(f/30) + float64(i%3))
+		o.RainRatePerHour = unit.Length(math.Max(0, 20*math.Sin(f/50))) * unit.Millimeter
+		switch {
+		case i > 400:
+			o.TotalRain -= 100 * unit.Millimeter
+		case i == 250:
+			o.TotalRain += 100 * unit.Millimeter
+		}
+		res = append(res, o)
+	}
+	return res
+}
+
+func TestReporter_Generate_RollingStatistics(t *testing.T) {
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local)
+	obs := variableObservations(day)
+	warmAt := 200
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "weather.db")), &gorm.Config{})
+	require.NoError(t, err)
+
+	bus := event.New()
+	st, err := store.New(db, bus)
+	require.NoError(t, err)
+	_, err = st.WriteObservations(obs[:warmAt])
+	require.NoError(t, err)
+
+	// the archived days and normals of the prior days
+	require.NoError(t, st.WriteDailySummary(model.DailySummary{
+		Date: day.AddDate(0, 0, -2), TempHi: unit.FromCelsius(20), TempLo: unit.FromCelsius(8), Rain: 5 * unit.Millimeter,
+	}))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, st.WriteDailySummary(model.DailySummary{
+			Date:   day.AddDate(-1, 0, i),
+			TempHi: unit.FromCelsius(float64(12 + i)),
+			TempLo: unit.FromCelsius(float64(2 + i)),
+			Rain:   unit.Length(i) * unit.Millimeter,
+		}))
+	}
+	require.NoError(t, st.WriteClimateNormals([]model.ClimateNormal{
+		{Month: time.November, TempMean: unit.FromCelsius(12), Rain: 50 * unit.Millimeter},
+		{Month: time.December, TempHiMean: unit.FromCelsius(20), TempLoMean: unit.FromCelsius(9), TempMean: unit.FromCelsius(14), Rain: 60 * unit.Millimeter},
+	}))
+
+	// count the queries of the store
+	var queries int
+	count := func(*gorm.DB) { queries++ }
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:count", count))
+	require.NoError(t, db.Callback().Row().Before("gorm:row").Register("test:count", count))
+
+	vp := viper.New()
+	vp.Set("calendar.day_start_hour", 9)
+	vp.Set("location.latitude", -41.44)
+	vp.Set("location.longitude", 147.23)
+
+	want, err := New(zap.NewNop(), vp, st)
+	require.NoError(t, err)
+	got, err := New(zap.NewNop(), vp, st, WithRollingStatistics(bus))
+	require.NoError(t, err)
+	got.rolling.Warm(obs[warmAt-1].Timestamp)
+
+	for i := warmAt; i < len(obs); i++ {
+		_, err := st.WriteObservation(obs[i])
+		require.NoError(t, err)
+		if i%6 != 0 {
+			continue
+		}
+
+		ts := obs[i].Timestamp.Add(17 * time.Second)
+		require.NotNil(t, got.rolling.Snapshot(ts, got.cal.BeginningOfDay(ts)))
+
+		a := want.Generate(ts)
+		queries = 0
+		b := got.Generate(ts)
+		assert.Zero(t, queries, "queries of the store whilst generating from the rolling statistics", ts)
+
+		assert.Equal(t, a.OutdoorTemperature, b.OutdoorTemperature, ts)
+		assert.InDelta(t, a.WindRun.Meters(), b.WindRun.Meters(), 1, ts)
+		assert.InDelta(t, a.PressureTrend.Hectopascals(), b.PressureTrend.Hectopascals(), 1e-6, ts)
+		assert.InDelta(t, a.TempTrend.Celsius(), b.TempTrend.Celsius(), 1e-6, ts)
+		assert.InDelta(t, a.PressureChange.Hectopascals(), b.PressureChange.Hectopascals(), 1e-6, ts)
+		assert.Equal(t, a.PressureTendency, b.PressureTendency, ts)
+		assert.Equal(t, a.TodayTempHiTime, b.TodayTempHiTime, ts)
+		assert.InDelta(t, a.TodayTempHi.Celsius(), b.TodayTempHi.Celsius(), 1e-9, ts)
+		assert.Equal(t, a.TodayTempLoTime, b.TodayTempLoTime, ts)
+		assert.Equal(t, a.TodayWindHiTime, b.TodayWindHiTime, ts)
+		assert.Equal(t, a.TodayWindGustHiTime, b.TodayWindGustHiTime, ts)
+		assert.InDelta(t, a.TodayWindGustHi.KilometersPerHour(), b.TodayWindGustHi.KilometersPerHour(), 1e-9, ts)
+		assert.Equal(t, a.TodayPressureHiTime, b.TodayPressureHiTime, ts)
+		assert.Equal(t, a.TodayPressureLoTime, b.TodayPressureLoTime, ts)
+		assert.Equal(t, a.TodayRainRateHiTime, b.TodayRainRateHiTime, ts)
+		assert.InDelta(t, a.TodayRainRateHi.Millimeters(), b.TodayRainRateHi.Millimeters(), 1e-9, ts)
+		assert.Equal(t, a.Date, b.Date, ts)
+		assert.InDelta(t, a.TenMinGustHi.KilometersPerHour(), b.TenMinGustHi.KilometersPerHour(), 1e-9, ts)
+		assert.InDelta(t, a.WindSpeedAvg.KilometersPerHour(), b.WindSpeedAvg.KilometersPerHour(), 1e-9, ts)
+		assert.InDelta(t, a.TenMinWindBearingAvg.Degrees(), b.TenMinWindBearingAvg.Degrees(), 1e-6, ts)
+		assert.InDelta(t, a.RainfallLastHour.Millimeters(), b.RainfallLastHour.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.RainfallLast24Hours.Millimeters(), b.RainfallLast24Hours.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.RainfallToday.Millimeters(), b.RainfallToday.Millimeters(), 1e-6, ts)
+		lastHour, today := got.Rainfall(ts)
+		assert.InDelta(t, a.RainfallLastHour.Millimeters(), lastHour.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.RainfallToday.Millimeters(), today.Millimeters(), 1e-6, ts)
+		lastHour, today = want.Rainfall(ts)
+		assert.InDelta(t, a.RainfallLastHour.Millimeters(), lastHour.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.RainfallToday.Millimeters(), today.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.YesterdayRainfall.Millimeters(), b.YesterdayRainfall.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.MonthlyRainfall.Millimeters(), b.MonthlyRainfall.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.SeasonRainfall.Millimeters(), b.SeasonRainfall.Millimeters(), 1e-6, ts)
+		assert.InDelta(t, a.YearlyRainfall.Millimeters(), b.YearlyRainfall.Millimeters(), 1e-6, ts)
+		assert.Equal(t, a.SunshineHoursToday, b.SunshineHoursToday, ts)
+		assert.InDelta(t, a.Evapotranspiration.Millimeters(), b.Evapotranspiration.Millimeters(), 1e-9, ts)
+		assert.InDelta(t, a.HeatingDegreeDaysToday, b.HeatingDegreeDaysToday, 1e-9, ts)
+		assert.InDelta(t, a.HeatingDegreeDaysToDate, b.HeatingDegreeDaysToDate, 1e-9, ts)
+		assert.InDelta(t, a.GrowingDegreeDaysToDate, b.GrowingDegreeDaysToDate, 1e-9, ts)
+		assert.InDelta(t, a.TodayTempHiAnomaly.Celsius(), b.TodayTempHiAnomaly.Celsius(), 1e-9, ts)
+		assert.Equal(t, a.TodayTempLoPercentile, b.TodayTempLoPercentile, ts)
+		assert.InDelta(t, a.MonthTempAnomaly.Celsius(), b.MonthTempAnomaly.Celsius(), 1e-9, ts)
+		assert.InDelta(t, a.MonthRainfallPercent, b.MonthRainfallPercent, 1e-6, ts)
+	}
+
+	last := obs[len(obs)-1].Timestamp
+	assert.Nil(t, got.rolling.Snapshot(last.Add(-time.Minute), got.cal.BeginningOfDay(last)), "prior to the last observation")
+
+	// an observation out of order is included by warming again
+	_, err = st.WriteObservation(model.Observation{Timestamp: last.Add(-time.Minute), TempOutdoor: unit.FromCelsius(40)})
+	require.NoError(t, err)
+	ts := last.Add(time.Minute)
+	assert.Equal(t, want.Generate(ts).TodayTempHiTime, got.Generate(ts).TodayTempHiTime)
+	assert.InDelta(t, 40, got.Generate(ts).TodayTempHi.Celsius(), 0.01)
+}
@@ -0,0 +1,100 @@
+package reporting
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+var (
+	ErrNoObservations = errors.New("no observations")
+)
+
+// Summarize calculates the summary statistics for the meteorological day containing t,
+// from the observations recorded during that day.
+func (r *Reporter) Summarize(t time.Time) (*model.DailySummary, error) {
+	start, end := r.cal.Day(t)
+
+	var count int64
+	tx := r.store.DB().Model(&store.Observation{}).
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Count(&count)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if count == 0 {
+		return nil, ErrNoObservations
+	}
+
+	// the period for limits is inclusive of end, so exclude the first observation of the following day
+	dur := end.Sub(start) - time.Second
+	ds := &model.DailySummary{Date: r.cal.Date(t)}
+	var val float64
+
+	ds.TempHiTime, val = r.calcLimitAndTimeForPeriod("temp_outdoor_c", limitMax, start, dur)
+	ds.TempHi = unit.FromCelsius(val)
+	ds.TempLoTime, val = r.calcLimitAndTimeForPeriod("temp_outdoor_c", limitMin, start, dur)
+	ds.TempLo = unit.FromCelsius(val)
+	ds.TempAvg = unit.FromCelsius(r.calcStatForPeriod("temp_outdoor_c", "AVG", start, dur))
+	ds.PressureHiTime, val = r.calcLimitAndTimeForPeriod(r.barometricCol, limitMax, start, dur)
+	ds.PressureHi = unit.Pressure(val) * unit.Hectopascal
+	ds.PressureLoTime, val = r.calcLimitAndTimeForPeriod(r.barometricCol, limitMin, start, dur)
+	ds.PressureLo = unit.Pressure(val) * unit.Hectopascal
+	ds.Rain = r.rainfall(start, end)
+	ds.RainRateHiTime, val = r.calcLimitAndTimeForPeriod("rain_rate_per_hour_mm", limitMax, start, dur)
+	ds.RainRateHi = unit.Length(val) * unit.Millimeter
+	ds.WindGustHiTime, val = r.calcLimitAndTimeForPeriod("wind_gust_kph", limitMax, start, dur)
+	ds.WindGustHi = unit.Speed(val) * unit.KilometersPerHour
+	ds.WindSpeedHiTime, val = r.calcLimitAndTimeForPeriod("wind_speed_kph", limitMax, start, dur)
+	ds.WindSpeedHi = unit.Speed(val) * unit.KilometersPerHour
+	ds.WindRun = r.windRun(start, end)
+	ds.DominantWindDir = r.WindStatistics(start, end).VectorMeanDirection
+	ds.HumidityHi = int(r.calcStatForPeriod("humidity_outdoor_pct", "MAX", start, dur)*100 + 0.5)
+	ds.HumidityLo = int(r.calcStatForPeriod("humidity_outdoor_pct", "MIN", start, dur)*100 + 0.5)
+	ds.Evapotranspiration = r.evapotranspiration(start, end)
+	ds.SunshineHours = r.sunshine(start, end)
+	ds.SolarRadiationHi = xunit.Irradiance(r.calcStatForPeriod("solar_radiation_wm2", "MAX", start, dur)) * xunit.WattPerSquareMetre
+	ds.UltravioletIndexHi = int(r.calcStatForPeriod("ultraviolet_index", "MAX", start, dur))
+	ds.HeatingDegreeDays, ds.CoolingDegreeDays, ds.GrowingDegreeDays = r.degreeDaysFor(ds.TempLo, ds.TempHi)
+
+	return ds, nil
+}
+
+// DailySummaries returns the summaries for the meteorological days from the date of start up to,
+// but excluding, the date of end. Days which have not been archived are summarized from the observations.
+func (r *Reporter) DailySummaries(start, end time.Time) ([]*model.DailySummary, error) {
+	stored, err := r.store.DailySummaries(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	const layout = "2006-01-02"
+	byDate := make(map[string]*model.DailySummary, len(stored))
+	for _, ds := range stored {
+		byDate[ds.Date.Format(layout)] = ds
+	}
+
+	var res []*model.DailySummary
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if ds, ok := byDate[d.Format(layout)]; ok {
+			res = append(res, ds)
+			continue
+		}
+
+		ds, err := r.Summarize(r.cal.Start(d))
+		if errors.Is(err, ErrNoObservations) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ds)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+)
+
+// windSamples returns the wind measurements for the observations from start up to, but excluding, end.
+func (r *Reporter) windSamples(start, end time.Time) []meteorology.WindSample {
+	var rows []struct {
+		Speed     float64
+		Gust      float64
+		Direction float64
+	}
+
+	r.store.DB().Model(&store.Observation{}).
+		Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Select("wind_speed_kph as speed, wind_gust_kph as gust, wind_dir_deg as direction").
+		Order("timestamp").
+		Find(&rows)
+
+	samples := make([]meteorology.WindSample, 0, len(rows))
+	for _, row := range rows {
+		samples = append(samples, meteorology.WindSample{
+			Speed:     unit.Speed(row.Speed) * unit.KilometersPerHour,
+			Gust:      unit.Speed(row.Gust) * unit.KilometersPerHour,
+			Direction: unit.Angle(row.Direction) * unit.Degree,
+		})
+	}
+
+	return samples
+}
+
+// WindStatistics calculates the wind statistics for the observations from start up to, but excluding, end.
+func (r *Reporter) WindStatistics(start, end time.Time) meteorology.WindStatistics {
+	return meteorology.CalculateWindStatistics(r.windSamples(start, end))
+}
+
+// WindRose calculates the wind rose for the observations from start up to, but excluding, end,
+// using the speed classes.
+func (r *Reporter) WindRose(start, end time.Time, classes []unit.Speed) meteorology.WindRose {
+	return meteorology.CalculateWindRose(r.windSamples(start, end), classes)
+}
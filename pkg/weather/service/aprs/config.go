@@ -0,0 +1,23 @@
+package aprs
+
+import (
+	"time"
+)
+
+type Config struct {
+	Enabled  bool
+	Server   string        // Server is the host:port of the APRS-IS server
+	Callsign string        // Callsign is the callsign or CWOP station ID, such as CW1234
+	Passcode int           // Passcode is the APRS-IS passcode, or -1 for CWOP stations without a callsign
+	Interval time.Duration // Interval is the minimum time between reports
+	Timeout  time.Duration // Timeout is the maximum time to connect, login and send a report
+}
+
+func NewConfig() Config {
+	return Config{
+		Server:   "cwop.aprs.net:14580",
+		Passcode: -1,
+		Interval: 10 * time.Minute,
+		Timeout:  30 * time.Second,
+	}
+}
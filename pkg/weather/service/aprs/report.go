@@ -0,0 +1,110 @@
+package aprs
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/xmath"
+	"github.com/martinlindhe/unit"
+)
+
+// Report is an APRS complete weather report, with the position and timestamp of the station,
+// per chapter 12 of the APRS protocol reference.
+type Report struct {
+	Callsign            string
+	Latitude, Longitude float64
+	Stats               *reporting.Statistics
+	RainSinceMidnight   unit.Length
+}
+
+// MarshalText returns the report as an APRS-IS packet, without the trailing line break, such as
+//
+//	CW1234>APRS,TCPIP*:@011430z4126.40S/14713.80E_270/005g012t057r001p010P005h82b10132L450
+func (r Report) MarshalText() ([]byte, error) {
+	s := r.Stats
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s>APRS,TCPIP*:@%s", r.Callsign, s.Timestamp.UTC().Format("021504z"))
+	b.WriteString(latitude(r.Latitude))
+	b.WriteByte('/')
+	b.WriteString(longitude(r.Longitude))
+
+	// the wind direction is 360 for north, as 000 is calm
+	speed := round(s.WindSpeedAvg.MilesPerHour())
+	dir := round(s.TenMinWindBearingAvg.Degrees())
+	if speed > 0 && dir%360 == 0 {
+		dir = 360
+	} else if speed == 0 {
+		dir = 0
+	}
+	fmt.Fprintf(&b, "_%03d/%03d", dir, speed)
+	fmt.Fprintf(&b, "g%03d", round(s.TenMinGustHi.MilesPerHour()))
+
+	if s.OutdoorTemperature == 0 {
+		b.WriteString("t...")
+	} else {
+		fmt.Fprintf(&b, "t%03d", round(s.OutdoorTemperature.Fahrenheit()))
+	}
+
+	fmt.Fprintf(&b, "r%03d", hundredthsOfInch(s.RainfallLastHour))
+	fmt.Fprintf(&b, "p%03d", hundredthsOfInch(s.RainfallLast24Hours))
+	fmt.Fprintf(&b, "P%03d", hundredthsOfInch(r.RainSinceMidnight))
+
+	switch h := s.OutdoorHumidity; {
+	case h <= 0:
+	case h >= 100:
+		b.WriteString("h00")
+	default:
+		fmt.Fprintf(&b, "h%02d", h)
+	}
+
+	if s.BarometricPressure > 0 {
+		fmt.Fprintf(&b, "b%05d", round(s.BarometricPressure.Hectopascals()*10))
+	}
+
+	if l := round(s.SolarRadiation.WattsPerSquareMetre()); l < 1000 {
+		fmt.Fprintf(&b, "L%03d", xmath.Max(l, 0))
+	} else {
+		fmt.Fprintf(&b, "l%03d", xmath.Min(l-1000, 999))
+	}
+
+	return b.Bytes(), nil
+}
+
+// latitude returns lat as degrees and minutes to two decimal places, such as 4126.40S.
+func latitude(lat float64) string {
+	hemisphere := 'N'
+	if lat < 0 {
+		hemisphere = 'S'
+	}
+	deg, minutes := degreesMinutes(lat)
+	return fmt.Sprintf("%02d%05.2f%c", deg, minutes, hemisphere)
+}
+
+// longitude returns long as degrees and minutes to two decimal places, such as 14713.80E.
+func longitude(long float64) string {
+	hemisphere := 'E'
+	if long < 0 {
+		hemisphere = 'W'
+	}
+	deg, minutes := degreesMinutes(long)
+	return fmt.Sprintf("%03d%05.2f%c", deg, minutes, hemisphere)
+}
+
+// degreesMinutes returns the whole degrees and minutes of the absolute value of v, rounding to
+// hundredths of a minute before splitting, so 59.999' is reported as the next degree rather than 60.00'.
+func degreesMinutes(v float64) (int, float64) {
+	h := round(math.Abs(v) * 6000)
+	return h / 6000, float64(h%6000) / 100
+}
+
+// hundredthsOfInch returns v in hundredths of an inch, limited to the three digits of the field.
+func hundredthsOfInch(v unit.Length) int {
+	return xmath.Min(xmath.Max(round(v.Inches()*100), 0), 999)
+}
+
+func round(v float64) int {
+	return int(math.Round(v))
+}
@@ -0,0 +1,95 @@
+package aprs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_MarshalText(t *testing.T) {
+	stats := func() *reporting.Statistics {
+		return &reporting.Statistics{
+			Timestamp:            time.Date(2021, 12, 1, 14, 30, 12, 0, time.UTC),
+			OutdoorTemperature:   unit.FromCelsius(14),
+			OutdoorHumidity:      82,
+			WindSpeedAvg:         8 * unit.KilometersPerHour,
+			TenMinWindBearingAvg: 270 * unit.Degree,
+			TenMinGustHi:         19.3 * unit.KilometersPerHour,
+			RainfallLastHour:     0.254 * unit.Millimeter,
+			RainfallLast24Hours:  2.54 * unit.Millimeter,
+			BarometricPressure:   1013.2 * unit.Hectopascal,
+			SolarRadiation:       450 * xunit.WattPerSquareMetre,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *Report)
+		want   string
+	}{
+		{
+			name: "report",
+			want: "CW1234>APRS,TCPIP*:@011430z4126.40S/14713.80E_270/005g012t057r001p010P005h82b10132L450",
+		},
+		{
+			name: "north wind",
+			modify: func(r *Report) {
+				r.Stats.TenMinWindBearingAvg = 359.8 * unit.Degree
+			},
+			want: "CW1234>APRS,TCPIP*:@011430z4126.40S/14713.80E_360/005g012t057r001p010P005h82b10132L450",
+		},
+		{
+			name: "calm",
+			modify: func(r *Report) {
+				r.Stats.WindSpeedAvg, r.Stats.TenMinGustHi = 0, 0
+			},
+			want: "CW1234>APRS,TCPIP*:@011430z4126.40S/14713.80E_000/000g000t057r001p010P005h82b10132L450",
+		},
+		{
+			name: "extremes",
+			modify: func(r *Report) {
+				r.Stats.OutdoorTemperature = unit.FromCelsius(-20.6)
+				r.Stats.OutdoorHumidity = 100
+				r.Stats.RainfallLast24Hours = 300 * unit.Millimeter
+				r.Stats.SolarRadiation = 1234 * xunit.WattPerSquareMetre
+			},
+			want: "CW1234>APRS,TCPIP*:@011430z4126.40S/14713.80E_270/005g012t-05r001p999P005h00b10132l234",
+		},
+		{
+			name: "missing",
+			modify: func(r *Report) {
+				r.Stats.OutdoorTemperature, r.Stats.OutdoorHumidity, r.Stats.BarometricPressure = 0, 0, 0
+			},
+			want: "CW1234>APRS,TCPIP*:@011430z4126.40S/14713.80E_270/005g012t...r001p010P005L450",
+		},
+		{
+			name: "north west",
+			modify: func(r *Report) {
+				r.Latitude, r.Longitude = 51.99999, -0.5
+			},
+			want: "CW1234>APRS,TCPIP*:@011430z5200.00N/00030.00W_270/005g012t057r001p010P005h82b10132L450",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Report{
+				Callsign:          "CW1234",
+				Latitude:          -41.44,
+				Longitude:         147.23,
+				Stats:             stats(),
+				RainSinceMidnight: 1.27 * unit.Millimeter,
+			}
+			if tc.modify != nil {
+				tc.modify(&r)
+			}
+			got, err := r.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
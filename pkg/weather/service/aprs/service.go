@@ -0,0 +1,233 @@
+// Package aprs is responsible for uploading weather reports to an APRS-IS server,
+// such as those of the Citizen Weather Observer Program (CWOP).
+package aprs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/service/realtime"
+	"github.com/mitchellh/mapstructure"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	aprsReports = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "weather",
+		Subsystem: "aprs",
+		Name:      "reports_total",
+		Help:      "The total number of APRS weather reports sent",
+	}, []string{"result"})
+)
+
+const (
+	// minInterval is the minimum interval between reports accepted by CWOP.
+	minInterval = 5 * time.Minute
+
+	// software identifies the software when logging in to the APRS-IS server.
+	software = "lmacrc-weather 1.0"
+)
+
+// Reporter provides the rain since midnight, as the rain day may start at another hour.
+type Reporter interface {
+	History(start, end time.Time, interval time.Duration) ([]reporting.Sample, error)
+}
+
+type Service struct {
+	log       *zap.Logger
+	reporter  Reporter
+	cal       *calendar.Calendar
+	server    string
+	callsign  string
+	passcode  int
+	interval  time.Duration
+	timeout   time.Duration
+	lat, long float64
+	ch        chan *reporting.Statistics
+
+	mu   sync.Mutex
+	last time.Time // last is the time of the statistics of the last report sent
+}
+
+func New(log *zap.Logger, v *viper.Viper, reporter Reporter, bus *event.Bus) (*Service, error) {
+	cfg := NewConfig()
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToTimeDurationHookFunc(),
+	))
+	if err := v.UnmarshalKey("aprs", &cfg, hook); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if cfg.Callsign == "" {
+		return nil, errors.New("config: aprs callsign is required")
+	}
+	if cfg.Interval < minInterval {
+		return nil, fmt.Errorf("config: aprs interval %s is less than %s", cfg.Interval, minInterval)
+	}
+
+	cal, err := calendar.New(v)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := struct {
+		Latitude, Longitude float64
+	}{}
+	if err := v.UnmarshalKey("location", &loc); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	s := &Service{
+		log:      log.With(zap.String("service", "aprs")),
+		reporter: reporter,
+		cal:      cal,
+		server:   cfg.Server,
+		callsign: strings.ToUpper(cfg.Callsign),
+		passcode: cfg.Passcode,
+		interval: cfg.Interval,
+		timeout:  cfg.Timeout,
+		lat:      loc.Latitude,
+		long:     loc.Longitude,
+		ch:       make(chan *reporting.Statistics, 1),
+	}
+
+	bus.MustSubscribe(realtime.NewStatistics, s.HandleStatistics)
+
+	return s, nil
+}
+
+// HandleStatistics queues the statistics for a report, when the interval has elapsed since
+// the last report. The report is sent asynchronously, so the realtime service is not delayed.
+func (s *Service) HandleStatistics(stats *reporting.Statistics) {
+	if stats.SensorContactLost {
+		return
+	}
+
+	s.mu.Lock()
+	due := service.IntervalElapsed(s.last, stats.Timestamp, s.interval, minInterval)
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	select {
+	case s.ch <- stats:
+	default:
+		s.log.Warn("Still sending the previous report, skipping.")
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	s.log.Info("Starting.")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Shutting down.")
+			return
+
+		case stats := <-s.ch:
+			if err := s.Send(ctx, stats); err != nil {
+				aprsReports.WithLabelValues("error").Inc()
+				s.log.Error("Failed to send APRS report.", zap.Error(err))
+				continue
+			}
+			aprsReports.WithLabelValues("sent").Inc()
+		}
+	}
+}
+
+// Send logs in to the APRS-IS server and sends the report for stats.
+func (s *Service) Send(ctx context.Context, stats *reporting.Statistics) error {
+	report, err := s.Report(stats)
+	if err != nil {
+		return err
+	}
+	packet, err := report.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.server)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	r := bufio.NewReader(conn)
+	if _, err := r.ReadString('\n'); err != nil {
+		return fmt.Errorf("reading banner: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(conn, "user %s pass %d vers %s\r\n", s.callsign, s.passcode, software); err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+	// the server responds with a comment, such as # logresp CW1234 unverified, server CWOP-1
+	resp, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading login response: %w", err)
+	}
+	if !strings.Contains(resp, "logresp") {
+		return fmt.Errorf("login: unexpected response %q", strings.TrimSpace(resp))
+	}
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", packet); err != nil {
+		return fmt.Errorf("sending report: %w", err)
+	}
+
+	s.mu.Lock()
+	if stats.Timestamp.After(s.last) {
+		s.last = stats.Timestamp
+	}
+	s.mu.Unlock()
+
+	s.log.Debug("APRS report sent.", zap.ByteString("packet", packet))
+	return nil
+}
+
+// Report returns the report for stats.
+func (s *Service) Report(stats *reporting.Statistics) (Report, error) {
+	// include the observation at the time of the statistics
+	ts := s.cal.In(stats.Timestamp)
+	end := ts.Add(time.Second)
+	start := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location())
+
+	report := Report{
+		Callsign:  s.callsign,
+		Latitude:  s.lat,
+		Longitude: s.long,
+		Stats:     stats,
+	}
+
+	res, err := s.reporter.History(start, end, end.Sub(start))
+	if err != nil {
+		return report, fmt.Errorf("history: %w", err)
+	}
+	if len(res) > 0 {
+		report.RainSinceMidnight = res[0].Rain
+	}
+	return report, nil
+}
@@ -0,0 +1,129 @@
+package aprs
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeReporter struct{}
+
+func (fakeReporter) History(start, end time.Time, interval time.Duration) ([]reporting.Sample, error) {
+	return []reporting.Sample{{Time: start, Observations: 1, Rain: 1.27 * unit.Millimeter}}, nil
+}
+
+// fakeServer is a stand-in for an APRS-IS server, which sends the lines received
+// after the banner, or the error reading them, to the returned channel.
+func fakeServer(t *testing.T, logresp string) (string, <-chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	ch := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("# aprsc 2.1.10\r\n"))
+		var lines []string
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				ch <- lines
+				return
+			}
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+			if len(lines) == 1 {
+				_, _ = conn.Write([]byte(logresp + "\r\n"))
+			}
+		}
+	}()
+	return l.Addr().String(), ch
+}
+
+func newService(t *testing.T, server string) *Service {
+	vp := viper.New()
+	vp.Set("location.latitude", -41.44)
+	vp.Set("location.longitude", 147.23)
+	vp.Set("location.timezone", "Australia/Hobart")
+	vp.Set("aprs.server", server)
+	vp.Set("aprs.callsign", "cw1234")
+	vp.Set("aprs.timeout", "5s")
+
+	s, err := New(zap.NewNop(), vp, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	return s
+}
+
+func TestService_Send(t *testing.T) {
+	server, ch := fakeServer(t, "# logresp CW1234 unverified, server CWOP-1")
+	s := newService(t, server)
+
+	stats := &reporting.Statistics{
+		Timestamp:          time.Date(2021, 12, 1, 14, 30, 12, 0, time.UTC),
+		OutdoorTemperature: unit.FromCelsius(14),
+		OutdoorHumidity:    82,
+		BarometricPressure: 1013.2 * unit.Hectopascal,
+	}
+	require.NoError(t, s.Send(context.Background(), stats))
+
+	assert.Equal(t, []string{
+		"user CW1234 pass -1 vers lmacrc-weather 1.0",
+		"CW1234>APRS,TCPIP*:@011430z4126.40S/14713.80E_000/000g000t057r000p000P005h82b10132L000",
+	}, <-ch)
+	assert.Equal(t, stats.Timestamp, s.last)
+}
+
+func TestService_Send_LoginRejected(t *testing.T) {
+	server, ch := fakeServer(t, "# port full")
+	s := newService(t, server)
+
+	err := s.Send(context.Background(), &reporting.Statistics{Timestamp: time.Now()})
+	assert.EqualError(t, err, `login: unexpected response "# port full"`)
+	assert.Len(t, <-ch, 1, "only the login is sent")
+	assert.True(t, s.last.IsZero())
+}
+
+func TestService_HandleStatistics(t *testing.T) {
+	s := newService(t, "127.0.0.1:0")
+	ts := time.Date(2021, 12, 1, 14, 30, 12, 0, time.UTC)
+	s.last = ts
+
+	s.HandleStatistics(&reporting.Statistics{Timestamp: ts.Add(5 * time.Minute)})
+	assert.Empty(t, s.ch, "within the interval")
+
+	// CWOP rejects reports of a station whose sensors are not reporting
+	s.HandleStatistics(&reporting.Statistics{Timestamp: ts.Add(20 * time.Minute), SensorContactLost: true})
+	assert.Empty(t, s.ch, "sensor contact lost")
+
+	stats := &reporting.Statistics{Timestamp: ts.Add(20 * time.Minute)}
+	s.HandleStatistics(stats)
+	require.Len(t, s.ch, 1)
+	assert.Same(t, stats, <-s.ch)
+}
+
+func TestNew_Config(t *testing.T) {
+	vp := viper.New()
+	_, err := New(zap.NewNop(), vp, fakeReporter{}, event.New())
+	assert.EqualError(t, err, "config: aprs callsign is required")
+
+	vp.Set("aprs.callsign", "CW1234")
+	vp.Set("aprs.interval", "1m")
+	_, err = New(zap.NewNop(), vp, fakeReporter{}, event.New())
+	assert.EqualError(t, err, "config: aprs interval 1m0s is less than 5m0s")
+}
@@ -10,9 +10,12 @@ import (
 	"time"
 
 	"github.com/gocarina/gocsv"
-	"github.com/jinzhu/now"
 	"github.com/lmacrc/weather/pkg/compress/brotli"
 	"github.com/lmacrc/weather/pkg/filepath/template"
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
 	"github.com/lmacrc/weather/pkg/weather/service"
 	"github.com/lmacrc/weather/pkg/weather/store"
 	"github.com/mitchellh/mapstructure"
@@ -21,12 +24,33 @@ import (
 	"gorm.io/gorm"
 )
 
+// Summarizer calculates the summary statistics for the day of t.
+type Summarizer interface {
+	Summarize(t time.Time) (*model.DailySummary, error)
+}
+
+type optionFn func(s *Service)
+
+// WithSummarizer specifies a Summarizer to record the daily summary
+// prior to archiving the observations for each day.
+func WithSummarizer(summarizer Summarizer) optionFn {
+	if summarizer == nil {
+		panic("summarizer == nil")
+	}
+
+	return func(s *Service) {
+		s.summarizer = summarizer
+	}
+}
+
 type Service struct {
 	log         *zap.Logger
 	db          *gorm.DB
 	store       *store.Store
 	compression Compression
 	ftp         service.Ftp
+	summarizer  Summarizer
+	cal         *calendar.Calendar
 
 	localDir  string
 	remoteDir string
@@ -37,12 +61,17 @@ func InitViper(v *viper.Viper) {
 	v.SetDefault("archive.compression", CompressionGzip)
 }
 
-func New(log *zap.Logger, db *gorm.DB, v *viper.Viper, s *store.Store, ftp service.Ftp) (*Service, error) {
+func New(log *zap.Logger, db *gorm.DB, v *viper.Viper, s *store.Store, ftp service.Ftp, opts ...optionFn) (*Service, error) {
 	var cfg Config
 	if err := v.UnmarshalKey("archive", &cfg, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
 		return nil, fmt.Errorf("config: %w", err)
 	}
 
+	cal, err := calendar.New(v)
+	if err != nil {
+		return nil, err
+	}
+
 	a := &Service{
 		log:         log,
 		db:          db,
@@ -52,6 +81,11 @@ func New(log *zap.Logger, db *gorm.DB, v *viper.Viper, s *store.Store, ftp servi
 		localDir:    cfg.LocalDir,
 		remoteDir:   cfg.RemoteDir,
 		filename:    template.Must(template.New("file").Parse(cfg.Filename)),
+		cal:         cal,
+	}
+
+	for _, fn := range opts {
+		fn(a)
 	}
 
 	return a, nil
@@ -67,9 +101,9 @@ func (s *Service) Run() {
 	}
 }
 
-// ArchiveAll archives all days prior to now
+// ArchiveAll archives all meteorological days prior to the current day
 func (s *Service) ArchiveAll() error {
-	last := now.BeginningOfDay()
+	last := s.cal.BeginningOfDay(time.Now())
 	s.log.Info("Archiving all data prior to today.", zap.Time("date", last))
 
 	dates, err := s.findAllDates(last)
@@ -112,37 +146,52 @@ func (s *Service) ArchiveAll() error {
 	return nil
 }
 
+// findAllDates returns the dates of the meteorological days with observations prior to t.
 func (s *Service) findAllDates(t time.Time) ([]time.Time, error) {
 	db := s.store.DB()
-	var dates []string
-	tx := db.Table("observations").
-		Where("timestamp < ?", t.UTC()).
-		Select("strftime('%Y%m%d', datetime(timestamp, 'localtime')) as date").
-		Group("date").
-		Find(&dates)
+
+	var first store.Observation
+	tx := db.Where("timestamp < ?", sqlite.FromTime(t)).
+		Order("timestamp").
+		Limit(1).
+		Find(&first)
+	if tx.Error != nil || tx.RowsAffected == 0 {
+		return nil, tx.Error
+	}
 
 	var ts []time.Time
-	for _, ds := range dates {
-		dt, err := time.ParseInLocation("20060102", ds, time.Local)
-		if err != nil {
-			continue
+	for dt := s.cal.Date(first.Timestamp.In(s.cal.Location())); s.cal.Start(dt).Before(t); dt = dt.AddDate(0, 0, 1) {
+		var count int64
+		tx = db.Model(&store.Observation{}).
+			Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(s.cal.Start(dt)), sqlite.FromTime(s.cal.Start(dt.AddDate(0, 0, 1)))).
+			Count(&count)
+		if tx.Error != nil {
+			return nil, tx.Error
+		}
+		if count > 0 {
+			ts = append(ts, dt)
 		}
-		ts = append(ts, dt)
 	}
 
-	return ts, tx.Error
+	return ts, nil
 }
 
 var (
 	ErrNoData = errors.New("no data")
 )
 
-// Archive will archive the data for the day specified by t and return a path to the archived
-// file.
+// Archive will archive the data for the meteorological day identified by the date of t and
+// return a path to the archived file.
 func (s *Service) Archive(t time.Time) (path string, err error) {
-	tt := now.With(t)
-	start := tt.BeginningOfDay()
-	end := start.AddDate(0, 0, 1)
+	start := s.cal.Start(t)
+	end := s.cal.Start(t.AddDate(0, 0, 1))
+
+	if s.summarizer != nil {
+		err = s.summarize(start)
+		if err != nil {
+			return "", err
+		}
+	}
 
 	var rows []*store.Observation
 	rows, err = s.findRows(s.db, start, end)
@@ -154,7 +203,7 @@ func (s *Service) Archive(t time.Time) (path string, err error) {
 		return "", ErrNoData
 	}
 
-	path = fmt.Sprintf("observations_%s.csv", start.Format("20060102"))
+	path = fmt.Sprintf("observations_%s.csv", t.Format("20060102"))
 	var useBrotli = brotli.IsAvailable() && s.compression == CompressionBrotli
 
 	if useBrotli {
@@ -186,9 +235,26 @@ func (s *Service) Archive(t time.Time) (path string, err error) {
 	return path, nil
 }
 
+// summarize records the daily summary for the day of t, as the observations
+// are no longer available once archived.
+func (s *Service) summarize(t time.Time) error {
+	ds, err := s.summarizer.Summarize(t)
+	if errors.Is(err, reporting.ErrNoObservations) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("summarize: %w", err)
+	}
+
+	if err = s.store.WriteDailySummary(*ds); err != nil {
+		return fmt.Errorf("write summary: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) findRows(tx *gorm.DB, start, end time.Time) ([]*store.Observation, error) {
 	var rows []*store.Observation
-	tx.Where("timestamp >= ? AND timestamp < ?", start.UTC(), end.UTC()).
+	tx.Where("timestamp >= ? AND timestamp < ?", sqlite.FromTime(start), sqlite.FromTime(end)).
 		Order("timestamp").
 		Find(&rows)
 
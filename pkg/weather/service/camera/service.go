@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/lmacrc/weather/pkg/filepath/template"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
 	"github.com/lmacrc/weather/pkg/weather/service"
 	"github.com/mitchellh/mapstructure"
 	"github.com/robfig/cron/v3"
@@ -33,6 +34,7 @@ type Service struct {
 	remoteDir    string
 	filename     *template.Template
 	schedule     cron.Schedule
+	loc          *time.Location
 }
 
 func New(log *zap.Logger, vp *viper.Viper, ftp service.Ftp) (*Service, error) {
@@ -46,6 +48,11 @@ func New(log *zap.Logger, vp *viper.Viper, ftp service.Ftp) (*Service, error) {
 		return nil, fmt.Errorf("parsing cron: %w", err)
 	}
 
+	loc, err := calendar.LoadLocation(vp)
+	if err != nil {
+		return nil, err
+	}
+
 	var camera Capturer
 	if driverFn := drivers[cfg.Driver]; driverFn == nil {
 		return nil, fmt.Errorf("invalid camera driver %q: expect [%s]", cfg.Driver, strings.Join(driverList(), ", "))
@@ -63,6 +70,7 @@ func New(log *zap.Logger, vp *viper.Viper, ftp service.Ftp) (*Service, error) {
 		remoteDir:    cfg.RemoteDir,
 		filename:     template.Must(template.New("file").Parse(cfg.Filename)),
 		schedule:     schedule,
+		loc:          loc,
 	}
 
 	return s, nil
@@ -72,7 +80,7 @@ func (s Service) Run(ctx context.Context) {
 	s.log.Info("Starting.")
 
 	for {
-		ts := time.Now()
+		ts := time.Now().In(s.loc)
 		next := s.schedule.Next(ts)
 		sleep := next.Sub(ts)
 		s.log.Info("Next upload scheduled.", zap.Time("time", next), zap.Duration("wait_time", sleep))
@@ -102,7 +110,10 @@ func (s Service) decodeImage(path string) (image.Image, error) {
 	return img, err
 }
 
+// CaptureImage captures an image, labelled with ts in the time zone of the station, and returns its path.
 func (s Service) CaptureImage(_ context.Context, ts time.Time) (string, error) {
+	ts = ts.In(s.loc)
+
 	path, err := s.camera.Capture(s.params)
 	if err != nil {
 		return "", fmt.Errorf("capture: %w", err)
@@ -0,0 +1,377 @@
+package clientraw
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/martinlindhe/unit"
+)
+
+// Data contains the statistics and history of the clientraw files. All times are in
+// the time zone of the station.
+type Data struct {
+	Stats     *reporting.Statistics
+	Station   string // Station is the name of the station
+	Latitude  float64
+	Longitude float64
+
+	Today   reporting.Sample   // Today contains the statistics of the current day so far
+	Minutes []reporting.Sample // Minutes contains the statistics of each minute of the last hour
+	Wind    []reporting.Sample // Wind contains the statistics of each 3 minutes of the last hour
+	Hours   []reporting.Sample // Hours contains the statistics of each hour of the last 24 hours
+
+	RainSince9am      unit.Length
+	RainSinceMidnight unit.Length
+}
+
+const (
+	preamble  = "12345"
+	endRecord = "!!EOR!!"
+)
+
+// Clientraw returns the clientraw.txt file, per docs/clientraw.md.
+func (d *Data) Clientraw() []byte {
+	s := d.Stats
+	b := buffer{b: make([]byte, 0, 1024)}
+
+	icon := weatherIcon(s)
+	last := func(samples []reporting.Sample, n int) []reporting.Sample {
+		if len(samples) < n {
+			return make([]reporting.Sample, n)
+		}
+		return samples[len(samples)-n:]
+	}
+	var lastHour, lastMinute, prevHour reporting.Sample
+	if n := len(d.Hours); n >= 2 {
+		lastHour, prevHour = d.Hours[n-1], d.Hours[n-2]
+	}
+	if n := len(d.Minutes); n > 0 {
+		lastMinute = d.Minutes[n-1]
+	}
+
+	b.String(preamble)                            // 000
+	b.Speed(s.WindSpeedAvg)                       // 001
+	b.Speed(s.TenMinGustHi)                       // 002
+	b.Float(s.WindBearing.Degrees(), 0)           // 003
+	b.Temp(s.OutdoorTemperature)                  // 004
+	b.Int(s.OutdoorHumidity)                      // 005
+	b.Pressure(s.BarometricPressure)              // 006
+	b.Rain(s.RainfallToday)                       // 007
+	b.Rain(s.MonthlyRainfall)                     // 008
+	b.Rain(s.YearlyRainfall)                      // 009
+	b.RainRate(s.RainRate)                        // 010
+	b.RainRate(d.Today.RainRateHi)                // 011
+	b.Temp(s.IndoorTemp)                          // 012
+	b.Int(s.IndoorHumidity)                       // 013
+	b.Unsupported(1)                              // 014
+	b.Int(icon)                                   // 015
+	b.Unsupported(3)                              // 016 - 018
+	b.Rain(s.YesterdayRainfall)                   // 019
+	b.Unsupported(9)                              // 020 - 028
+	b.Timestamp(s.Timestamp, "15")                // 029
+	b.Timestamp(s.Timestamp, "04")                // 030
+	b.Timestamp(s.Timestamp, "05")                // 031
+	b.String(stationName(d.Station, s.Timestamp)) // 032
+	b.Unsupported(1)                              // 033
+	b.Int(solarPercent(s))                        // 034
+	b.Int(s.Timestamp.Day())                      // 035
+	b.Int(int(s.Timestamp.Month()))               // 036
+	b.Unsupported(7)                              // 037 - 043
+	b.Temp(s.WindChill)                           // 044
+	b.Temp(s.Humidex)                             // 045
+	b.Temp(s.TodayTempHi)                         // 046
+	b.Temp(s.TodayTempLo)                         // 047
+	b.Int(icon)                                   // 048
+	b.String(iconDescriptions[icon])              // 049
+	b.Float(s.PressureTrend.Hectopascals(), 1)    // 050
+	for _, w := range last(d.Wind, 20) {          // 051 - 070
+		b.Speed(w.WindSpeed)
+	}
+	b.Speed(s.TodayWindGustHi)             // 071
+	b.Temp(s.DewPoint)                     // 072
+	b.Int(cloudBaseFeet(s))                // 073
+	b.Timestamp(s.Timestamp, "02/01/2006") // 074
+	b.Temp(d.Today.Humidex.Hi)             // 075
+	b.Temp(d.Today.Humidex.Lo)             // 076
+	b.Temp(d.Today.WindChill.Hi)           // 077
+	b.Temp(d.Today.WindChill.Lo)           // 078
+	b.Float(float64(s.UVIndex), 1)         // 079
+	hours := last(d.Hours, 10)
+	for _, h := range hours { // 080 - 089
+		b.Speed(h.WindSpeed)
+	}
+	for _, h := range hours { // 090 - 099
+		b.Temp(h.Temp)
+	}
+	for _, h := range hours { // 100 - 109
+		b.Rain(h.Rain)
+	}
+	b.Temp(d.Today.HeatIndex.Hi)                       // 110
+	b.Temp(d.Today.HeatIndex.Lo)                       // 111
+	b.Temp(s.HeatIndex)                                // 112
+	b.Speed(s.TodayWindHi)                             // 113
+	b.Unsupported(3)                                   // 114 - 116
+	b.Float(s.TenMinWindBearingAvg.Degrees(), 0)       // 117
+	b.Unsupported(9)                                   // 118 - 126
+	b.Float(s.SolarRadiation.WattsPerSquareMetre(), 0) // 127
+	b.Temp(d.Today.IndoorRange.Hi)                     // 128
+	b.Temp(d.Today.IndoorRange.Lo)                     // 129
+	b.Temp(s.ApparentTemp)                             // 130
+	b.Pressure(s.TodayPressureHi)                      // 131
+	b.Pressure(s.TodayPressureLo)                      // 132
+	b.Speed(lastHour.WindGust)                         // 133
+	b.Timestamp(lastHour.WindGustTime, "15:04")        // 134
+	b.Timestamp(s.TodayWindGustHiTime, "15:04")        // 135
+	b.Temp(d.Today.ApparentTemp.Hi)                    // 136
+	b.Temp(d.Today.ApparentTemp.Lo)                    // 137
+	b.Temp(d.Today.DewPoint.Hi)                        // 138
+	b.Temp(d.Today.DewPoint.Lo)                        // 139
+	b.Speed(lastMinute.WindGust)                       // 140
+	b.Int(s.Timestamp.Year())                          // 141
+	b.Unsupported(1)                                   // 142
+	b.Int(trend(s.TempTrend.Celsius(), 0, 0.1))        // 143
+	if prevHour.Observations > 0 {
+		b.Int(trend(float64(s.OutdoorHumidity), float64(prevHour.Humidity), 1))                                 // 144
+		b.Int(trend(s.Humidex.Celsius(), meteorology.Humidex(prevHour.Temp, prevHour.Humidity).Celsius(), 0.1)) // 145
+	} else {
+		b.Int(0) // 144
+		b.Int(0) // 145
+	}
+	b.Unsupported(12)                                                    // 146 - 157
+	b.Speed(s.WindSpeedAvg)                                              // 158
+	b.Temp(meteorology.WetBulb(s.OutdoorTemperature, s.OutdoorHumidity)) // 159
+	b.Float(d.Latitude, 4)                                               // 160
+	b.Float(-d.Longitude, 4)                                             // 161
+	b.Rain(d.RainSince9am)                                               // 162
+	b.Int(d.Today.HumidityHi)                                            // 163
+	b.Int(d.Today.HumidityLo)                                            // 164
+	b.Rain(d.RainSinceMidnight)                                          // 165
+	b.Timestamp(d.Today.WindChill.LoTime, "15:04")                       // 166
+	b.Unsupported(6)                                                     // 167 - 172
+	b.Float(s.WindRun.Kilometers(), 1)                                   // 173
+	b.String(endRecord)                                                  // 174
+
+	return b.Bytes()
+}
+
+// ClientrawHour returns the clientrawhour.txt file, containing the statistics of each minute
+// of the last hour, per docs/clientraw.md.
+func (d *Data) ClientrawHour() []byte {
+	b := buffer{b: make([]byte, 0, 4096)}
+
+	minutes := make([]reporting.Sample, 60)
+	if n := len(d.Minutes); n > len(minutes) {
+		copy(minutes, d.Minutes[n-len(minutes):])
+	} else {
+		copy(minutes[len(minutes)-n:], d.Minutes)
+	}
+
+	// the rain of today at the end of each minute
+	rain := make([]unit.Length, len(minutes))
+	total := d.Stats.RainfallToday
+	for i := len(minutes) - 1; i >= 0; i-- {
+		rain[i] = unit.Length(math.Max(0, float64(total)))
+		total -= minutes[i].Rain
+	}
+
+	b.String(preamble)
+	for _, m := range minutes {
+		b.Speed(m.WindSpeed)
+	}
+	for _, m := range minutes {
+		b.Speed(m.WindGust)
+	}
+	for _, m := range minutes {
+		b.Float(m.WindDir.Degrees(), 0)
+	}
+	for _, m := range minutes {
+		b.Temp(m.Temp)
+	}
+	for _, m := range minutes {
+		b.Int(m.Humidity)
+	}
+	for _, m := range minutes {
+		b.Pressure(m.Pressure)
+	}
+	for _, r := range rain {
+		b.Rain(r)
+	}
+	for _, m := range minutes {
+		b.Float(m.SolarRadiation.WattsPerSquareMetre(), 0)
+	}
+	b.String(endRecord)
+
+	return b.Bytes()
+}
+
+// Weather Display icons
+const (
+	iconSunny          = 0
+	iconClearNight     = 1
+	iconNightCloudy    = 4
+	iconHeavyRain      = 8
+	iconNightHeavyRain = 12
+	iconNightOvercast  = 13
+	iconNightRain      = 14
+	iconNightShowers   = 15
+	iconOvercast       = 18
+	iconPartlyCloudy   = 19
+	iconRain           = 20
+	iconShowers        = 22
+	iconWindy          = 33
+)
+
+var iconDescriptions = map[int]string{
+	iconSunny:          "Sunny",
+	iconClearNight:     "Clear_night",
+	iconNightCloudy:    "Cloudy",
+	iconHeavyRain:      "Heavy_rain",
+	iconNightHeavyRain: "Heavy_rain",
+	iconNightOvercast:  "Overcast",
+	iconNightRain:      "Rain",
+	iconNightShowers:   "Showers",
+	iconOvercast:       "Overcast",
+	iconPartlyCloudy:   "Partly_cloudy",
+	iconRain:           "Rain",
+	iconShowers:        "Showers",
+	iconWindy:          "Windy",
+}
+
+// heavyRainRate is the rain rate, per hour, of heavy rain.
+const heavyRainRate = 7.6 * unit.Millimeter
+
+// weatherIcon returns the icon of the current weather, from the rain rate, or else
+// the Zambretti forecast and the wind force.
+func weatherIcon(s *reporting.Statistics) int {
+	day := func(day, night int) int {
+		if s.IsDaylight {
+			return day
+		}
+		return night
+	}
+
+	switch {
+	case s.RainRate >= heavyRainRate:
+		return day(iconHeavyRain, iconNightHeavyRain)
+	case s.RainRate > 0:
+		return day(iconRain, iconNightRain)
+	case s.WindForce >= 6:
+		return iconWindy
+	}
+
+	switch z := s.ZambrettiForecast.ToInt(); {
+	case z >= 1 && z <= 3:
+		return day(iconSunny, iconClearNight)
+	case z >= 4 && z <= 9:
+		return day(iconPartlyCloudy, iconNightCloudy)
+	case z >= 10 && z <= 19:
+		return day(iconOvercast, iconNightOvercast)
+	case z >= 20:
+		return day(iconShowers, iconNightShowers)
+	default:
+		return day(iconSunny, iconClearNight)
+	}
+}
+
+// stationName returns the station name field, which may not contain spaces, with the time of t.
+func stationName(name string, t time.Time) string {
+	return strings.ReplaceAll(name, " ", "_") + "-" + t.Format("15:04:05")
+}
+
+// solarPercent returns the solar radiation as a percentage of the current theoretical maximum.
+func solarPercent(s *reporting.Statistics) int {
+	if s.CurrentSolarMax <= 0 {
+		return 0
+	}
+	return int(math.Min(100, math.Round(float64(s.SolarRadiation/s.CurrentSolarMax)*100)))
+}
+
+func cloudBaseFeet(s *reporting.Statistics) int {
+	if s.CloudBaseUnits == "ft" {
+		return s.CloudBase
+	}
+	return int(math.Round((unit.Length(s.CloudBase) * unit.Meter).Feet()))
+}
+
+// trend returns 1 when v is rising, -1 when v is falling, or else 0, by at least threshold from prev.
+func trend(v, prev, threshold float64) int {
+	switch {
+	case v-prev >= threshold:
+		return 1
+	case prev-v >= threshold:
+		return -1
+	default:
+		return 0
+	}
+}
+
+type buffer struct {
+	b []byte
+}
+
+func (b *buffer) Bytes() []byte {
+	if len(b.b) > 0 && b.b[len(b.b)-1] == ' ' {
+		return b.b[:len(b.b)-1]
+	}
+	return b.b
+}
+
+func (b *buffer) Float(f float64, prec int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		f = 0
+	}
+	b.b = strconv.AppendFloat(b.b, f, 'f', prec, 64)
+	b.b = append(b.b, ' ')
+}
+
+func (b *buffer) Int(i int) {
+	b.b = strconv.AppendInt(b.b, int64(i), 10)
+	b.b = append(b.b, ' ')
+}
+
+func (b *buffer) String(s string) {
+	b.b = append(b.b, s...)
+	b.b = append(b.b, ' ')
+}
+
+func (b *buffer) Timestamp(t time.Time, layout string) {
+	b.b = t.AppendFormat(b.b, layout)
+	b.b = append(b.b, ' ')
+}
+
+// Unsupported writes zero for each of n fields of unsupported sensors.
+func (b *buffer) Unsupported(n int) {
+	for i := 0; i < n; i++ {
+		b.Int(0)
+	}
+}
+
+// Temp writes t in Celsius, or zero when t is unavailable (0 K).
+func (b *buffer) Temp(t unit.Temperature) {
+	if t == 0 {
+		b.Float(0, 1)
+		return
+	}
+	b.Float(t.Celsius(), 1)
+}
+
+func (b *buffer) Speed(v unit.Speed) {
+	b.Float(v.Knots(), 1)
+}
+
+func (b *buffer) Pressure(v unit.Pressure) {
+	b.Float(v.Hectopascals(), 1)
+}
+
+func (b *buffer) Rain(v unit.Length) {
+	b.Float(v.Millimeters(), 1)
+}
+
+// RainRate writes the rain rate v, which is per hour, as millimeters per minute.
+func (b *buffer) RainRate(v unit.Length) {
+	b.Float(v.Millimeters()/60, 2)
+}
@@ -0,0 +1,97 @@
+package clientraw
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testData() *Data {
+	ts := time.Date(2021, 12, 1, 14, 5, 9, 0, time.UTC)
+	hours := make([]reporting.Sample, 24)
+	for i := range hours {
+		hours[i] = reporting.Sample{
+			Time:         ts.Add(time.Duration(i-24) * time.Hour),
+			Observations: 12,
+			Temp:         unit.FromCelsius(float64(i)),
+			Humidity:     70,
+			Rain:         unit.Length(i) * unit.Millimeter / 10,
+			WindSpeed:    unit.Speed(i) * unit.Knot,
+		}
+	}
+	hours[23].WindGust = 20 * unit.Knot
+	hours[23].WindGustTime = ts.Add(-20 * time.Minute)
+
+	return &Data{
+		Stats: &reporting.Statistics{
+			Timestamp:          ts,
+			OutdoorTemperature: unit.FromCelsius(22.4),
+			OutdoorHumidity:    65,
+			WindSpeedAvg:       10 * unit.Knot,
+			TenMinGustHi:       15 * unit.Knot,
+			WindBearing:        261 * unit.Degree,
+			BarometricPressure: 1012.3 * unit.Hectopascal,
+			RainRate:           1.2 * unit.Millimeter,
+			RainfallToday:      3 * unit.Millimeter,
+			TempTrend:          unit.FromCelsius(0.5),
+			IsDaylight:         true,
+			WindRun:            146.6 * unit.Kilometer,
+		},
+		Station:   "Launceston Airport",
+		Latitude:  -41.44,
+		Longitude: 147.23,
+		Hours:     hours,
+		Today: reporting.Sample{
+			Observations: 100,
+			HumidityHi:   90,
+			HumidityLo:   40,
+		},
+		RainSince9am: 2.5 * unit.Millimeter,
+	}
+}
+
+func TestData_Clientraw(t *testing.T) {
+	fields := strings.Fields(string(testData().Clientraw()))
+	require.Len(t, fields, 175)
+	field := func(n int) string { return fields[n] }
+
+	assert.Equal(t, "12345", field(0))
+	assert.Equal(t, "10.0", field(1), "wind speed in knots")
+	assert.Equal(t, "15.0", field(2))
+	assert.Equal(t, "261", field(3))
+	assert.Equal(t, "22.4", field(4))
+	assert.Equal(t, "1012.3", field(6))
+	assert.Equal(t, "0.02", field(10), "rain rate per minute")
+	assert.Equal(t, "20", field(15), "raining")
+	assert.Equal(t, "14 05 09", strings.Join(fields[29:32], " "))
+	assert.Equal(t, "Launceston_Airport-14:05:09", field(32))
+	assert.Equal(t, "Rain", field(49))
+	assert.Equal(t, "01/12/2021", field(74))
+	assert.Equal(t, "14.0 15.0 16.0 17.0 18.0 19.0 20.0 21.0 22.0 23.0", strings.Join(fields[90:100], " "), "last 10 hours")
+	assert.Equal(t, "20.0", field(133))
+	assert.Equal(t, "13:45", field(134))
+	assert.Equal(t, "1", field(143), "rising temperature")
+	assert.Equal(t, "-1", field(144), "falling humidity")
+	assert.Equal(t, "-41.4400", field(160))
+	assert.Equal(t, "-147.2300", field(161), "negative for east")
+	assert.Equal(t, "2.5", field(162))
+	assert.Equal(t, "90 40", strings.Join(fields[163:165], " "))
+	assert.Equal(t, "146.6", field(173))
+	assert.Equal(t, "!!EOR!!", field(174))
+}
+
+func TestData_ClientrawHour(t *testing.T) {
+	d := testData()
+	d.Minutes = make([]reporting.Sample, 60)
+	d.Minutes[59].Rain = 0.5 * unit.Millimeter
+	d.Minutes[58].Rain = 0.5 * unit.Millimeter
+
+	fields := strings.Fields(string(d.ClientrawHour()))
+	require.Len(t, fields, 1+8*60+1)
+	assert.Equal(t, "2.0 2.5 3.0", strings.Join(fields[418:421], " "), "daily rain at the end of each minute")
+}
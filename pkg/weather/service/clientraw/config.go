@@ -0,0 +1,21 @@
+package clientraw
+
+import (
+	"github.com/lmacrc/weather/pkg/weather/service"
+)
+
+type Config struct {
+	Enabled     bool
+	LocalDir    string              `toml:"local_dir" mapstructure:"local_dir"`
+	RemoteDir   string              `toml:"remote_dir" mapstructure:"remote_dir"`
+	StationName string              `toml:"station_name" mapstructure:"station_name"`
+	Stale       service.StalePolicy // Stale specifies how the files are published when sensor contact is lost
+}
+
+func NewConfig() Config {
+	return Config{
+		LocalDir:    ".",
+		StationName: "Weather",
+		Stale:       service.StalePolicySkip,
+	}
+}
@@ -0,0 +1,214 @@
+// Package clientraw is responsible for publishing the Weather Display clientraw files,
+// used by the Saratoga website templates.
+package clientraw
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/service/realtime"
+	"github.com/mitchellh/mapstructure"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Reporter provides the history of the clientraw files.
+type Reporter interface {
+	History(start, end time.Time, interval time.Duration) ([]reporting.Sample, error)
+}
+
+type Service struct {
+	log       *zap.Logger
+	reporter  Reporter
+	ftp       service.Ftp
+	schedule  cron.Schedule
+	cal       *calendar.Calendar
+	localDir  string
+	remoteDir string
+	station   string
+	lat, long float64
+	stale     service.StalePolicy
+	ch        chan *reporting.Statistics
+}
+
+func New(log *zap.Logger, v *viper.Viper, reporter Reporter, ftp service.Ftp, bus *event.Bus) (*Service, error) {
+	cfg := NewConfig()
+	if err := v.UnmarshalKey("clientraw", &cfg, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	// the files are published with each realtime statistics, and expire with the next
+	rt := realtime.NewConfig()
+	if err := v.UnmarshalKey("realtime", &rt, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	schedule, err := cron.ParseStandard(rt.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("parsing realtime cron: %w", err)
+	}
+
+	cal, err := calendar.New(v)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := struct {
+		Latitude, Longitude float64
+	}{}
+	if err := v.UnmarshalKey("location", &loc); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	s := &Service{
+		log:       log.With(zap.String("service", "clientraw")),
+		reporter:  reporter,
+		ftp:       ftp,
+		schedule:  schedule,
+		cal:       cal,
+		localDir:  cfg.LocalDir,
+		remoteDir: cfg.RemoteDir,
+		station:   cfg.StationName,
+		lat:       loc.Latitude,
+		long:      loc.Longitude,
+		stale:     cfg.Stale,
+		ch:        make(chan *reporting.Statistics, 1),
+	}
+
+	bus.MustSubscribe(realtime.NewStatistics, s.HandleStatistics)
+
+	return s, nil
+}
+
+// HandleStatistics queues the statistics for publishing the files. The history is queried
+// asynchronously, so the realtime service is not delayed.
+func (s *Service) HandleStatistics(stats *reporting.Statistics) {
+	select {
+	case s.ch <- stats:
+	default:
+		s.log.Warn("Still publishing the previous statistics, skipping.")
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	s.log.Info("Starting.")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Shutting down.")
+			return
+
+		case stats := <-s.ch:
+			s.Publish(stats)
+		}
+	}
+}
+
+// Publish writes the clientraw files for stats, and enqueues them for upload.
+func (s *Service) Publish(stats *reporting.Statistics) {
+	stats, ok := s.stale.Apply(stats)
+	if !ok {
+		s.log.Warn("Sensor contact lost, skipping clientraw files.")
+		return
+	}
+
+	d, err := s.Data(stats)
+	if err != nil {
+		s.log.Error("Unable to query clientraw history.", zap.Error(err))
+		return
+	}
+
+	expiresAt := s.schedule.Next(time.Now())
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{"clientraw.txt", d.Clientraw()},
+		{"clientrawhour.txt", d.ClientrawHour()},
+	} {
+		path := filepath.Join(s.localDir, f.name)
+		if err := os.WriteFile(path, f.data, 0644); err != nil {
+			s.log.Error("Unable to write clientraw file.", zap.String("path", path), zap.Error(err))
+			continue
+		}
+
+		if s.ftp == nil {
+			continue
+		}
+		err = s.ftp.Enqueue(service.FtpRequest{
+			LocalPath:      path,
+			RemoteDir:      s.remoteDir,
+			RemoteFilename: f.name,
+			ExpiresAt:      &expiresAt,
+		})
+		if err != nil {
+			s.log.Error("Failed to enqueue clientraw file for upload.", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
+
+// Data returns the statistics and history of the clientraw files for stats.
+func (s *Service) Data(stats *reporting.Statistics) (*Data, error) {
+	d := &Data{
+		Stats:     stats,
+		Station:   s.station,
+		Latitude:  s.lat,
+		Longitude: s.long,
+	}
+
+	// include the observation at the time of the statistics
+	ts := s.cal.In(stats.Timestamp)
+	end := ts.Add(time.Second)
+
+	var err error
+	if d.Minutes, err = s.reporter.History(end.Add(-time.Hour), end, time.Minute); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	if d.Wind, err = s.reporter.History(end.Add(-time.Hour), end, 3*time.Minute); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	if d.Hours, err = s.reporter.History(end.Add(-24*time.Hour), end, time.Hour); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	if d.Today, err = s.span(s.cal.BeginningOfDay(ts), end); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+
+	since9am, err := s.span(sinceHour(ts, 9), end)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	sinceMidnight, err := s.span(sinceHour(ts, 0), end)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	d.RainSince9am, d.RainSinceMidnight = since9am.Rain, sinceMidnight.Rain
+
+	return d, nil
+}
+
+// span returns the statistics of the observations from start up to, but excluding, end.
+func (s *Service) span(start, end time.Time) (reporting.Sample, error) {
+	res, err := s.reporter.History(start, end, end.Sub(start))
+	if err != nil || len(res) == 0 {
+		return reporting.Sample{Time: start}, err
+	}
+	return res[0], nil
+}
+
+// sinceHour returns the most recent time, at or before t, of the hour of the day.
+func sinceHour(t time.Time, hour int) time.Time {
+	res := time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, t.Location())
+	if res.After(t) {
+		res = res.AddDate(0, 0, -1)
+	}
+	return res
+}
@@ -1,6 +1,7 @@
 package influxdb
 
 import (
+	"github.com/lmacrc/weather/pkg/weather/service"
 	"github.com/spf13/viper"
 )
 
@@ -9,6 +10,7 @@ type Config struct {
 	Database        string
 	RetentionPolicy string `toml:"retention_policy" mapstructure:"retention_policy"`
 	Measurement     string
+	Stale           service.StalePolicy // Stale specifies how statistics are published when sensor contact is lost
 }
 
 func NewConfig() Config {
@@ -16,6 +18,7 @@ func NewConfig() Config {
 		Server:          "http://localhost:8086",
 		RetentionPolicy: "auto",
 		Measurement:     "observations",
+		Stale:           service.StalePolicyMark,
 	}
 }
 
@@ -24,4 +27,5 @@ func InitViper(v *viper.Viper) {
 	v.SetDefault("influxdb.server", cfg.Server)
 	v.SetDefault("influxdb.retention_policy", cfg.RetentionPolicy)
 	v.SetDefault("influxdb.measurement", cfg.Measurement)
+	v.SetDefault("influxdb.stale", cfg.Stale)
 }
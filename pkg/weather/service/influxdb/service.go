@@ -8,7 +8,9 @@ import (
 	"github.com/influxdata/influxdb1-client/v2"
 	"github.com/lmacrc/weather/pkg/event"
 	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service"
 	"github.com/lmacrc/weather/pkg/weather/service/realtime"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
@@ -20,11 +22,12 @@ type Service struct {
 	database    string
 	rp          string
 	measurement string
+	stale       service.StalePolicy
 }
 
 func New(log *zap.Logger, v *viper.Viper, bus *event.Bus) (*Service, error) {
 	var cfg Config
-	if err := v.UnmarshalKey("influxdb", &cfg); err != nil {
+	if err := v.UnmarshalKey("influxdb", &cfg, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
 		return nil, fmt.Errorf("config: %w", err)
 	}
 
@@ -49,6 +52,7 @@ func New(log *zap.Logger, v *viper.Viper, bus *event.Bus) (*Service, error) {
 		database:    cfg.Database,
 		rp:          cfg.RetentionPolicy,
 		measurement: cfg.Measurement,
+		stale:       cfg.Stale,
 	}
 
 	bus.MustSubscribe(realtime.NewStatistics, s.HandleStatistics)
@@ -63,6 +67,12 @@ func (s *Service) Run(ctx context.Context) {
 }
 
 func (s *Service) HandleStatistics(stats *reporting.Statistics) {
+	stats, ok := s.stale.Apply(stats)
+	if !ok {
+		s.log.Warn("Sensor contact lost, skipping statistics.")
+		return
+	}
+
 	bp, err := client.NewBatchPoints(client.BatchPointsConfig{
 		Precision:       "s",
 		Database:        s.database,
@@ -93,7 +103,12 @@ func (s *Service) HandleStatistics(stats *reporting.Statistics) {
 		"wind_speed_last_mps":     stats.WindSpeedLast.MetersPerSecond(),
 		"wind_bearing_deg":        stats.WindBearing.Degrees(),
 		"rain_rate_mm_per_hour":   stats.RainRate.Millimeters(),
+		"rainfall_last_hour_mm":   stats.RainfallLastHour.Millimeters(),
+		"rainfall_last_24h_mm":    stats.RainfallLast24Hours.Millimeters(),
+		"rainfall_today_mm":       stats.RainfallToday.Millimeters(),
 		"barometric_pressure_hpa": stats.BarometricPressure.Hectopascals(),
+		"pressure_change_hpa":     stats.PressureChange.Hectopascals(),
+		"pressure_tendency":       stats.PressureTendency.Code(),
 		"wind_force":              stats.WindForce,
 		"wind_run_m":              stats.WindRun.Meters(),
 		"indoor_temp_c":           stats.IndoorTemp.Celsius(),
@@ -104,9 +119,18 @@ func (s *Service) HandleStatistics(stats *reporting.Statistics) {
 		"humidex_c":               stats.Humidex.Celsius(),
 		"uv_index":                stats.UVIndex,
 		"solar_radiation_wsm":     stats.SolarRadiation.WattsPerSquareMetre(),
+		"evapotranspiration_mm":   stats.Evapotranspiration.Millimeters(),
+		"current_solar_max_wsm":   stats.CurrentSolarMax.WattsPerSquareMetre(),
+		"sunshine_hours_today":    stats.SunshineHoursToday.Hours(),
 		"apparent_temp_c":         stats.ApparentTemp.Celsius(),
+		"heating_degree_days":     stats.HeatingDegreeDaysToday,
+		"cooling_degree_days":     stats.CoolingDegreeDaysToday,
+		"growing_degree_days":     stats.GrowingDegreeDaysToday,
 		"wind_direction":          stats.WindDirection.String(),
 		"is_daylight":             boolToString(stats.IsDaylight),
+		"is_sunny":                boolToString(stats.IsSunny),
+		"sensor_contact_lost":     boolToString(stats.SensorContactLost),
+		"zambretti_forecast":      stats.ZambrettiForecast.String(),
 	}
 
 	p, err := client.NewPoint(s.measurement, tags, fields, stats.Timestamp)
@@ -0,0 +1,19 @@
+package noaa
+
+type Config struct {
+	Enabled       bool
+	Cron          string
+	LocalDir      string `toml:"local_dir" mapstructure:"local_dir"`
+	RemoteDir     string `toml:"remote_dir" mapstructure:"remote_dir"`
+	MonthFilename string `toml:"month_filename" mapstructure:"month_filename"`
+	YearFilename  string `toml:"year_filename" mapstructure:"year_filename"`
+}
+
+func NewConfig() Config {
+	return Config{
+		Cron:          "0 1 * * *",
+		LocalDir:      ".",
+		MonthFilename: "NOAAMO{{ strftime \"%m%y\" .Date }}.txt",
+		YearFilename:  "NOAAYR{{ strftime \"%Y\" .Date }}.txt",
+	}
+}
@@ -0,0 +1,170 @@
+// Package noaa is responsible for publishing the NOAA monthly and yearly climate summaries.
+package noaa
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/filepath/template"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/reporting/noaa"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Reporter provides the daily summaries for the reports.
+type Reporter interface {
+	DailySummaries(start, end time.Time) ([]*model.DailySummary, error)
+	Units() units.System
+}
+
+type Service struct {
+	log           *zap.Logger
+	reporter      Reporter
+	ftp           service.Ftp
+	schedule      cron.Schedule
+	station       noaa.Station
+	localDir      string
+	remoteDir     string
+	monthFilename *template.Template
+	yearFilename  *template.Template
+	cal           *calendar.Calendar
+}
+
+func New(log *zap.Logger, v *viper.Viper, reporter Reporter, ftp service.Ftp) (*Service, error) {
+	cfg := NewConfig()
+	if err := v.UnmarshalKey("noaa", &cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	schedule, err := cron.ParseStandard(cfg.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cron: %w", err)
+	}
+
+	cal, err := calendar.New(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var station noaa.Station
+	if err := v.UnmarshalKey("location", &station); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	monthFilename, err := template.New("month").Parse(cfg.MonthFilename)
+	if err != nil {
+		return nil, fmt.Errorf("parsing month_filename: %w", err)
+	}
+	yearFilename, err := template.New("year").Parse(cfg.YearFilename)
+	if err != nil {
+		return nil, fmt.Errorf("parsing year_filename: %w", err)
+	}
+
+	return &Service{
+		log:           log.With(zap.String("service", "noaa")),
+		reporter:      reporter,
+		ftp:           ftp,
+		schedule:      schedule,
+		station:       station,
+		localDir:      cfg.LocalDir,
+		remoteDir:     cfg.RemoteDir,
+		monthFilename: monthFilename,
+		yearFilename:  yearFilename,
+		cal:           cal,
+	}, nil
+}
+
+// Schedule returns the schedule for publishing the reports.
+func (s *Service) Schedule() cron.Schedule { return s.schedule }
+
+// Run writes the reports for the month and year of the previous day, and enqueues them for upload.
+func (s *Service) Run() {
+	s.run(time.Now())
+}
+
+// run writes the reports for the month and year of the last meteorological day completed at now.
+// When the day starts after midnight, the last day of a month is completed on the following day,
+// so the reports of the month are written again once its last day is complete.
+func (s *Service) run(now time.Time) {
+	date := s.cal.Date(now).AddDate(0, 0, -1)
+	s.log.Info("Generating NOAA reports.", zap.Time("date", date))
+
+	for _, report := range []struct {
+		filename *template.Template
+		write    func(io.Writer, time.Time) error
+	}{
+		{s.monthFilename, s.WriteMonth},
+		{s.yearFilename, s.WriteYear},
+	} {
+		path, err := s.writeFile(report.filename, date, report.write)
+		if err != nil {
+			s.log.Error("Failed to write NOAA report.", zap.Error(err))
+			continue
+		}
+		s.log.Info("NOAA report written to file.", zap.String("path", path))
+
+		if s.ftp != nil {
+			err = s.ftp.Enqueue(service.FtpRequest{
+				LocalPath:      path,
+				RemoteDir:      s.remoteDir,
+				RemoteFilename: filepath.Base(path),
+			})
+			if err != nil {
+				s.log.Error("Failed to enqueue NOAA report for upload.", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *Service) writeFile(filename *template.Template, date time.Time, write func(io.Writer, time.Time) error) (path string, err error) {
+	var buf bytes.Buffer
+	if err = filename.Execute(&buf, map[string]interface{}{"Date": date}); err != nil {
+		return "", fmt.Errorf("filename template: %w", err)
+	}
+
+	path = filepath.Join(s.localDir, buf.String())
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return path, write(f, date)
+}
+
+func (s *Service) options() noaa.Options {
+	return noaa.Options{Station: s.station, Units: s.reporter.Units()}
+}
+
+// WriteMonth writes the report for the month of t to w.
+func (s *Service) WriteMonth(w io.Writer, t time.Time) error {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	days, err := s.reporter.DailySummaries(start, start.AddDate(0, 1, 0))
+	if err != nil {
+		return fmt.Errorf("daily summaries: %w", err)
+	}
+	return noaa.WriteMonth(w, start, days, s.options())
+}
+
+// WriteYear writes the report for the year of t to w.
+func (s *Service) WriteYear(w io.Writer, t time.Time) error {
+	start := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	days, err := s.reporter.DailySummaries(start, start.AddDate(1, 0, 0))
+	if err != nil {
+		return fmt.Errorf("daily summaries: %w", err)
+	}
+	return noaa.WriteYear(w, start, days, s.options())
+}
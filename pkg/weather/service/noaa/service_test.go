@@ -0,0 +1,100 @@
+package noaa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeReporter struct {
+	start, end []time.Time
+}
+
+func (r *fakeReporter) DailySummaries(start, end time.Time) ([]*model.DailySummary, error) {
+	r.start = append(r.start, start)
+	r.end = append(r.end, end)
+	return []*model.DailySummary{{Date: start, TempHi: unit.FromCelsius(20), TempLo: unit.FromCelsius(10)}}, nil
+}
+
+func (r *fakeReporter) Units() units.System { return units.Metric }
+
+type fakeFtp []service.FtpRequest
+
+func (f *fakeFtp) Enqueue(req service.FtpRequest) error {
+	*f = append(*f, req)
+	return nil
+}
+
+func TestService_Run(t *testing.T) {
+	dir := t.TempDir()
+	vp := viper.New()
+	vp.Set("noaa.local_dir", dir)
+	vp.Set("noaa.remote_dir", "reports")
+	vp.Set("location.name", "Launceston")
+
+	var (
+		r   fakeReporter
+		ftp fakeFtp
+	)
+	s, err := New(zap.NewNop(), vp, &r, &ftp)
+	require.NoError(t, err)
+
+	s.Run()
+
+	date := time.Now().AddDate(0, 0, -1)
+	month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+	require.Len(t, r.start, 2)
+	assert.True(t, month.Equal(r.start[0]))
+	assert.True(t, month.AddDate(0, 1, 0).Equal(r.end[0]))
+	assert.Equal(t, date.Year(), r.start[1].Year())
+	assert.Equal(t, time.January, r.start[1].Month())
+
+	require.Len(t, ftp, 2)
+	assert.Equal(t, "NOAAMO"+date.Format("0106")+".txt", ftp[0].RemoteFilename)
+	assert.Equal(t, "NOAAYR"+date.Format("2006")+".txt", ftp[1].RemoteFilename)
+	assert.Equal(t, "reports", ftp[0].RemoteDir)
+
+	data, err := os.ReadFile(filepath.Join(dir, ftp[0].RemoteFilename))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "NAME: Launceston")
+}
+
+func TestService_Run_DayStartHour(t *testing.T) {
+	vp := viper.New()
+	vp.Set("noaa.local_dir", t.TempDir())
+	vp.Set("location.timezone", "Australia/Hobart")
+	vp.Set("calendar.day_start_hour", 9)
+
+	var (
+		r   fakeReporter
+		ftp fakeFtp
+	)
+	s, err := New(zap.NewNop(), vp, &r, &ftp)
+	require.NoError(t, err)
+
+	loc := s.cal.Location()
+	for _, tc := range []struct {
+		now  time.Time
+		want string
+	}{
+		// the 30th, the last day of November, runs until 9am on the 1st
+		{time.Date(2021, 12, 1, 1, 0, 0, 0, loc), "NOAAMO1121.txt"},
+		{time.Date(2021, 12, 2, 1, 0, 0, 0, loc), "NOAAMO1121.txt"},
+		{time.Date(2021, 12, 3, 1, 0, 0, 0, loc), "NOAAMO1221.txt"},
+	} {
+		ftp = ftp[:0]
+		s.run(tc.now)
+		require.Len(t, ftp, 2)
+		assert.Equal(t, tc.want, ftp[0].RemoteFilename, tc.now)
+	}
+}
@@ -0,0 +1,24 @@
+package pws
+
+import (
+	"time"
+)
+
+type Config struct {
+	Enabled   bool
+	URL       string        // URL is the upload endpoint, which defaults to that of the site, or its RapidFire endpoint
+	ID        string        // ID is the station ID
+	Password  string        // Password is the station key or password
+	RapidFire bool          `toml:"rapid_fire" mapstructure:"rapid_fire"` // RapidFire uploads each observation, rather than every interval
+	Interval  time.Duration // Interval is the minimum time between uploads, unless RapidFire
+	Retries   int           // Retries is the number of times a failed upload is retried, unless RapidFire
+	Timeout   time.Duration // Timeout is the maximum time for each upload attempt
+}
+
+func NewConfig() Config {
+	return Config{
+		Interval: 5 * time.Minute,
+		Retries:  3,
+		Timeout:  30 * time.Second,
+	}
+}
@@ -0,0 +1,263 @@
+// Package pws is responsible for uploading observations to weather networks accepting the
+// PWS upload protocol, such as Weather Underground and PWSweather, or a variant of it, such
+// as the Met Office Weather Observations Website (WOW).
+package pws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+	"github.com/mitchellh/mapstructure"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	pwsUploads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "weather",
+		Subsystem: "pws",
+		Name:      "uploads_total",
+		Help:      "The total number of observations uploaded to weather networks",
+	}, []string{"site", "result"})
+
+	pwsRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "weather",
+		Subsystem: "pws",
+		Name:      "upload_retries_total",
+		Help:      "The total number of failed uploads to weather networks which were retried",
+	}, []string{"site"})
+)
+
+const (
+	// software identifies the software to the weather networks.
+	software = "lmacrc-weather"
+
+	// defaultBackoff is the delay before the first retry of a failed upload, which doubles for each retry.
+	defaultBackoff = 10 * time.Second
+)
+
+// Site is a weather network accepting the PWS upload protocol.
+type Site struct {
+	Name         string // Name is the configuration key of the site
+	URL          string // URL is the default upload endpoint
+	RapidFireURL string // RapidFireURL is the default RapidFire endpoint, or empty when the site does not accept RapidFire uploads
+
+	values func(u Upload) url.Values
+}
+
+var (
+	Wunderground = Site{
+		Name:         "wunderground",
+		URL:          "https://weatherstation.wunderground.com/weatherstation/updateweatherstation.php",
+		RapidFireURL: "https://rtupdate.wunderground.com/weatherstation/updateweatherstation.php",
+		values:       Upload.Values,
+	}
+	PWSweather = Site{
+		Name:   "pwsweather",
+		URL:    "https://pwsupdate.pwsweather.com/api/v1/submitwx",
+		values: Upload.Values,
+	}
+	MetOfficeWOW = Site{
+		Name:   "wow",
+		URL:    "https://wow.metoffice.gov.uk/automaticreading",
+		values: Upload.WOWValues,
+	}
+)
+
+// Reporter provides the rain from the total rain counter, as for the reports, rather than the
+// counters of the console, which the console may reset at other times.
+type Reporter interface {
+	Rainfall(ts time.Time) (lastHour, today unit.Length)
+}
+
+type Service struct {
+	log            *zap.Logger
+	reporter       Reporter
+	site           string
+	client         *http.Client
+	url            string
+	values         func(u Upload) url.Values
+	id, password   string
+	rapidFire      bool
+	interval       time.Duration
+	retries        int
+	backoff        time.Duration
+	barometricType reporting.BarometricMeasurementType
+	ch             chan Upload
+
+	mu   sync.Mutex
+	prev time.Time // prev is the time of the previous observation
+	last time.Time // last is the time of the last observation queued for upload
+}
+
+// New returns a service uploading the observations to site. The reporter should subscribe to the
+// observations of bus first, so its rain includes each observation as it is uploaded.
+func New(log *zap.Logger, v *viper.Viper, site Site, reporter Reporter, bus *event.Bus) (*Service, error) {
+	cfg := NewConfig()
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToTimeDurationHookFunc(),
+	))
+	if err := v.UnmarshalKey(site.Name, &cfg, hook); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if cfg.ID == "" || cfg.Password == "" {
+		return nil, fmt.Errorf("config: %s id and password are required", site.Name)
+	}
+	if cfg.RapidFire && site.RapidFireURL == "" {
+		return nil, fmt.Errorf("config: %s does not support rapid_fire", site.Name)
+	}
+	if cfg.URL == "" {
+		cfg.URL = site.URL
+		if cfg.RapidFire {
+			cfg.URL = site.RapidFireURL
+		}
+	}
+	if cfg.RapidFire {
+		// a retry would delay the following observations, which are dropped whilst uploading,
+		// and the next observation follows shortly anyway
+		cfg.Retries = 0
+	}
+
+	rc, err := reporting.ReadConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		log:            log.With(zap.String("service", "pws"), zap.String("site", site.Name)),
+		reporter:       reporter,
+		site:           site.Name,
+		client:         &http.Client{Timeout: cfg.Timeout},
+		url:            cfg.URL,
+		values:         site.values,
+		id:             cfg.ID,
+		password:       cfg.Password,
+		rapidFire:      cfg.RapidFire,
+		interval:       cfg.Interval,
+		retries:        cfg.Retries,
+		backoff:        defaultBackoff,
+		barometricType: rc.BarometricMeasurement,
+		ch:             make(chan Upload, 1),
+	}
+
+	bus.MustSubscribe(store.NewObservation, s.HandleObservation)
+
+	return s, nil
+}
+
+// HandleObservation queues the observation for upload, with RapidFire, or when the interval
+// has elapsed since the last upload. The observation is uploaded asynchronously, so the
+// store is not delayed.
+func (s *Service) HandleObservation(o *model.Observation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	freq := o.Timestamp.Sub(s.prev)
+	if o.Timestamp.After(s.prev) {
+		s.prev = o.Timestamp
+	}
+	if !s.rapidFire && !service.IntervalElapsed(s.last, o.Timestamp, s.interval, 0) {
+		return
+	}
+
+	u := Upload{
+		ID:          s.id,
+		Password:    s.password,
+		Observation: o,
+		Barometer:   o.BarometricRel,
+	}
+	if s.barometricType == reporting.BarometricMeasurementTypeAbsolute {
+		u.Barometer = o.BarometricAbs
+	}
+	if s.rapidFire && freq > 0 && freq < time.Hour {
+		u.RapidFire = freq
+	}
+
+	select {
+	case s.ch <- u:
+		s.last = o.Timestamp
+	default:
+		s.log.Warn("Still uploading the previous observation, skipping.")
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	s.log.Info("Starting.")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Shutting down.")
+			return
+
+		case u := <-s.ch:
+			u.RainLastHour, u.RainToday = s.reporter.Rainfall(u.Observation.Timestamp)
+			if err := s.Upload(ctx, u); err != nil {
+				pwsUploads.WithLabelValues(s.site, "error").Inc()
+				s.log.Error("Failed to upload observation.", zap.Error(err))
+				continue
+			}
+			pwsUploads.WithLabelValues(s.site, "ok").Inc()
+		}
+	}
+}
+
+// Upload uploads u, retrying with an exponential backoff when it fails.
+func (s *Service) Upload(ctx context.Context, u Upload) error {
+	backoff := s.backoff
+	for attempt := 0; ; attempt++ {
+		err := s.send(ctx, u)
+		if err == nil || attempt >= s.retries {
+			return err
+		}
+
+		pwsRetries.WithLabelValues(s.site).Inc()
+		s.log.Warn("Failed to upload observation, retrying.", zap.Error(err), zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (s *Service) send(ctx context.Context, u Upload) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"?"+s.values(u).Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		// the password is in the query of the URL
+		return fmt.Errorf("request failed: %w", service.RedactURL(err))
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	msg := strings.TrimSpace(string(body))
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s: %s", res.Status, msg)
+	}
+	// Weather Underground responds OK to an invalid station ID or password
+	if strings.HasPrefix(msg, "INVALID") {
+		return fmt.Errorf("upload rejected: %s", msg)
+	}
+	return nil
+}
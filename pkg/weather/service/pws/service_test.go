@@ -0,0 +1,184 @@
+package pws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeReporter struct {
+	lastHour, today unit.Length
+}
+
+func (r fakeReporter) Rainfall(time.Time) (lastHour, today unit.Length) { return r.lastHour, r.today }
+
+func newService(t *testing.T, server string, rapidFire bool) *Service {
+	vp := viper.New()
+	vp.Set("wunderground.url", server)
+	vp.Set("wunderground.id", "KXYZ123")
+	vp.Set("wunderground.password", "secret")
+	vp.Set("wunderground.rapid_fire", rapidFire)
+	vp.Set("reporting.barometric_measurement", "absolute")
+
+	s, err := New(zap.NewNop(), vp, Wunderground, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	s.backoff = time.Millisecond
+	return s
+}
+
+func TestService_Upload(t *testing.T) {
+	var queries []url.Values
+	responses := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusInternalServerError, "unavailable"},
+		{http.StatusOK, "success"},
+		{http.StatusOK, "INVALIDPASSWORDID|Password or key and/or id are incorrect"},
+		{http.StatusOK, "INVALIDPASSWORDID|Password or key and/or id are incorrect"},
+		{http.StatusOK, "INVALIDPASSWORDID|Password or key and/or id are incorrect"},
+		{http.StatusOK, "INVALIDPASSWORDID|Password or key and/or id are incorrect"},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := responses[len(queries)]
+		queries = append(queries, r.URL.Query())
+		w.WriteHeader(res.status)
+		_, _ = w.Write([]byte(res.body))
+	}))
+	defer ts.Close()
+
+	s := newService(t, ts.URL, false)
+	o := &model.Observation{Timestamp: time.Now(), BarometricAbs: 1000 * unit.Hectopascal, BarometricRel: 1013.2 * unit.Hectopascal}
+	s.HandleObservation(o)
+	u := <-s.ch
+
+	require.NoError(t, s.Upload(context.Background(), u))
+	require.Len(t, queries, 2, "retried once")
+	assert.Equal(t, "KXYZ123", queries[1].Get("ID"))
+	assert.Equal(t, "secret", queries[1].Get("PASSWORD"))
+	assert.Equal(t, "29.53", queries[1].Get("baromin"), "absolute pressure")
+	assert.Empty(t, queries[1].Get("realtime"))
+
+	err := s.Upload(context.Background(), u)
+	assert.EqualError(t, err, "upload rejected: INVALIDPASSWORDID|Password or key and/or id are incorrect")
+	assert.Len(t, queries, 6, "retried 3 times")
+}
+
+func TestService_HandleObservation(t *testing.T) {
+	ts := time.Date(2021, 12, 1, 14, 30, 12, 0, time.UTC)
+
+	t.Run("interval", func(t *testing.T) {
+		s := newService(t, "http://localhost", false)
+		s.HandleObservation(&model.Observation{Timestamp: ts})
+		assert.Zero(t, (<-s.ch).RapidFire)
+		s.HandleObservation(&model.Observation{Timestamp: ts.Add(time.Minute)})
+		assert.Empty(t, s.ch, "within the interval")
+		s.HandleObservation(&model.Observation{Timestamp: ts.Add(5 * time.Minute)})
+		assert.Len(t, s.ch, 1)
+	})
+
+	t.Run("rapid fire", func(t *testing.T) {
+		s := newService(t, "http://localhost", true)
+		s.HandleObservation(&model.Observation{Timestamp: ts})
+		assert.Zero(t, (<-s.ch).RapidFire, "unknown frequency")
+		s.HandleObservation(&model.Observation{Timestamp: ts.Add(16 * time.Second)})
+		assert.Equal(t, 16*time.Second, (<-s.ch).RapidFire)
+	})
+}
+
+func TestService_Run(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		_, _ = w.Write([]byte("success"))
+	}))
+	defer server.Close()
+
+	s := newService(t, server.URL, false)
+	s.reporter = fakeReporter{lastHour: 2.54 * unit.Millimeter, today: 12.7 * unit.Millimeter}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	s.HandleObservation(&model.Observation{
+		Timestamp:  time.Now(),
+		HourlyRain: 25.4 * unit.Millimeter,
+		DailyRain:  25.4 * unit.Millimeter,
+	})
+	q := <-queries
+	assert.Equal(t, "0.10", q.Get("rainin"), "rain of the last hour from the total rain counter")
+	assert.Equal(t, "0.50", q.Get("dailyrainin"), "rain of the day from the total rain counter")
+}
+
+func TestNew_Config(t *testing.T) {
+	vp := viper.New()
+	vp.Set("pwsweather.id", "LAUNCESTON")
+	_, err := New(zap.NewNop(), vp, PWSweather, fakeReporter{}, event.New())
+	assert.EqualError(t, err, "config: pwsweather id and password are required")
+
+	vp.Set("pwsweather.password", "key")
+	s, err := New(zap.NewNop(), vp, PWSweather, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	assert.Equal(t, PWSweather.URL, s.url)
+
+	vp.Set("pwsweather.rapid_fire", true)
+	_, err = New(zap.NewNop(), vp, PWSweather, fakeReporter{}, event.New())
+	assert.EqualError(t, err, "config: pwsweather does not support rapid_fire")
+
+	vp.Set("wunderground.id", "KXYZ123")
+	vp.Set("wunderground.password", "secret")
+	s, err = New(zap.NewNop(), vp, Wunderground, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	assert.Equal(t, Wunderground.URL, s.url)
+	assert.Equal(t, 3, s.retries)
+
+	vp.Set("wunderground.rapid_fire", true)
+	s, err = New(zap.NewNop(), vp, Wunderground, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	assert.Equal(t, "https://rtupdate.wunderground.com/weatherstation/updateweatherstation.php", s.url)
+	assert.Zero(t, s.retries, "no retries with RapidFire")
+
+	vp.Set("wunderground.url", "http://localhost/rapidfire")
+	s, err = New(zap.NewNop(), vp, Wunderground, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost/rapidfire", s.url)
+}
+
+func TestService_Upload_WOW(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	vp := viper.New()
+	vp.Set("wow.url", ts.URL)
+	vp.Set("wow.id", "1234567")
+	vp.Set("wow.password", "123456")
+
+	s, err := New(zap.NewNop(), vp, MetOfficeWOW, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	s.HandleObservation(&model.Observation{Timestamp: time.Now(), TempOutdoor: unit.FromCelsius(14), HourlyRain: 25.4 * unit.Millimeter})
+
+	u := <-s.ch
+	u.RainLastHour = 2.54 * unit.Millimeter
+	require.NoError(t, s.Upload(context.Background(), u))
+	assert.Equal(t, "1234567", query.Get("siteid"))
+	assert.Equal(t, "123456", query.Get("siteAuthenticationKey"))
+	assert.Equal(t, "57.2", query.Get("tempf"))
+	assert.Equal(t, "0.10", query.Get("rainin"), "rain of the last hour from the total rain counter")
+	assert.NotContains(t, query, "ID")
+}
@@ -0,0 +1,85 @@
+package pws
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+// Upload is an observation in the US units of the PWS upload protocol, as accepted by
+// Weather Underground and PWSweather, and with other parameter names by WOW.
+type Upload struct {
+	ID, Password string
+	Observation  *model.Observation
+	Barometer    unit.Pressure // Barometer is the pressure per the barometric measurement of the reports
+	RainLastHour unit.Length   // RainLastHour is the rain of the last hour, from the total rain counter
+	RainToday    unit.Length   // RainToday is the rain since the start of the day, from the total rain counter
+	RapidFire    time.Duration // RapidFire is the interval between RapidFire uploads, or zero
+}
+
+// Values returns the query parameters of the upload.
+func (u Upload) Values() url.Values {
+	o := u.Observation
+
+	v := url.Values{}
+	v.Set("ID", u.ID)
+	v.Set("PASSWORD", u.Password)
+	v.Set("action", "updateraw")
+	u.setMeasurements(v)
+	v.Set("solarradiation", ftoa(o.SolarRadiation.WattsPerSquareMetre(), 1))
+	v.Set("UV", strconv.Itoa(o.UltravioletIndex))
+	v.Set("indoortempf", ftoa(o.TempIndoor.Fahrenheit(), 1))
+	if o.HumidityIndoor > 0 {
+		v.Set("indoorhumidity", strconv.Itoa(o.HumidityIndoor))
+	}
+
+	if u.RapidFire > 0 {
+		secs := int(u.RapidFire.Round(time.Second) / time.Second)
+		if secs < 1 {
+			secs = 1
+		}
+		v.Set("realtime", "1")
+		v.Set("rtfreq", strconv.Itoa(secs))
+	}
+	return v
+}
+
+// WOWValues returns the query parameters of the upload for the Met Office Weather Observations
+// Website, where the ID and Password are the site ID and authentication key. WOW also accepts
+// soiltempf and soilmoisture, which are omitted as the station has no soil sensors.
+func (u Upload) WOWValues() url.Values {
+	v := url.Values{}
+	v.Set("siteid", u.ID)
+	v.Set("siteAuthenticationKey", u.Password)
+	u.setMeasurements(v)
+	return v
+}
+
+// setMeasurements sets the parameters common to each site.
+func (u Upload) setMeasurements(v url.Values) {
+	o := u.Observation
+
+	v.Set("dateutc", o.Timestamp.UTC().Format("2006-01-02 15:04:05"))
+	v.Set("winddir", strconv.Itoa(int(o.WindDir.Degrees()+0.5)%360))
+	v.Set("windspeedmph", ftoa(o.WindSpeed.MilesPerHour(), 1))
+	v.Set("windgustmph", ftoa(o.WindGust.MilesPerHour(), 1))
+	v.Set("tempf", ftoa(o.TempOutdoor.Fahrenheit(), 1))
+	if o.HumidityOutdoor > 0 {
+		v.Set("humidity", strconv.Itoa(o.HumidityOutdoor))
+		v.Set("dewptf", ftoa(meteorology.DewPoint(o.TempOutdoor, o.HumidityOutdoor).Fahrenheit(), 1))
+	}
+	if u.Barometer > 0 {
+		v.Set("baromin", ftoa(u.Barometer.InchOfMercury(), 2))
+	}
+	v.Set("rainin", ftoa(u.RainLastHour.Inches(), 2))
+	v.Set("dailyrainin", ftoa(u.RainToday.Inches(), 2))
+	v.Set("softwaretype", software)
+}
+
+func ftoa(v float64, prec int) string {
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
@@ -0,0 +1,80 @@
+package pws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpload_Values(t *testing.T) {
+	o := &model.Observation{
+		Timestamp:        time.Date(2021, 12, 1, 14, 30, 12, 0, time.FixedZone("AEDT", 11*60*60)),
+		BarometricRel:    1013.2 * unit.Hectopascal,
+		HourlyRain:       25.4 * unit.Millimeter,
+		DailyRain:        25.4 * unit.Millimeter,
+		HumidityOutdoor:  82,
+		HumidityIndoor:   45,
+		WindDir:          359.7 * unit.Degree,
+		WindGust:         19.3 * unit.KilometersPerHour,
+		WindSpeed:        8 * unit.KilometersPerHour,
+		SolarRadiation:   450.25 * xunit.WattPerSquareMetre,
+		TempOutdoor:      unit.FromCelsius(14),
+		TempIndoor:       unit.FromCelsius(21),
+		UltravioletIndex: 3,
+	}
+
+	u := Upload{
+		ID:           "KXYZ123",
+		Password:     "secret",
+		Observation:  o,
+		Barometer:    o.BarometricRel,
+		RainLastHour: 2.54 * unit.Millimeter,
+		RainToday:    12.7 * unit.Millimeter,
+	}
+	assert.Equal(t, "ID=KXYZ123&PASSWORD=secret&UV=3&action=updateraw&baromin=29.92&dailyrainin=0.50"+
+		"&dateutc=2021-12-01+03%3A30%3A12&dewptf=51.7&humidity=82&indoorhumidity=45&indoortempf=69.8"+
+		"&rainin=0.10&softwaretype=lmacrc-weather&solarradiation=450.2&tempf=57.2&winddir=0"+
+		"&windgustmph=12.0&windspeedmph=5.0", u.Values().Encode())
+
+	u.RapidFire = 16 * time.Second
+	v := u.Values()
+	assert.Equal(t, "1", v.Get("realtime"))
+	assert.Equal(t, "16", v.Get("rtfreq"))
+
+	o.HumidityOutdoor, u.Barometer = 0, 0
+	v = u.Values()
+	assert.NotContains(t, v, "humidity")
+	assert.NotContains(t, v, "dewptf")
+	assert.NotContains(t, v, "baromin")
+}
+
+func TestUpload_WOWValues(t *testing.T) {
+	o := &model.Observation{
+		Timestamp:        time.Date(2021, 12, 1, 14, 30, 12, 0, time.UTC),
+		HourlyRain:       25.4 * unit.Millimeter,
+		DailyRain:        25.4 * unit.Millimeter,
+		HumidityOutdoor:  82,
+		WindDir:          270 * unit.Degree,
+		WindGust:         19.3 * unit.KilometersPerHour,
+		WindSpeed:        8 * unit.KilometersPerHour,
+		TempOutdoor:      unit.FromCelsius(14),
+		TempIndoor:       unit.FromCelsius(21),
+		UltravioletIndex: 3,
+	}
+
+	u := Upload{
+		ID:           "1234567",
+		Password:     "123456",
+		Observation:  o,
+		Barometer:    1013.2 * unit.Hectopascal,
+		RainLastHour: 2.54 * unit.Millimeter,
+		RainToday:    12.7 * unit.Millimeter,
+	}
+	assert.Equal(t, "baromin=29.92&dailyrainin=0.50&dateutc=2021-12-01+14%3A30%3A12&dewptf=51.7&humidity=82"+
+		"&rainin=0.10&siteAuthenticationKey=123456&siteid=1234567&softwaretype=lmacrc-weather&tempf=57.2"+
+		"&winddir=270&windgustmph=12.0&windspeedmph=5.0", u.WOWValues().Encode())
+}
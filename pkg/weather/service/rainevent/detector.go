@@ -0,0 +1,74 @@
+package rainevent
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+// detector segments rainfall into events, from the increments of the total rain counter
+// between consecutive observations. As for the reports, an increment is ignored when the
+// counter decreases or the increment exceeds the maximum, which is considered a spike.
+type detector struct {
+	dryGap       time.Duration
+	maxIncrement unit.Length
+
+	last    time.Time   // last is the time of the last observation
+	total   unit.Length // total is the total rain counter of the last observation
+	current *model.RainEvent
+}
+
+// Add adds o, returning the current event, or nil when it is not raining, and the event
+// which ended prior to o, if any. Observations prior to the last are ignored. An event
+// starts at the observation prior to the first increment, as the rain fell after it.
+func (d *detector) Add(o *model.Observation) (current, ended *model.RainEvent) {
+	if !d.last.IsZero() && !o.Timestamp.After(d.last) {
+		return d.current, nil
+	}
+
+	var inc unit.Length
+	if !d.last.IsZero() {
+		inc = o.TotalRain - d.total
+		if inc < 0 || inc > d.maxIncrement {
+			inc = 0
+		}
+	}
+	prev := d.last
+	d.last, d.total = o.Timestamp, o.TotalRain
+
+	ended = d.Check(o.Timestamp)
+
+	if inc > 0 {
+		if d.current == nil {
+			d.current = &model.RainEvent{Start: prev, InProgress: true}
+		}
+		d.current.End = o.Timestamp
+		d.current.Duration = d.current.End.Sub(d.current.Start)
+		d.current.Rain += inc
+	}
+	if d.current != nil && o.RainRatePerHour > d.current.RainRateHi {
+		d.current.RainRateHi, d.current.RainRateHiTime = o.RainRatePerHour, o.Timestamp
+	}
+
+	return d.current, ended
+}
+
+// clone returns a copy of d, which may be advanced without changing the current event of d.
+func (d detector) clone() detector {
+	if d.current != nil {
+		d.current = copyOf(d.current)
+	}
+	return d
+}
+
+// Check ends the current event, returning it, when no rain has fallen for the dry gap as of ts.
+func (d *detector) Check(ts time.Time) *model.RainEvent {
+	if d.current == nil || ts.Sub(d.current.End) < d.dryGap {
+		return nil
+	}
+	ended := d.current
+	ended.InProgress = false
+	d.current = nil
+	return ended
+}
@@ -0,0 +1,180 @@
+// Package rainevent is responsible for segmenting rainfall into rain events, or storms,
+// which are separated by a period without rain.
+package rainevent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	// Started is a topic for publishing when a rain event starts.
+	// The *model.RainEvent is published.
+	Started = event.T("rainevent:started")
+
+	// Ended is a topic for publishing when a rain event ends, once the dry gap has elapsed.
+	// The *model.RainEvent is published.
+	Ended = event.T("rainevent:ended")
+)
+
+var (
+	rainEvents = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "weather",
+		Subsystem: "rain",
+		Name:      "events_total",
+		Help:      "The total number of rain events started",
+	})
+)
+
+// checkInterval is how often the service checks for the end of the current event.
+const checkInterval = time.Minute
+
+type Service struct {
+	log   *zap.Logger
+	store *store.Store
+	bus   *event.Bus
+
+	mu sync.Mutex
+	d  detector
+}
+
+func New(log *zap.Logger, v *viper.Viper, s *store.Store, bus *event.Bus) (*Service, error) {
+	cfg, err := reporting.ReadConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &Service{
+		log:   log.With(zap.String("service", "rainevent")),
+		store: s,
+		bus:   bus,
+		d: detector{
+			dryGap:       cfg.Rain.EventDryGap,
+			maxIncrement: unit.Length(cfg.Rain.MaxIncrement) * unit.Millimeter,
+		},
+	}
+
+	// resume from the last observation and any event in progress
+	if o := s.LastObservation(time.Now()); o != nil {
+		svc.d.last, svc.d.total = o.Timestamp, o.TotalRain
+	}
+	ev, err := s.LastRainEvent()
+	if err != nil {
+		return nil, err
+	}
+	if ev != nil && ev.InProgress {
+		svc.d.current = ev
+	}
+
+	return svc, nil
+}
+
+// WriteObservation writes o to the store, replacing the event rain reported by the
+// console with the rain of the current event, which is zero when it is not raining.
+// The events are only advanced once o is written.
+func (s *Service) WriteObservation(o model.Observation) (*model.Observation, error) {
+	s.mu.Lock()
+	d := s.d.clone()
+	current, ended := d.Add(&o)
+	o.EventRain = 0
+	if current != nil {
+		o.EventRain = current.Rain
+	}
+
+	res, err := s.store.WriteObservation(o)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.d = d
+
+	var started *model.RainEvent
+	if ended != nil {
+		s.write(ended)
+		ended = copyOf(ended)
+	}
+	if current != nil {
+		isNew := current.ID == 0
+		s.write(current)
+		if isNew {
+			started = copyOf(current)
+		}
+	}
+	s.mu.Unlock()
+
+	if ended != nil {
+		s.publishEnded(ended)
+	}
+	if started != nil {
+		rainEvents.Inc()
+		s.log.Info("Rain event started.", zap.Time("start", started.Start))
+		s.bus.Publish(Started, started)
+	}
+
+	return res, nil
+}
+
+// Check ends the current event when no rain has fallen for the dry gap as of ts,
+// publishing an event.
+func (s *Service) Check(ts time.Time) {
+	s.mu.Lock()
+	ended := s.d.Check(ts)
+	if ended != nil {
+		s.write(ended)
+		ended = copyOf(ended)
+	}
+	s.mu.Unlock()
+
+	if ended != nil {
+		s.publishEnded(ended)
+	}
+}
+
+// write writes ev to the store, which assigns the ID of a new event.
+func (s *Service) write(ev *model.RainEvent) {
+	if err := s.store.WriteRainEvent(ev); err != nil {
+		s.log.Error("Failed to write rain event.", zap.Error(err))
+	}
+}
+
+func (s *Service) publishEnded(ev *model.RainEvent) {
+	s.log.Info("Rain event ended.",
+		zap.Time("start", ev.Start),
+		zap.Duration("duration", ev.Duration),
+		zap.Float64("rain_mm", ev.Rain.Millimeters()))
+	s.bus.Publish(Ended, ev)
+}
+
+func copyOf(ev *model.RainEvent) *model.RainEvent {
+	c := *ev
+	return &c
+}
+
+func (s *Service) Run(ctx context.Context) {
+	s.log.Info("Starting.")
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Shutting down.")
+			return
+
+		case <-ticker.C:
+			s.Check(time.Now())
+		}
+	}
+}
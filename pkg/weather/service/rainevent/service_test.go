@@ -0,0 +1,129 @@
+package rainevent
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestService_WriteObservation(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "weather.db")), &gorm.Config{})
+	require.NoError(t, err)
+
+	bus := event.New()
+	st, err := store.New(db, bus)
+	require.NoError(t, err)
+
+	vp := viper.New()
+	vp.Set("reporting.rain.event_dry_gap", "1h")
+
+	s, err := New(zap.NewNop(), vp, st, bus)
+	require.NoError(t, err)
+
+	var started, ended []*model.RainEvent
+	bus.MustSubscribe(Started, func(ev *model.RainEvent) { started = append(started, ev) })
+	bus.MustSubscribe(Ended, func(ev *model.RainEvent) { ended = append(ended, ev) })
+
+	ts := time.Date(2021, 7, 1, 22, 0, 0, 0, time.UTC)
+	total := 100.0
+	write := func(d time.Duration, rain, rate float64) *model.Observation {
+		total += rain
+		o, err := s.WriteObservation(model.Observation{
+			Timestamp:       ts.Add(d),
+			TotalRain:       unit.Length(total) * unit.Millimeter,
+			EventRain:       42 * unit.Millimeter,
+			RainRatePerHour: unit.Length(rate) * unit.Millimeter,
+		})
+		require.NoError(t, err)
+		return o
+	}
+
+	o := write(0, 0, 0)
+	assert.Zero(t, o.EventRain, "replaces the console event rain")
+
+	write(5*time.Minute, 0.2, 2)
+	require.Len(t, started, 1)
+	assert.Equal(t, ts, started[0].Start, "from the observation prior to the first increment")
+
+	write(10*time.Minute, 1.0, 12)
+	write(15*time.Minute, 500, 0) // spike
+	write(50*time.Minute, 0.4, 4)
+	o = write(60*time.Minute, 0, 0)
+	assert.InDelta(t, 1.6, o.EventRain.Millimeters(), 1e-6)
+
+	s.Check(ts.Add(109 * time.Minute))
+	assert.Empty(t, ended, "within the dry gap")
+	s.Check(ts.Add(110 * time.Minute))
+	require.Len(t, ended, 1)
+	s.Check(ts.Add(120 * time.Minute))
+	assert.Len(t, ended, 1, "publish once")
+
+	o = write(2*time.Hour, 0, 0)
+	assert.Zero(t, o.EventRain)
+
+	// a new event is started after the dry gap
+	write(4*time.Hour, 0.2, 1)
+	assert.Len(t, started, 2)
+
+	events, err := st.RainEvents(ts, ts.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	ev := events[0]
+	assert.False(t, ev.InProgress)
+	assert.Equal(t, ts.Add(50*time.Minute), ev.End)
+	assert.Equal(t, ts, ev.Start)
+	assert.Equal(t, 50*time.Minute, ev.Duration)
+	assert.InDelta(t, 1.6, ev.Rain.Millimeters(), 1e-6)
+	assert.InDelta(t, 12, ev.RainRateHi.Millimeters(), 1e-6)
+	assert.Equal(t, ts.Add(10*time.Minute), ev.RainRateHiTime)
+	assert.True(t, events[1].InProgress)
+	assert.Equal(t, ts.Add(2*time.Hour), events[1].Start)
+
+	// the event in progress is resumed
+	s, err = New(zap.NewNop(), vp, st, bus)
+	require.NoError(t, err)
+	o = write(4*time.Hour+5*time.Minute, 0.3, 0)
+	assert.InDelta(t, 0.5, o.EventRain.Millimeters(), 1e-6)
+	assert.Len(t, started, 2)
+}
+
+func TestService_WriteObservation_Error(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "weather.db")), &gorm.Config{})
+	require.NoError(t, err)
+
+	bus := event.New()
+	st, err := store.New(db, bus)
+	require.NoError(t, err)
+
+	s, err := New(zap.NewNop(), viper.New(), st, bus)
+	require.NoError(t, err)
+
+	var started []*model.RainEvent
+	bus.MustSubscribe(Started, func(ev *model.RainEvent) { started = append(started, ev) })
+
+	ts := time.Date(2021, 7, 1, 22, 0, 0, 0, time.UTC)
+	_, err = s.WriteObservation(model.Observation{Timestamp: ts, TotalRain: 100 * unit.Millimeter})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	_, err = s.WriteObservation(model.Observation{Timestamp: ts.Add(5 * time.Minute), TotalRain: 101 * unit.Millimeter})
+	require.Error(t, err)
+	assert.Empty(t, started, "no event for an observation which was not written")
+	assert.Nil(t, s.d.current)
+	assert.Equal(t, ts, s.d.last)
+}
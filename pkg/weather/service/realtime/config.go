@@ -1,12 +1,19 @@
 package realtime
 
+import (
+	"github.com/lmacrc/weather/pkg/weather/service"
+)
+
 type Config struct {
 	Cron      string
-	RemoteDir string `toml:"remote_dir" mapstructure:"remote_dir"`
+	RemoteDir string              `toml:"remote_dir" mapstructure:"remote_dir"`
+	Stale     service.StalePolicy // Stale specifies how statistics are published when sensor contact is lost
+	Gauges    bool                // Gauges also publishes realtimegauges.txt, for the SteelSeries gauges
 }
 
 func NewConfig() Config {
 	return Config{
-		Cron: "*/5 * * * *",
+		Cron:  "*/5 * * * *",
+		Stale: service.StalePolicyMark,
 	}
 }
@@ -0,0 +1,243 @@
+package realtime
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/martinlindhe/unit"
+)
+
+// https://cumuluswiki.org/a/Realtimegauges.txt
+
+// gaugesVersion is the version of the realtimegauges.txt format expected by the SteelSeries gauges.
+const gaugesVersion = "14"
+
+// Gauges contains the data of the realtimegauges.txt file, used by the SteelSeries gauges.
+// The values are in the units of Stats.
+type Gauges struct {
+	Stats *reporting.Statistics
+	Today reporting.Sample // Today contains the statistics of the current day so far
+
+	HourlyRainHi     unit.Length // HourlyRainHi is the highest rain of an hour of the current day
+	HourlyRainHiTime time.Time
+
+	// BearingFrom and BearingTo are the range of the wind bearing of the last 10 minutes, clockwise.
+	BearingFrom unit.Angle
+	BearingTo   unit.Angle
+
+	WindRose meteorology.WindRose // WindRose is the wind rose of the current day
+	AllTime  *reporting.Records   // AllTime contains the all time records
+}
+
+func (g Gauges) MarshalJSON() ([]byte, error) {
+	s := g.Stats
+	u := Statistics(*s).units()
+	b := gaugesBuffer{b: make([]byte, 0, 2048), u: u}
+	rec := g.AllTime
+	if rec == nil {
+		rec = &reporting.Records{}
+	}
+
+	b.Append('{')
+	b.String("date", s.Timestamp.Format("15:04"))
+	b.String("dateFormat", "dd/mm/yyyy")
+	b.Bool("SensorContactLost", s.SensorContactLost)
+	b.String("forecast", s.ZambrettiForecast.String())
+	b.String("tempunit", s.TempUnits)
+	b.String("windunit", s.WindUnits)
+	b.String("pressunit", s.PressureUnits)
+	b.String("rainunit", s.RainUnits)
+	b.Temp("temp", s.OutdoorTemperature)
+	b.Temp("tempTL", s.TodayTempLo)
+	b.Temp("tempTH", s.TodayTempHi)
+	b.Temp("intemp", s.IndoorTemp)
+	b.Temp("dew", s.DewPoint)
+	b.Temp("dewpointTL", g.Today.DewPoint.Lo)
+	b.Temp("dewpointTH", g.Today.DewPoint.Hi)
+	b.Temp("apptemp", s.ApparentTemp)
+	b.Temp("apptempTL", g.Today.ApparentTemp.Lo)
+	b.Temp("apptempTH", g.Today.ApparentTemp.Hi)
+	b.Temp("wchill", s.WindChill)
+	b.Temp("wchillTL", g.Today.WindChill.Lo)
+	b.Temp("heatindex", s.HeatIndex)
+	b.Temp("heatindexTH", g.Today.HeatIndex.Hi)
+	b.Temp("humidex", s.Humidex)
+	b.Speed("wlatest", s.WindSpeedLast)
+	b.Speed("wspeed", s.WindSpeedAvg)
+	b.Speed("wgust", s.TenMinGustHi)
+	b.Speed("wgustTM", s.TodayWindGustHi)
+	b.Float("bearing", s.WindBearing.Degrees(), 0)
+	b.Float("avgbearing", s.TenMinWindBearingAvg.Degrees(), 0)
+	b.Pressure("press", s.BarometricPressure)
+	b.Pressure("pressTL", s.TodayPressureLo)
+	b.Pressure("pressTH", s.TodayPressureHi)
+	b.Pressure("pressL", rec.PressureLo)
+	b.Pressure("pressH", rec.PressureHi)
+	b.Rain("rfall", s.RainfallToday)
+	b.Rain("rrate", s.RainRate)
+	b.Rain("rrateTM", g.Today.RainRateHi)
+	b.Int("hum", s.OutdoorHumidity)
+	b.Int("humTL", g.Today.HumidityLo)
+	b.Int("humTH", g.Today.HumidityHi)
+	b.Int("inhum", s.IndoorHumidity)
+	b.TempChange("temptrend", s.TempTrend)
+	b.Time("TtempTL", s.TodayTempLoTime)
+	b.Time("TtempTH", s.TodayTempHiTime)
+	b.Time("TdewpointTL", g.Today.DewPoint.LoTime)
+	b.Time("TdewpointTH", g.Today.DewPoint.HiTime)
+	b.Time("TapptempTL", g.Today.ApparentTemp.LoTime)
+	b.Time("TapptempTH", g.Today.ApparentTemp.HiTime)
+	b.Time("TwchillTL", g.Today.WindChill.LoTime)
+	b.Time("TheatindexTH", g.Today.HeatIndex.HiTime)
+	b.Time("TrrateTM", g.Today.RainRateHiTime)
+	b.Time("ThourlyrainTH", g.HourlyRainHiTime)
+	if g.Today.RainTime.IsZero() {
+		b.String("LastRainTipISO", "----")
+	} else {
+		b.String("LastRainTipISO", g.Today.RainTime.Format("2006-01-02 15:04"))
+	}
+	b.Rain("hourlyrainTH", g.HourlyRainHi)
+	b.Time("ThumTL", g.Today.HumidityLoTime)
+	b.Time("ThumTH", g.Today.HumidityHiTime)
+	b.Time("TpressTL", s.TodayPressureLoTime)
+	b.Time("TpressTH", s.TodayPressureHiTime)
+	b.Pressure("presstrendval", s.PressureTrend)
+	b.Int("Tbeaufort", meteorology.SpeedToWindForce(s.TodayWindGustHi).ToInt())
+	b.Time("TwgustTM", s.TodayWindGustHiTime)
+	b.Speed("windTM", s.TodayWindHi)
+	b.Float("bearingTM", g.Today.WindGustDir.Degrees(), 0)
+	b.String("timeUTC", s.Timestamp.UTC().Format("2006,1,2,15,4,5"))
+	b.Float("BearingRangeFrom10", g.BearingFrom.Degrees(), 0)
+	b.Float("BearingRangeTo10", g.BearingTo.Degrees(), 0)
+	b.Float("UV", float64(s.UVIndex), 1)
+	b.Float("UVTH", float64(g.Today.UVIndexHi), 1)
+	b.Float("SolarRad", s.SolarRadiation.WattsPerSquareMetre(), 0)
+	b.Float("SolarTM", g.Today.SolarRadiationHi.WattsPerSquareMetre(), 0)
+	b.Float("CurrentSolarMax", s.CurrentSolarMax.WattsPerSquareMetre(), 0)
+	b.String("domwinddir", string(meteorology.CardinalDirection(g.Today.WindDir.Degrees())))
+	b.WindRose("WindRoseData", g.WindRose)
+	b.Float("windrun", u.Speed.Distance().To(s.WindRun), 1)
+	b.Int("cloudbasevalue", s.CloudBase)
+	b.String("cloudbaseunit", s.CloudBaseUnits)
+	b.String("version", s.CumulusVersion)
+	b.Int("build", s.CumulusBuildNumber)
+	b.String("ver", gaugesVersion)
+	b.End()
+
+	return b.Bytes(), nil
+}
+
+// gaugesBuffer writes the fields of a JSON object, each followed by a comma. All values
+// except WindRoseData are strings, as expected by the SteelSeries gauges.
+type gaugesBuffer struct {
+	b []byte
+	u units.System
+}
+
+func (b *gaugesBuffer) Bytes() []byte { return b.b }
+
+func (b *gaugesBuffer) Append(d ...byte) {
+	b.b = append(b.b, d...)
+}
+
+// End replaces the comma following the last field with the end of the object.
+func (b *gaugesBuffer) End() {
+	b.b[len(b.b)-1] = '}'
+}
+
+func (b *gaugesBuffer) key(k string) {
+	b.b = strconv.AppendQuote(b.b, k)
+	b.b = append(b.b, ':')
+}
+
+func (b *gaugesBuffer) String(k, v string) {
+	b.key(k)
+	b.b = strconv.AppendQuote(b.b, v)
+	b.b = append(b.b, ',')
+}
+
+func (b *gaugesBuffer) Float(k string, f float64, prec int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		f = 0
+	}
+	b.String(k, strconv.FormatFloat(f, 'f', prec, 64))
+}
+
+func (b *gaugesBuffer) Int(k string, i int) {
+	b.String(k, strconv.Itoa(i))
+}
+
+func (b *gaugesBuffer) Bool(k string, v bool) {
+	if v {
+		b.Int(k, 1)
+	} else {
+		b.Int(k, 0)
+	}
+}
+
+// Time writes t as hh:mm, or "--:--" when t is zero.
+func (b *gaugesBuffer) Time(k string, t time.Time) {
+	if t.IsZero() {
+		b.String(k, "--:--")
+		return
+	}
+	b.String(k, t.Format("15:04"))
+}
+
+// Temp writes t, or zero when t is unavailable (0 K).
+func (b *gaugesBuffer) Temp(k string, t unit.Temperature) {
+	if t == 0 {
+		b.Float(k, 0, 1)
+		return
+	}
+	b.Float(k, b.u.Temperature.To(t), 1)
+}
+
+// TempChange writes the temperature change t, which is expressed as an offset from 0°C.
+func (b *gaugesBuffer) TempChange(k string, t unit.Temperature) {
+	b.Float(k, b.u.Temperature.Delta(unit.Temperature(t.Celsius())), 1)
+}
+
+func (b *gaugesBuffer) Speed(k string, v unit.Speed) {
+	b.Float(k, b.u.Speed.To(v), 1)
+}
+
+func (b *gaugesBuffer) Pressure(k string, v unit.Pressure) {
+	b.Float(k, b.u.Pressure.To(v), b.pressurePrec())
+}
+
+func (b *gaugesBuffer) pressurePrec() int {
+	if b.u.Pressure == units.InchOfMercury {
+		return 2
+	}
+	return 1
+}
+
+func (b *gaugesBuffer) Rain(k string, v unit.Length) {
+	prec := 1
+	if b.u.Rain == units.Inches {
+		prec = 2
+	}
+	b.Float(k, b.u.Rain.To(v), prec)
+}
+
+// WindRose writes the percentage of the samples of each of the 16 compass points, from north.
+func (b *gaugesBuffer) WindRose(k string, wr meteorology.WindRose) {
+	b.key(k)
+	b.b = append(b.b, '[')
+	for i, classes := range wr.Frequency {
+		if i > 0 {
+			b.b = append(b.b, ',')
+		}
+		var f float64
+		for _, v := range classes {
+			f += v
+		}
+		b.b = strconv.AppendFloat(b.b, f*100, 'f', 1, 64)
+	}
+	b.b = append(b.b, ']', ',')
+}
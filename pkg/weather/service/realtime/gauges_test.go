@@ -0,0 +1,93 @@
+package realtime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGauges_MarshalJSON(t *testing.T) {
+	stats := reporting.Statistics(testStatistics())
+	wr := meteorology.CalculateWindRose([]meteorology.WindSample{
+		{Speed: 10 * unit.KilometersPerHour, Direction: 0},
+		{Speed: 10 * unit.KilometersPerHour, Direction: 270 * unit.Degree},
+		{Speed: 10 * unit.KilometersPerHour, Direction: 275 * unit.Degree},
+		{Speed: 0, Direction: 90 * unit.Degree},
+	}, nil)
+	g := Gauges{
+		Stats: &stats,
+		Today: reporting.Sample{
+			DewPoint:       reporting.TempRange{Lo: unit.FromCelsius(2.1), LoTime: time.Date(2008, 10, 18, 6, 30, 0, 0, time.UTC)},
+			HumidityHi:     95,
+			UVIndexHi:      4,
+			RainRateHi:     3.2 * unit.Millimeter,
+			RainRateHiTime: time.Date(2008, 10, 18, 11, 2, 0, 0, time.UTC),
+		},
+		BearingFrom: 200 * unit.Degree,
+		BearingTo:   280 * unit.Degree,
+		WindRose:    wr,
+		AllTime:     &reporting.Records{PressureLo: 975.2 * unit.Hectopascal, PressureHi: 1040.1 * unit.Hectopascal},
+	}
+
+	data, err := json.Marshal(g)
+	require.NoError(t, err)
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &got), string(data))
+	assert.Equal(t, "14", got["ver"])
+	assert.Equal(t, "16:03", got["date"])
+	assert.Equal(t, "8.4", got["temp"])
+	assert.Equal(t, "7.8", got["tempTL"])
+	assert.Equal(t, "14:41", got["TtempTL"])
+	assert.Equal(t, "2.1", got["dewpointTL"])
+	assert.Equal(t, "06:30", got["TdewpointTL"])
+	assert.Equal(t, "--:--", got["TapptempTH"], "not available")
+	assert.Equal(t, "-0.7", got["temptrend"])
+	assert.Equal(t, "0.1", got["presstrendval"])
+	assert.Equal(t, "975.2", got["pressL"])
+	assert.Equal(t, "3.2", got["rrateTM"])
+	assert.Equal(t, "11:02", got["TrrateTM"])
+	assert.Equal(t, "95", got["humTH"])
+	assert.Equal(t, "200", got["BearingRangeFrom10"])
+	assert.Equal(t, "2008,10,18,16,3,45", got["timeUTC"])
+	assert.Equal(t, "Fine, possible showers", got["forecast"])
+	assert.Equal(t, "0", got["SensorContactLost"])
+
+	rose, ok := got["WindRoseData"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, rose, 16)
+	assert.Equal(t, 25.0, rose[0])
+	assert.Equal(t, 50.0, rose[12], "west")
+
+	// the values are in the units of the statistics
+	stats.TempUnits = "F"
+	stats.PressureUnits = "in"
+	data, err = json.Marshal(g)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "47.1", got["temp"])
+	assert.Equal(t, "-1.3", got["temptrend"])
+	assert.Equal(t, "28.80", got["pressL"])
+}
+
+func TestBearingRange(t *testing.T) {
+	samples := []reporting.Sample{
+		{Observations: 1, WindSpeed: 5 * unit.KilometersPerHour, WindDir: 350 * unit.Degree},
+		{Observations: 1, WindSpeed: 5 * unit.KilometersPerHour, WindDir: 20 * unit.Degree},
+		{Observations: 1, WindSpeed: 0, WindDir: 180 * unit.Degree},
+		{},
+	}
+	from, to := bearingRange(5*unit.Degree, samples)
+	assert.InDelta(t, 350, from.Degrees(), 1e-9, "across north")
+	assert.InDelta(t, 20, to.Degrees(), 1e-9)
+
+	from, to = bearingRange(90*unit.Degree, nil)
+	assert.InDelta(t, 90, from.Degrees(), 1e-9)
+	assert.InDelta(t, 90, to.Degrees(), 1e-9)
+}
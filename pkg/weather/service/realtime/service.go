@@ -3,13 +3,19 @@ package realtime
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"time"
 
 	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
 	"github.com/lmacrc/weather/pkg/weather/reporting"
 	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/martinlindhe/unit"
+	"github.com/mitchellh/mapstructure"
 	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -20,6 +26,10 @@ import (
 var (
 	// NewStatistics is a topic for publishing new statistics calculations.
 	NewStatistics = event.T("realtime:new_stats")
+
+	// RapidPressureFall is a topic for publishing when the pressure starts falling by at least the
+	// fall warning of the reporting configuration. The *reporting.Statistics are published.
+	RapidPressureFall = event.T("realtime:rapid_pressure_fall")
 )
 
 type Service struct {
@@ -29,15 +39,21 @@ type Service struct {
 	schedule   cron.Schedule
 	bus        *event.Bus
 	remotePath string
+	stale      service.StalePolicy
+	gauges     bool
+	cal        *calendar.Calendar
 }
 
 type Reporter interface {
 	Generate(ts time.Time) *reporting.Statistics
+	History(start, end time.Time, interval time.Duration) ([]reporting.Sample, error)
+	Records(start, end time.Time) (*reporting.Records, error)
+	WindRose(start, end time.Time, classes []unit.Speed) meteorology.WindRose
 }
 
 func New(log *zap.Logger, v *viper.Viper, reporter Reporter, ftp service.Ftp, bus *event.Bus) (*Service, error) {
-	var cfg Config
-	if err := v.UnmarshalKey("realtime", &cfg); err != nil {
+	cfg := NewConfig()
+	if err := v.UnmarshalKey("realtime", &cfg, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
 		return nil, fmt.Errorf("config: %w", err)
 	}
 
@@ -46,6 +62,11 @@ func New(log *zap.Logger, v *viper.Viper, reporter Reporter, ftp service.Ftp, bu
 		return nil, fmt.Errorf("parsing cron: %w", err)
 	}
 
+	cal, err := calendar.New(v)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
 		log:        log.With(zap.String("service", "realtime")),
 		reporter:   reporter,
@@ -53,14 +74,18 @@ func New(log *zap.Logger, v *viper.Viper, reporter Reporter, ftp service.Ftp, bu
 		schedule:   schedule,
 		bus:        bus,
 		remotePath: cfg.RemoteDir,
+		stale:      cfg.Stale,
+		gauges:     cfg.Gauges,
+		cal:        cal,
 	}, nil
 }
 
 func (s Service) Run(ctx context.Context) {
 	s.log.Info("Starting.")
 
+	var fallWarning bool
 	for {
-		ts := time.Now()
+		ts := time.Now().In(s.cal.Location())
 		next := s.schedule.Next(ts)
 		sleep := next.Sub(ts)
 		s.log.Info("Next upload scheduled.", zap.Time("time", next), zap.Duration("wait_time", sleep))
@@ -76,29 +101,125 @@ func (s Service) Run(ctx context.Context) {
 			stats := s.reporter.Generate(next)
 			s.bus.Publish(NewStatistics, stats)
 
+			if stats.PressureFallWarning && !fallWarning {
+				s.log.Warn("Pressure falling rapidly.",
+					zap.Float64("change_hpa", stats.PressureChange.Hectopascals()),
+					zap.String("tendency", stats.PressureTendency.String()))
+				s.bus.Publish(RapidPressureFall, stats)
+			}
+			fallWarning = stats.PressureFallWarning
+
+			stats, ok := s.stale.Apply(stats)
+			if !ok {
+				s.log.Warn("Sensor contact lost, skipping realtime.txt.")
+				continue
+			}
+
 			data, err := Statistics(*stats).MarshalText()
 			if err != nil {
 				s.log.Error("Unable to marshal realtime.txt statistics data.", zap.Error(err))
 				continue
 			}
 
-			err = os.WriteFile("realtime.txt", data, 0777)
+			expiresAt := s.schedule.Next(time.Now())
+			s.publish("realtime.txt", data, expiresAt)
+
+			if !s.gauges {
+				continue
+			}
+
+			g, err := s.Gauges(stats)
 			if err != nil {
-				s.log.Error("Unable to write realtime.txt file.", zap.Error(err))
+				s.log.Error("Unable to query realtimegauges.txt history.", zap.Error(err))
 				continue
 			}
 
-			expiresAt := s.schedule.Next(time.Now())
-			s.log.Info("Enqueue realtime.txt for upload.", zap.Time("expires_at", expiresAt))
-			err = s.ftp.Enqueue(service.FtpRequest{
-				LocalPath:      "realtime.txt",
-				RemoteDir:      s.remotePath,
-				RemoteFilename: "realtime.txt",
-				ExpiresAt:      &expiresAt,
-			})
+			data, err = json.Marshal(g)
 			if err != nil {
-				s.log.Error("Failed to enqueue realtime.txt for upload.", zap.Error(err))
+				s.log.Error("Unable to marshal realtimegauges.txt data.", zap.Error(err))
+				continue
 			}
+
+			s.publish("realtimegauges.txt", data, expiresAt)
 		}
 	}
 }
+
+// publish writes the file and enqueues it for upload, until expiresAt.
+func (s Service) publish(filename string, data []byte, expiresAt time.Time) {
+	err := os.WriteFile(filename, data, 0777)
+	if err != nil {
+		s.log.Error("Unable to write file.", zap.String("filename", filename), zap.Error(err))
+		return
+	}
+
+	s.log.Info("Enqueue file for upload.", zap.String("filename", filename), zap.Time("expires_at", expiresAt))
+	err = s.ftp.Enqueue(service.FtpRequest{
+		LocalPath:      filename,
+		RemoteDir:      s.remotePath,
+		RemoteFilename: filename,
+		ExpiresAt:      &expiresAt,
+	})
+	if err != nil {
+		s.log.Error("Failed to enqueue file for upload.", zap.String("filename", filename), zap.Error(err))
+	}
+}
+
+// Gauges returns the data of realtimegauges.txt for stats.
+func (s Service) Gauges(stats *reporting.Statistics) (*Gauges, error) {
+	// include the observation at the time of the statistics
+	ts := s.cal.In(stats.Timestamp)
+	start, end := s.cal.BeginningOfDay(ts), ts.Add(time.Second)
+
+	g := &Gauges{Stats: stats, WindRose: s.reporter.WindRose(start, end, nil)}
+
+	today, err := s.reporter.History(start, end, end.Sub(start))
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	if len(today) > 0 {
+		g.Today = today[0]
+	}
+
+	hours, err := s.reporter.History(start, end, time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	for _, h := range hours {
+		if h.Rain > g.HourlyRainHi {
+			g.HourlyRainHi, g.HourlyRainHiTime = h.Rain, h.RainTime
+		}
+	}
+
+	minutes, err := s.reporter.History(end.Add(-10*time.Minute), end, time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	g.BearingFrom, g.BearingTo = bearingRange(stats.TenMinWindBearingAvg, minutes)
+
+	g.AllTime, err = s.reporter.Records(time.Time{}, s.cal.Date(ts))
+	if err != nil {
+		return nil, fmt.Errorf("records: %w", err)
+	}
+	g.AllTime.Include(stats)
+
+	return g, nil
+}
+
+// bearingRange returns the range, clockwise, of the wind directions of the samples about the average.
+// Calm samples are excluded.
+func bearingRange(avg unit.Angle, samples []reporting.Sample) (from, to unit.Angle) {
+	var lo, hi float64
+	for _, m := range samples {
+		if m.Observations == 0 || m.WindSpeed == 0 {
+			continue
+		}
+		d := math.Mod(m.WindDir.Degrees()-avg.Degrees()+540, 360) - 180
+		lo, hi = math.Min(lo, d), math.Max(hi, d)
+	}
+
+	bearing := func(deg float64) unit.Angle {
+		return unit.Angle(math.Mod(deg+360, 360)) * unit.Degree
+	}
+	return bearing(avg.Degrees() + lo), bearing(avg.Degrees() + hi)
+}
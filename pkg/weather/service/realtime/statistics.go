@@ -5,14 +5,35 @@ import (
 	"time"
 
 	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/units"
 	"github.com/lmacrc/weather/pkg/xunit"
 	"github.com/martinlindhe/unit"
 )
 
 type Statistics reporting.Statistics
 
+// units returns the unit system named by the unit labels of s, using the
+// metric unit for any label which is missing or invalid.
+func (s Statistics) units() units.System {
+	sys := units.Metric
+	if u, err := units.ParseTemperature(s.TempUnits); err == nil {
+		sys.Temperature = u
+	}
+	if u, err := units.ParseSpeed(s.WindUnits); err == nil {
+		sys.Speed = u
+	}
+	if u, err := units.ParsePressure(s.PressureUnits); err == nil {
+		sys.Pressure = u
+	}
+	if u, err := units.ParseRain(s.RainUnits); err == nil {
+		sys.Rain = u
+	}
+	return sys
+}
+
 type buffer struct {
 	b []byte
+	u units.System
 }
 
 func (b *buffer) Bytes() []byte {
@@ -60,7 +81,7 @@ func (b *buffer) Time(t time.Time) {
 }
 
 func (b *buffer) Temp(t unit.Temperature) {
-	b.Float(t.Celsius(), 1)
+	b.Float(b.u.Temperature.To(t), 1)
 }
 
 func (b *buffer) Hours(t time.Duration) {
@@ -68,11 +89,18 @@ func (b *buffer) Hours(t time.Duration) {
 }
 
 func (b *buffer) Pressure(t unit.Pressure) {
-	b.Float(t.Hectopascals(), 1)
+	b.Float(b.u.Pressure.To(t), b.pressurePrec())
 }
 
 func (b *buffer) SignedPressure(t unit.Pressure) {
-	b.SignedFloat(t.Hectopascals(), 1)
+	b.SignedFloat(b.u.Pressure.To(t), b.pressurePrec())
+}
+
+func (b *buffer) pressurePrec() int {
+	if b.u.Pressure == units.InchOfMercury {
+		return 2
+	}
+	return 1
 }
 
 func (b *buffer) Bearing(v unit.Angle) {
@@ -83,16 +111,21 @@ func (b *buffer) Irradiance(v xunit.Irradiance) {
 	b.Float(v.WattsPerSquareMetre(), 1)
 }
 
-func (b *buffer) SpeedKph(v unit.Speed) {
-	b.Float(v.KilometersPerHour(), 1)
+func (b *buffer) Speed(v unit.Speed) {
+	b.Float(b.u.Speed.To(v), 1)
 }
 
-func (b *buffer) LengthKm(v unit.Length) {
-	b.Float(v.Kilometers(), 1)
+// WindRun writes v in the units of distance of the wind speed.
+func (b *buffer) WindRun(v unit.Length) {
+	b.Float(b.u.Speed.Distance().To(v), 1)
 }
 
-func (b *buffer) LengthMm(v unit.Length) {
-	b.Float(v.Millimeters(), 1)
+func (b *buffer) Rain(v unit.Length) {
+	prec := 1
+	if b.u.Rain == units.Inches {
+		prec = 2
+	}
+	b.Float(b.u.Rain.To(v), prec)
 }
 
 func (b *buffer) Bool(v bool) {
@@ -103,8 +136,9 @@ func (b *buffer) Bool(v bool) {
 	}
 }
 
+// SignedTemp writes the temperature change t, which is expressed as an offset from 0°C.
 func (b *buffer) SignedTemp(t unit.Temperature) {
-	b.SignedFloat(t.Celsius(), 1)
+	b.SignedFloat(b.u.Temperature.Delta(unit.Temperature(t.Celsius())), 1)
 }
 
 func (b *buffer) ShortTime(t time.Time) {
@@ -122,18 +156,18 @@ func (b *buffer) Append(d ...byte) {
 }
 
 func (s Statistics) MarshalText() (text []byte, err error) {
-	b := buffer{b: make([]byte, 0, 512)}
+	b := buffer{b: make([]byte, 0, 512), u: s.units()}
 
 	b.Date(s.Timestamp)                                 // 01
 	b.Time(s.Timestamp)                                 // 02
 	b.Temp(s.OutdoorTemperature)                        // 03
 	b.Int(s.OutdoorHumidity)                            // 04
 	b.Temp(s.DewPoint)                                  // 05
-	b.SpeedKph(s.WindSpeedAvg)                          // 06
-	b.SpeedKph(s.WindSpeedLast)                         // 07
+	b.Speed(s.WindSpeedAvg)                             // 06
+	b.Speed(s.WindSpeedLast)                            // 07
 	b.Bearing(s.WindBearing)                            // 08
-	b.LengthMm(s.RainRate)                              // 09
-	b.LengthMm(s.RainfallToday)                         // 10
+	b.Rain(s.RainRate)                                  // 09
+	b.Rain(s.RainfallToday)                             // 10
 	b.Pressure(s.BarometricPressure)                    // 11
 	b.String(string(s.WindDirection))                   // 12
 	b.Int(s.WindForce)                                  // 13
@@ -141,11 +175,11 @@ func (s Statistics) MarshalText() (text []byte, err error) {
 	b.String(s.TempUnits)                               // 15
 	b.String(s.PressureUnits)                           // 16
 	b.String(s.RainUnits)                               // 17
-	b.LengthKm(s.WindRun)                               // 18
+	b.WindRun(s.WindRun)                                // 18
 	b.SignedPressure(s.PressureTrend)                   // 19
-	b.LengthMm(s.MonthlyRainfall)                       // 20
-	b.LengthMm(s.YearlyRainfall)                        // 21
-	b.LengthMm(s.YesterdayRainfall)                     // 22
+	b.Rain(s.MonthlyRainfall)                           // 20
+	b.Rain(s.YearlyRainfall)                            // 21
+	b.Rain(s.YesterdayRainfall)                         // 22
 	b.Temp(s.IndoorTemp)                                // 23
 	b.Int(s.IndoorHumidity)                             // 24
 	b.Temp(s.WindChill)                                 // 25
@@ -154,9 +188,9 @@ func (s Statistics) MarshalText() (text []byte, err error) {
 	b.ShortTime(s.TodayTempHiTime)                      // 28
 	b.Temp(s.TodayTempLo)                               // 29
 	b.ShortTime(s.TodayTempLoTime)                      // 30
-	b.SpeedKph(s.TodayWindHi)                           // 31
+	b.Speed(s.TodayWindHi)                              // 31
 	b.ShortTime(s.TodayWindHiTime)                      // 32
-	b.SpeedKph(s.TodayWindGustHi)                       // 33
+	b.Speed(s.TodayWindGustHi)                          // 33
 	b.ShortTime(s.TodayWindGustHiTime)                  // 34
 	b.Pressure(s.TodayPressureHi)                       // 35
 	b.ShortTime(s.TodayPressureHiTime)                  // 36
@@ -164,15 +198,15 @@ func (s Statistics) MarshalText() (text []byte, err error) {
 	b.ShortTime(s.TodayPressureLoTime)                  // 38
 	b.String(s.CumulusVersion)                          // 39
 	b.Int(s.CumulusBuildNumber)                         // 40
-	b.SpeedKph(s.TenMinGustHi)                          // 41
+	b.Speed(s.TenMinGustHi)                             // 41
 	b.Temp(s.HeatIndex)                                 // 42
 	b.Temp(s.Humidex)                                   // 43
 	b.Int(s.UVIndex)                                    // 44
-	b.Float1(s.Evapotranspiration)                      // 45
+	b.Rain(s.Evapotranspiration)                        // 45
 	b.Irradiance(s.SolarRadiation)                      // 46
 	b.Bearing(s.TenMinWindBearingAvg)                   // 47
-	b.LengthMm(s.RainfallLastHour)                      // 48
-	b.Int(s.ZambrettiForecast)                          // 49
+	b.Rain(s.RainfallLastHour)                          // 48
+	b.Int(s.ZambrettiForecast.ToInt())                  // 49
 	b.Bool(s.IsDaylight)                                // 50
 	b.Bool(s.SensorContactLost)                         // 51
 	b.String(string(s.WindDirectionAvg))                // 52
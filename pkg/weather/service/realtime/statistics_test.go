@@ -1,6 +1,7 @@
 package realtime
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -9,8 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestStatistics_MarshalText(t *testing.T) {
-	stats := Statistics{
+func testStatistics() Statistics {
+	return Statistics{
 		Timestamp:            time.Date(2008, 10, 18, 16, 3, 45, 0, time.UTC),
 		OutdoorTemperature:   unit.FromCelsius(8.4),
 		OutdoorHumidity:      84,
@@ -51,10 +52,10 @@ func TestStatistics_MarshalText(t *testing.T) {
 		CumulusVersion:       "1.8.2",
 		CumulusBuildNumber:   448,
 		TenMinGustHi:         36.0 * unit.KilometersPerHour,
-		HeatIndex:            10.3,
-		Humidex:              10.5,
+		HeatIndex:            unit.FromCelsius(10.3),
+		Humidex:              unit.FromCelsius(10.5),
 		UVIndex:              1,
-		Evapotranspiration:   1,
+		Evapotranspiration:   1 * unit.Millimeter,
 		SolarRadiation:       1,
 		TenMinWindBearingAvg: 234 * unit.Degree,
 		RainfallLastHour:     2.5 * unit.Millimeter,
@@ -70,9 +71,36 @@ func TestStatistics_MarshalText(t *testing.T) {
 		IsSunny:              true,
 		TempFeelsLike:        unit.FromCelsius(8.4),
 	}
+}
+
+func TestStatistics_MarshalText(t *testing.T) {
+	stats := testStatistics()
 
 	got, err := stats.MarshalText()
 	assert.NoError(t, err)
 	const exp = `18/10/08 16:03:45 8.4 84 5.8 24.2 33.0 261 0.0 1.0 999.7 W 6 kph C hPa mm 146.6 +0.1 85.2 588.4 11.6 20.3 57 3.6 -0.7 10.9 12:00 7.8 14:41 37.4 14:38 44.0 14:28 999.8 16:01 998.4 12:06 1.8.2 448 36.0 10.3 10.5 1 1.0 1.0 234 2.5 5 1 0 NNW 2040 ft 12.3 11.4 420 1 8.4`
 	assert.Equal(t, exp, string(got))
 }
+
+func TestStatistics_MarshalText_US(t *testing.T) {
+	stats := testStatistics()
+	stats.WindUnits = "mph"
+	stats.TempUnits = "F"
+	stats.PressureUnits = "in"
+	stats.RainUnits = "in"
+
+	got, err := stats.MarshalText()
+	assert.NoError(t, err)
+
+	fields := strings.Fields(string(got))
+	field := func(n int) string { return fields[n-1] }
+	assert.Equal(t, "47.1", field(3), "temperature")
+	assert.Equal(t, "15.0", field(6), "wind speed")
+	assert.Equal(t, "0.04", field(10), "rainfall today")
+	assert.Equal(t, "29.52", field(11), "pressure")
+	assert.Equal(t, "mph F in in", strings.Join(fields[13:17], " "))
+	assert.Equal(t, "91.1", field(18), "wind run in miles")
+	assert.Equal(t, "+0.00", field(19), "pressure trend")
+	assert.Equal(t, "-1.3", field(26), "temperature trend")
+	assert.Equal(t, "23.17", field(21), "yearly rainfall")
+}
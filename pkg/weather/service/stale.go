@@ -0,0 +1,46 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+)
+
+// StalePolicy specifies how a service publishes statistics calculated after
+// contact with the weather station sensors has been lost.
+type StalePolicy string
+
+const (
+	StalePolicySkip    StalePolicy = "skip"    // StalePolicySkip does not publish stale statistics.
+	StalePolicyMark    StalePolicy = "mark"    // StalePolicyMark publishes stale statistics, flagged as sensor contact lost.
+	StalePolicyPublish StalePolicy = "publish" // StalePolicyPublish publishes stale statistics as if they were current.
+)
+
+func (p *StalePolicy) UnmarshalText(text []byte) error {
+	switch StalePolicy(text) {
+	case StalePolicySkip, StalePolicyMark, StalePolicyPublish:
+		*p = StalePolicy(text)
+	default:
+		return fmt.Errorf("invalid stale policy %s: expect skip,mark,publish", string(text))
+	}
+	return nil
+}
+
+// Apply returns the statistics to publish according to the policy, or false if
+// the statistics should not be published.
+func (p StalePolicy) Apply(stats *reporting.Statistics) (*reporting.Statistics, bool) {
+	if !stats.SensorContactLost {
+		return stats, true
+	}
+
+	switch p {
+	case StalePolicySkip:
+		return nil, false
+	case StalePolicyPublish:
+		res := *stats
+		res.SensorContactLost = false
+		return &res, true
+	default:
+		return stats, true
+	}
+}
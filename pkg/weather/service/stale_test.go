@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStalePolicy_Apply(t *testing.T) {
+	current := &reporting.Statistics{}
+	stale := &reporting.Statistics{SensorContactLost: true}
+
+	for _, p := range []StalePolicy{StalePolicySkip, StalePolicyMark, StalePolicyPublish} {
+		got, ok := p.Apply(current)
+		assert.True(t, ok, string(p))
+		assert.Same(t, current, got, string(p))
+	}
+
+	_, ok := StalePolicySkip.Apply(stale)
+	assert.False(t, ok)
+
+	got, ok := StalePolicyMark.Apply(stale)
+	assert.True(t, ok)
+	assert.True(t, got.SensorContactLost)
+
+	got, ok = StalePolicyPublish.Apply(stale)
+	assert.True(t, ok)
+	assert.False(t, got.SensorContactLost)
+	assert.True(t, stale.SensorContactLost, "original is unchanged")
+}
+
+func TestStalePolicy_UnmarshalText(t *testing.T) {
+	var p StalePolicy
+	assert.NoError(t, p.UnmarshalText([]byte("skip")))
+	assert.Equal(t, StalePolicySkip, p)
+	assert.Error(t, p.UnmarshalText([]byte("ignore")))
+}
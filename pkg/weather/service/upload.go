@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// IntervalSlack allows for an observation, or the statistics generated from it, arriving slightly
+// before the end of an upload interval, so the upload is not delayed until the following one.
+const IntervalSlack = 5 * time.Second
+
+// IntervalElapsed returns true when an upload at t is due, the interval, less IntervalSlack, having
+// elapsed since the last upload. The interval is never reduced below min, the minimum interval
+// accepted by the receiving network, which is zero when it has none.
+func IntervalElapsed(last, t time.Time, interval, min time.Duration) bool {
+	interval -= IntervalSlack
+	if interval < min {
+		interval = min
+	}
+	return t.Sub(last) >= interval
+}
+
+// RedactURL returns the error of a failed HTTP request without the URL, which may contain
+// the credentials of the request.
+func RedactURL(err error) error {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return uerr.Err
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalElapsed(t *testing.T) {
+	last := time.Date(2021, 12, 1, 14, 30, 12, 0, time.UTC)
+
+	for _, tc := range []struct {
+		interval, min, after time.Duration
+		want                 bool
+	}{
+		{5 * time.Minute, 0, time.Minute, false},
+		{5 * time.Minute, 0, 5*time.Minute - 2*time.Second, true},
+		{5 * time.Minute, 0, 5*time.Minute - 6*time.Second, false},
+		{5 * time.Minute, 5 * time.Minute, 5*time.Minute - 2*time.Second, false},
+		{5 * time.Minute, 5 * time.Minute, 5 * time.Minute, true},
+		{10 * time.Minute, 5 * time.Minute, 10*time.Minute - 2*time.Second, true},
+	} {
+		assert.Equal(t, tc.want, IntervalElapsed(last, last.Add(tc.after), tc.interval, tc.min),
+			"%s after %s, minimum %s", tc.interval, tc.after, tc.min)
+	}
+	assert.True(t, IntervalElapsed(time.Time{}, last, 5*time.Minute, 5*time.Minute), "first upload")
+}
+
+func TestRedactURL(t *testing.T) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://127.0.0.1:0/?PASSWORD=secret", nil)
+	require.NoError(t, err)
+	_, err = http.DefaultClient.Do(req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secret")
+	assert.NotContains(t, RedactURL(err).Error(), "secret")
+
+	other := errors.New("unexpected status")
+	assert.Equal(t, other, RedactURL(other))
+}
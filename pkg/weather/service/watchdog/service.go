@@ -0,0 +1,148 @@
+// Package watchdog is responsible for detecting when contact with the weather station sensors is lost.
+package watchdog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	// ContactLost is a topic for publishing when contact with the sensors is lost.
+	// The time of the last observation is published.
+	ContactLost = event.T("watchdog:contact_lost")
+
+	// ContactRestored is a topic for publishing when contact with the sensors is restored.
+	// The time of the new observation is published.
+	ContactRestored = event.T("watchdog:contact_restored")
+)
+
+var (
+	sensorContactLost = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "weather",
+		Subsystem: "sensor",
+		Name:      "contact_lost",
+		Help:      "Set to 1 when contact with the weather station sensors is lost, otherwise 0",
+	})
+
+	lastObservationTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "weather",
+		Subsystem: "sensor",
+		Name:      "last_observation_timestamp_seconds",
+		Help:      "The time of the last observation, in seconds since the epoch",
+	})
+)
+
+// checkInterval is how often the service checks for contact being lost.
+const checkInterval = time.Minute
+
+type Service struct {
+	log     *zap.Logger
+	bus     *event.Bus
+	timeout time.Duration
+	ch      chan struct{}
+
+	mu   sync.Mutex
+	last time.Time
+	lost bool
+}
+
+func New(log *zap.Logger, v *viper.Viper, s *store.Store, bus *event.Bus) (*Service, error) {
+	cfg, err := reporting.ReadConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Service{
+		log:     log.With(zap.String("service", "watchdog")),
+		bus:     bus,
+		timeout: cfg.SensorTimeout,
+		ch:      make(chan struct{}, 1),
+	}
+
+	if o := s.LastObservation(time.Now()); o != nil {
+		w.last = o.Timestamp
+		lastObservationTimestamp.Set(float64(o.Timestamp.Unix()))
+	}
+
+	bus.MustSubscribe(store.NewObservation, w.HandleObservation)
+
+	return w, nil
+}
+
+func (s *Service) HandleObservation(o *model.Observation) {
+	s.mu.Lock()
+	if o.Timestamp.After(s.last) {
+		s.last = o.Timestamp
+	}
+	s.mu.Unlock()
+
+	lastObservationTimestamp.Set(float64(o.Timestamp.Unix()))
+
+	// check asynchronously, as events cannot be published whilst handling an event
+	select {
+	case s.ch <- struct{}{}:
+	default:
+	}
+}
+
+// Check determines whether contact with the sensors is lost at time ts, publishing
+// an event when contact is lost or restored.
+func (s *Service) Check(ts time.Time) {
+	s.mu.Lock()
+	last := s.last
+	lost := last.IsZero() || ts.Sub(last) > s.timeout
+	changed := lost != s.lost
+	s.lost = lost
+	s.mu.Unlock()
+
+	if lost {
+		sensorContactLost.Set(1)
+	} else {
+		sensorContactLost.Set(0)
+	}
+
+	if !changed {
+		return
+	}
+
+	if lost {
+		s.log.Warn("Sensor contact lost.", zap.Time("last_observation", last))
+		s.bus.Publish(ContactLost, last)
+	} else {
+		s.log.Info("Sensor contact restored.", zap.Time("last_observation", last))
+		s.bus.Publish(ContactRestored, last)
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	s.log.Info("Starting.")
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	s.Check(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Shutting down.")
+			return
+
+		case <-s.ch:
+			s.Check(time.Now())
+
+		case <-ticker.C:
+			s.Check(time.Now())
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package watchdog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestService_Check(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "weather.db")), &gorm.Config{})
+	require.NoError(t, err)
+
+	bus := event.New()
+	st, err := store.New(db, bus)
+	require.NoError(t, err)
+
+	vp := viper.New()
+	vp.Set("reporting.sensor_timeout", "5m")
+
+	s, err := New(zap.NewNop(), vp, st, bus)
+	require.NoError(t, err)
+
+	var lost, restored int
+	bus.MustSubscribe(ContactLost, func(time.Time) { lost++ })
+	bus.MustSubscribe(ContactRestored, func(time.Time) { restored++ })
+
+	ts := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	s.Check(ts)
+	assert.Equal(t, 1, lost, "no observations")
+
+	_, err = st.WriteObservation(model.Observation{Timestamp: ts})
+	require.NoError(t, err)
+	s.Check(ts.Add(time.Minute))
+	assert.Equal(t, 1, restored)
+
+	s.Check(ts.Add(5 * time.Minute))
+	assert.Equal(t, 1, lost, "within timeout")
+
+	s.Check(ts.Add(6 * time.Minute))
+	s.Check(ts.Add(7 * time.Minute))
+	assert.Equal(t, 2, lost, "publish once")
+	assert.Equal(t, 1, restored)
+}
@@ -0,0 +1,48 @@
+package webtags
+
+import (
+	"fmt"
+)
+
+// Format is the syntax of a template file.
+type Format string
+
+const (
+	FormatCumulus Format = "cumulus" // FormatCumulus templates contain Cumulus web tags, such as <#temp>.
+	FormatGo      Format = "go"      // FormatGo templates are Go text templates, such as {{ .Tag "temp" }}.
+)
+
+func (f *Format) UnmarshalText(text []byte) error {
+	switch Format(text) {
+	case FormatCumulus, FormatGo:
+		*f = Format(text)
+	default:
+		return fmt.Errorf("invalid format %s: expect cumulus,go", string(text))
+	}
+	return nil
+}
+
+type FileConfig struct {
+	Template  string // Template is the path of the template file
+	Output    string // Output is the name of the rendered file, a template of the Date of the statistics
+	RemoteDir string `toml:"remote_dir" mapstructure:"remote_dir"` // RemoteDir overrides the remote directory of the section
+	Cron      string // Cron overrides the schedule of the section
+	Format    Format // Format overrides the format of the section
+}
+
+type Config struct {
+	Enabled   bool
+	Cron      string
+	LocalDir  string `toml:"local_dir" mapstructure:"local_dir"`
+	RemoteDir string `toml:"remote_dir" mapstructure:"remote_dir"`
+	Format    Format
+	Files     []FileConfig
+}
+
+func NewConfig() Config {
+	return Config{
+		Cron:     "*/15 * * * *",
+		LocalDir: ".",
+		Format:   FormatCumulus,
+	}
+}
@@ -0,0 +1,257 @@
+// Package webtags is responsible for rendering user-provided template files, containing Cumulus
+// web tags or Go templates, with the statistics of the station and publishing them.
+package webtags
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	gotemplate "text/template"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/filepath/template"
+	"github.com/lmacrc/weather/pkg/weather/calendar"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/lmacrc/weather/pkg/weather/webtag"
+	"github.com/mitchellh/mapstructure"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Reporter provides the statistics, history and records of the web tags.
+type Reporter interface {
+	Generate(ts time.Time) *reporting.Statistics
+	History(start, end time.Time, interval time.Duration) ([]reporting.Sample, error)
+	Records(start, end time.Time) (*reporting.Records, error)
+	Units() units.System
+}
+
+type Service struct {
+	log      *zap.Logger
+	reporter Reporter
+	ftp      service.Ftp
+	cal      *calendar.Calendar
+	station  webtag.Station
+	localDir string
+	jobs     []*Job
+}
+
+// Job renders a template file on a schedule.
+type Job struct {
+	s         *Service
+	schedule  cron.Schedule
+	template  string
+	format    Format
+	output    *template.Template
+	remoteDir string
+}
+
+func New(log *zap.Logger, v *viper.Viper, reporter Reporter, ftp service.Ftp) (*Service, error) {
+	cfg := NewConfig()
+	if err := v.UnmarshalKey("webtags", &cfg, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	cal, err := calendar.New(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var station webtag.Station
+	if err := v.UnmarshalKey("location", &station); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	s := &Service{
+		log:      log.With(zap.String("service", "webtags")),
+		reporter: reporter,
+		ftp:      ftp,
+		cal:      cal,
+		station:  station,
+		localDir: cfg.LocalDir,
+	}
+
+	for i, f := range cfg.Files {
+		if f.Template == "" {
+			return nil, fmt.Errorf("config: files[%d]: template cannot be empty", i)
+		}
+		if f.Output == "" {
+			f.Output = filepath.Base(f.Template)
+		}
+		if f.Cron == "" {
+			f.Cron = cfg.Cron
+		}
+		if f.RemoteDir == "" {
+			f.RemoteDir = cfg.RemoteDir
+		}
+		if f.Format == "" {
+			f.Format = cfg.Format
+		}
+
+		schedule, err := cron.ParseStandard(f.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("%s: parsing cron: %w", f.Template, err)
+		}
+		output, err := template.New("output").Parse(f.Output)
+		if err != nil {
+			return nil, fmt.Errorf("%s: parsing output: %w", f.Template, err)
+		}
+		// parse the template now, so an invalid template is reported at startup
+		if _, err := parseTemplate(f.Template, f.Format); err != nil {
+			return nil, err
+		}
+
+		s.jobs = append(s.jobs, &Job{
+			s:         s,
+			schedule:  schedule,
+			template:  f.Template,
+			format:    f.Format,
+			output:    output,
+			remoteDir: f.RemoteDir,
+		})
+	}
+
+	return s, nil
+}
+
+// Jobs returns a job for each template file.
+func (s *Service) Jobs() []*Job { return s.jobs }
+
+// Schedule returns the schedule for rendering the template.
+func (j *Job) Schedule() cron.Schedule { return j.schedule }
+
+// Run renders the template with the current statistics, and enqueues the file for upload.
+func (j *Job) Run() {
+	s := j.s
+	ts := s.cal.In(time.Now())
+	s.log.Info("Rendering template.", zap.String("template", j.template))
+
+	d, err := s.Data(ts)
+	if err != nil {
+		s.log.Error("Unable to query web tag data.", zap.Error(err))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := j.output.Execute(&buf, map[string]interface{}{"Date": ts}); err != nil {
+		s.log.Error("Failed to render output filename.", zap.String("template", j.template), zap.Error(err))
+		return
+	}
+	filename := buf.String()
+	path := filepath.Join(s.localDir, filename)
+
+	buf.Reset()
+	if err := s.Render(&buf, j.template, j.format, d); err != nil {
+		s.log.Error("Failed to render template.", zap.String("template", j.template), zap.Error(err))
+		return
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		s.log.Error("Unable to write rendered template.", zap.String("path", path), zap.Error(err))
+		return
+	}
+	s.log.Info("Template rendered to file.", zap.String("path", path))
+
+	if s.ftp == nil {
+		return
+	}
+	expiresAt := j.schedule.Next(time.Now())
+	err = s.ftp.Enqueue(service.FtpRequest{
+		LocalPath:      path,
+		RemoteDir:      j.remoteDir,
+		RemoteFilename: filename,
+		ExpiresAt:      &expiresAt,
+	})
+	if err != nil {
+		s.log.Error("Failed to enqueue rendered template for upload.", zap.String("path", path), zap.Error(err))
+	}
+}
+
+// Render writes the template file at path, of the format, rendered with d.
+func (s *Service) Render(w io.Writer, path string, format Format, d *webtag.Data) error {
+	t, err := parseTemplate(path, format)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, d)
+}
+
+// executor is a parsed template file.
+type executor interface {
+	Execute(w io.Writer, d *webtag.Data) error
+}
+
+type goTemplate struct {
+	t *gotemplate.Template
+}
+
+func (t goTemplate) Execute(w io.Writer, d *webtag.Data) error { return t.t.Execute(w, d) }
+
+func parseTemplate(path string, format Format) (executor, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatGo {
+		t, err := gotemplate.New(filepath.Base(path)).Funcs(template.FuncMap()).Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		return goTemplate{t}, nil
+	}
+	return webtag.Parse(string(text)), nil
+}
+
+// Data returns the statistics, history and records of the web tags at ts.
+func (s *Service) Data(ts time.Time) (*webtag.Data, error) {
+	ts = s.cal.In(ts)
+	stats := s.reporter.Generate(ts)
+	d := &webtag.Data{
+		Stats:   stats,
+		Units:   s.reporter.Units(),
+		Station: s.station,
+	}
+
+	// include the observation at the time of the statistics
+	end := ts.Add(time.Second)
+	start := s.cal.BeginningOfDay(ts)
+	today, err := s.reporter.History(start, end, end.Sub(start))
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	if len(today) > 0 {
+		d.Today = today[0]
+	}
+
+	hourAgo, err := s.reporter.History(end.Add(-65*time.Minute), end.Add(-55*time.Minute), 10*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	if len(hourAgo) > 0 && hourAgo[0].Observations > 0 {
+		d.TempHourAgo = hourAgo[0].Temp
+	}
+
+	date := s.cal.Date(ts)
+	for _, r := range []struct {
+		start time.Time
+		rec   **reporting.Records
+	}{
+		{time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()), &d.Month},
+		{time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location()), &d.Year},
+		{time.Time{}, &d.AllTime},
+	} {
+		rec, err := s.reporter.Records(r.start, date)
+		if err != nil {
+			return nil, fmt.Errorf("records: %w", err)
+		}
+		rec.Include(stats)
+		*r.rec = rec
+	}
+
+	return d, nil
+}
@@ -0,0 +1,92 @@
+package webtags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeReporter struct{}
+
+func (fakeReporter) Generate(ts time.Time) *reporting.Statistics {
+	return &reporting.Statistics{Timestamp: ts, OutdoorTemperature: unit.FromCelsius(12.3)}
+}
+
+func (fakeReporter) History(start, end time.Time, interval time.Duration) ([]reporting.Sample, error) {
+	return []reporting.Sample{{Time: start, Observations: 1, Temp: unit.FromCelsius(10.3)}}, nil
+}
+
+func (fakeReporter) Records(start, end time.Time) (*reporting.Records, error) {
+	return &reporting.Records{}, nil
+}
+
+func (fakeReporter) Units() units.System { return units.Metric }
+
+type fakeFtp []service.FtpRequest
+
+func (f *fakeFtp) Enqueue(req service.FtpRequest) error {
+	*f = append(*f, req)
+	return nil
+}
+
+func TestService_Run(t *testing.T) {
+	dir, out := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.htm"), []byte(`<b><#location>: <#temp>, <#TempChangeLastHour></b>`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"temp":{{ .Tag "temp" "dp=2" }},"year":{{ strftime "%Y" .Stats.Timestamp }}}`), 0644))
+
+	vp := viper.New()
+	vp.Set("location.name", "Launceston")
+	vp.Set("webtags.local_dir", out)
+	vp.Set("webtags.remote_dir", "www")
+	vp.Set("webtags.files", []map[string]interface{}{
+		{"template": filepath.Join(dir, "index.htm")},
+		{"template": filepath.Join(dir, "data.json"), "format": "go", "output": `data-{{ strftime "%Y" .Date }}.json`, "cron": "0 * * * *"},
+	})
+
+	var ftp fakeFtp
+	s, err := New(zap.NewNop(), vp, fakeReporter{}, &ftp)
+	require.NoError(t, err)
+	require.Len(t, s.Jobs(), 2)
+
+	for _, j := range s.Jobs() {
+		j.Run()
+	}
+
+	got, err := os.ReadFile(filepath.Join(out, "index.htm"))
+	require.NoError(t, err)
+	assert.Equal(t, "<b>Launceston: 12.3, 2.0</b>", string(got))
+
+	year := time.Now().Format("2006")
+	got, err = os.ReadFile(filepath.Join(out, "data-"+year+".json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"temp":12.30,"year":`+year+`}`, string(got))
+
+	require.Len(t, ftp, 2)
+	assert.Equal(t, "index.htm", ftp[0].RemoteFilename)
+	assert.Equal(t, "www", ftp[0].RemoteDir)
+	assert.Equal(t, "data-"+year+".json", ftp[1].RemoteFilename)
+}
+
+func TestNew_InvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.txt"), []byte(`{{ .Tag`), 0644))
+
+	vp := viper.New()
+	vp.Set("webtags.files", []map[string]interface{}{{"template": filepath.Join(dir, "bad.txt"), "format": "go"}})
+	_, err := New(zap.NewNop(), vp, fakeReporter{}, nil)
+	assert.Error(t, err)
+
+	vp.Set("webtags.files", []map[string]interface{}{{"template": filepath.Join(dir, "missing.txt")}})
+	_, err = New(zap.NewNop(), vp, fakeReporter{}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package windy
+
+import (
+	"time"
+)
+
+type Config struct {
+	Enabled    bool
+	URL        string        // URL is the base URL of the stations API, to which the API key is appended
+	APIKey     string        `toml:"api_key" mapstructure:"api_key"`
+	Station    int           // Station is the index of the station of the API key
+	Name       string        // Name is the name of the station, which defaults to that of the location
+	TempHeight float64       `toml:"temp_height" mapstructure:"temp_height"` // TempHeight is the height of the temperature sensor above ground, in metres
+	WindHeight float64       `toml:"wind_height" mapstructure:"wind_height"` // WindHeight is the height of the wind sensor above ground, in metres
+	Interval   time.Duration // Interval is the minimum time between uploads
+	Timeout    time.Duration // Timeout is the maximum time for each request
+}
+
+func NewConfig() Config {
+	return Config{
+		URL:        "https://stations.windy.com/pws/update",
+		TempHeight: 2,
+		WindHeight: 10,
+		Interval:   5 * time.Minute,
+		Timeout:    30 * time.Second,
+	}
+}
@@ -0,0 +1,215 @@
+// Package windy is responsible for uploading observations to the Windy stations API.
+package windy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/service"
+	"github.com/lmacrc/weather/pkg/weather/store"
+	"github.com/martinlindhe/unit"
+	"github.com/mitchellh/mapstructure"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	windyRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "weather",
+		Subsystem: "windy",
+		Name:      "requests_total",
+		Help:      "The total number of requests to register the station and upload observations to Windy",
+	}, []string{"request", "result"})
+)
+
+// minInterval is the minimum interval between uploads accepted by Windy, which ignores
+// the observations of a station uploaded more often.
+const minInterval = 5 * time.Minute
+
+// Reporter provides the precipitation of the last hour, which Windy expects in precip.
+type Reporter interface {
+	Rainfall(ts time.Time) (lastHour, today unit.Length)
+}
+
+type Service struct {
+	log            *zap.Logger
+	reporter       Reporter
+	client         *http.Client
+	url            string
+	station        Station
+	interval       time.Duration
+	barometricType reporting.BarometricMeasurementType
+	ch             chan *model.Observation
+
+	mu         sync.Mutex
+	last       time.Time // last is the time of the last observation queued for upload
+	registered bool      // registered is true once the station metadata is registered
+}
+
+// New returns a service uploading the observations of bus to Windy, registering the station
+// with the first upload.
+func New(log *zap.Logger, v *viper.Viper, reporter Reporter, bus *event.Bus) (*Service, error) {
+	cfg := NewConfig()
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToTimeDurationHookFunc(),
+	))
+	if err := v.UnmarshalKey("windy", &cfg, hook); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if cfg.APIKey == "" {
+		return nil, errors.New("config: windy api_key is required")
+	}
+	if cfg.Interval < minInterval {
+		return nil, fmt.Errorf("config: windy interval %s is less than %s", cfg.Interval, minInterval)
+	}
+
+	loc := struct {
+		Name                          string
+		Latitude, Longitude, Altitude float64
+	}{}
+	if err := v.UnmarshalKey("location", &loc); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if cfg.Name == "" {
+		cfg.Name = loc.Name
+	}
+
+	rc, err := reporting.ReadConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		log:      log.With(zap.String("service", "windy")),
+		reporter: reporter,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		url:      strings.TrimSuffix(cfg.URL, "/") + "/" + url.PathEscape(cfg.APIKey),
+		station: Station{
+			Station:    cfg.Station,
+			Name:       cfg.Name,
+			Latitude:   loc.Latitude,
+			Longitude:  loc.Longitude,
+			Elevation:  loc.Altitude,
+			TempHeight: cfg.TempHeight,
+			WindHeight: cfg.WindHeight,
+		},
+		interval:       cfg.Interval,
+		barometricType: rc.BarometricMeasurement,
+		ch:             make(chan *model.Observation, 1),
+	}
+
+	bus.MustSubscribe(store.NewObservation, s.HandleObservation)
+
+	return s, nil
+}
+
+// HandleObservation queues the observation for Run to upload, once the interval, and at least
+// the minimum interval of Windy, has elapsed since the last observation queued.
+func (s *Service) HandleObservation(o *model.Observation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !service.IntervalElapsed(s.last, o.Timestamp, s.interval, minInterval) {
+		return
+	}
+
+	select {
+	case s.ch <- o:
+		s.last = o.Timestamp
+	default:
+		s.log.Warn("Still uploading the previous observation, skipping.")
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	s.log.Info("Starting.")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Shutting down.")
+			return
+
+		case o := <-s.ch:
+			if err := s.Upload(ctx, o); err != nil {
+				s.log.Error("Failed to upload observation.", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Upload registers the station metadata, unless already registered, and uploads o.
+func (s *Service) Upload(ctx context.Context, o *model.Observation) error {
+	s.mu.Lock()
+	registered := s.registered
+	s.mu.Unlock()
+
+	if !registered {
+		if err := s.post(ctx, "register", map[string]interface{}{"stations": []Station{s.station}}); err != nil {
+			return fmt.Errorf("registering station: %w", err)
+		}
+		s.log.Info("Station registered.", zap.String("name", s.station.Name))
+
+		s.mu.Lock()
+		s.registered = true
+		s.mu.Unlock()
+	}
+
+	barometer := o.BarometricRel
+	if s.barometricType == reporting.BarometricMeasurementTypeAbsolute {
+		barometer = o.BarometricAbs
+	}
+	rain, _ := s.reporter.Rainfall(o.Timestamp)
+	obs := NewObservation(s.station.Station, o, barometer, rain)
+	return s.post(ctx, "observation", map[string]interface{}{"observations": []Observation{obs}})
+}
+
+// post posts the JSON of body, counting the request by name.
+func (s *Service) post(ctx context.Context, name string, body interface{}) (err error) {
+	defer func() {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		windyRequests.WithLabelValues(name, result).Inc()
+	}()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		// the API key is the last element of the path
+		return fmt.Errorf("request failed: %w", service.RedactURL(err))
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package windy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeReporter struct {
+	lastHour unit.Length
+}
+
+func (r fakeReporter) Rainfall(time.Time) (lastHour, today unit.Length) { return r.lastHour, 0 }
+
+func newService(t *testing.T, server string) *Service {
+	vp := viper.New()
+	vp.Set("location.name", "Launceston")
+	vp.Set("location.latitude", -41.44)
+	vp.Set("location.longitude", 147.23)
+	vp.Set("location.altitude", 180)
+	vp.Set("windy.url", server+"/pws/update/")
+	vp.Set("windy.api_key", "secret")
+
+	s, err := New(zap.NewNop(), vp, fakeReporter{}, event.New())
+	require.NoError(t, err)
+	return s
+}
+
+func TestService_Upload(t *testing.T) {
+	var bodies []map[string]json.RawMessage
+	status := http.StatusInternalServerError
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/pws/update/secret", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var body map[string]json.RawMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	s := newService(t, ts.URL)
+	s.reporter = fakeReporter{lastHour: 2.54 * unit.Millimeter}
+	o := &model.Observation{
+		Timestamp:     time.Now(),
+		TempOutdoor:   unit.FromCelsius(14),
+		BarometricRel: 1013.2 * unit.Hectopascal,
+		HourlyRain:    25.4 * unit.Millimeter,
+	}
+
+	err := s.Upload(context.Background(), o)
+	assert.EqualError(t, err, "registering station: unexpected status 500 Internal Server Error: ")
+	require.Len(t, bodies, 1)
+
+	status = http.StatusOK
+	require.NoError(t, s.Upload(context.Background(), o))
+	require.Len(t, bodies, 3, "registered again")
+	assert.JSONEq(t, `[{"station":0,"name":"Launceston","lat":-41.44,"lon":147.23,"elevation":180,"tempheight":2,"windheight":10}]`,
+		string(bodies[1]["stations"]))
+
+	var obs []Observation
+	require.NoError(t, json.Unmarshal(bodies[2]["observations"], &obs))
+	require.Len(t, obs, 1)
+	assert.Equal(t, 14.0, obs[0].Temp)
+	assert.Equal(t, 101320.0, *obs[0].Pressure)
+	assert.Equal(t, 2.5, obs[0].Precip, "rain of the last hour from the total rain counter")
+
+	require.NoError(t, s.Upload(context.Background(), o))
+	require.Len(t, bodies, 4)
+	assert.Contains(t, bodies[3], "observations")
+	assert.NotContains(t, bodies[3], "stations", "registered once")
+}
+
+func TestService_HandleObservation(t *testing.T) {
+	ts := time.Date(2021, 12, 1, 14, 30, 12, 0, time.UTC)
+	s := newService(t, "http://localhost")
+
+	s.HandleObservation(&model.Observation{Timestamp: ts})
+	require.Len(t, s.ch, 1)
+	<-s.ch
+
+	// Windy ignores an upload within its minimum interval, which the slack is not allowed to shorten
+	s.HandleObservation(&model.Observation{Timestamp: ts.Add(minInterval - 2*time.Second)})
+	assert.Empty(t, s.ch)
+	s.HandleObservation(&model.Observation{Timestamp: ts.Add(minInterval)})
+	assert.Len(t, s.ch, 1)
+}
+
+func TestNew_Config(t *testing.T) {
+	vp := viper.New()
+	_, err := New(zap.NewNop(), vp, fakeReporter{}, event.New())
+	assert.EqualError(t, err, "config: windy api_key is required")
+
+	vp.Set("windy.api_key", "secret")
+	vp.Set("windy.interval", "1m")
+	_, err = New(zap.NewNop(), vp, fakeReporter{}, event.New())
+	assert.EqualError(t, err, "config: windy interval 1m0s is less than 5m0s")
+}
@@ -0,0 +1,67 @@
+package windy
+
+import (
+	"math"
+
+	"github.com/lmacrc/weather/pkg/weather/meteorology"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+// Station is the metadata of a station, registered with Windy.
+type Station struct {
+	Station    int     `json:"station"`
+	Name       string  `json:"name"`
+	Latitude   float64 `json:"lat"`
+	Longitude  float64 `json:"lon"`
+	Elevation  float64 `json:"elevation"`
+	TempHeight float64 `json:"tempheight"`
+	WindHeight float64 `json:"windheight"`
+}
+
+// Observation is an observation in the SI units expected by Windy.
+type Observation struct {
+	Station        int      `json:"station"`
+	Time           string   `json:"time"`               // ISO 8601, in UTC
+	Temp           float64  `json:"temp"`               // °C
+	Wind           float64  `json:"wind"`               // m/s
+	Gust           float64  `json:"gust"`               // m/s
+	WindDir        int      `json:"winddir"`            // degrees
+	Humidity       *int     `json:"humidity,omitempty"` // %
+	DewPoint       *float64 `json:"dewpoint,omitempty"` // °C
+	Pressure       *float64 `json:"pressure,omitempty"` // Pa
+	Precip         float64  `json:"precip"`             // mm in the last hour
+	UV             int      `json:"uv"`                 // index
+	SolarRadiation float64  `json:"solarradiation"`     // W/m²
+}
+
+// NewObservation returns o as an observation of station, where barometer is the pressure
+// per the barometric measurement of the reports, and rain is the rain of the last hour from
+// the total rain counter.
+func NewObservation(station int, o *model.Observation, barometer unit.Pressure, rain unit.Length) Observation {
+	res := Observation{
+		Station:        station,
+		Time:           o.Timestamp.UTC().Format("2006-01-02T15:04:05Z"),
+		Temp:           round(o.TempOutdoor.Celsius(), 1),
+		Wind:           round(o.WindSpeed.MetersPerSecond(), 1),
+		Gust:           round(o.WindGust.MetersPerSecond(), 1),
+		WindDir:        int(o.WindDir.Degrees()+0.5) % 360,
+		Precip:         round(rain.Millimeters(), 1),
+		UV:             o.UltravioletIndex,
+		SolarRadiation: round(o.SolarRadiation.WattsPerSquareMetre(), 1),
+	}
+	if rh := o.HumidityOutdoor; rh > 0 {
+		dp := round(meteorology.DewPoint(o.TempOutdoor, rh).Celsius(), 1)
+		res.Humidity, res.DewPoint = &rh, &dp
+	}
+	if barometer > 0 {
+		p := math.Round(barometer.Pascals())
+		res.Pressure = &p
+	}
+	return res
+}
+
+func round(v float64, prec int) float64 {
+	p := math.Pow10(prec)
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,43 @@
+package windy
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewObservation(t *testing.T) {
+	o := &model.Observation{
+		Timestamp:        time.Date(2021, 12, 1, 14, 30, 12, 0, time.FixedZone("AEDT", 11*60*60)),
+		HourlyRain:       25.4 * unit.Millimeter,
+		HumidityOutdoor:  82,
+		WindDir:          359.7 * unit.Degree,
+		WindGust:         19.3 * unit.KilometersPerHour,
+		WindSpeed:        8 * unit.KilometersPerHour,
+		SolarRadiation:   450.25 * xunit.WattPerSquareMetre,
+		TempOutdoor:      unit.FromCelsius(14),
+		UltravioletIndex: 3,
+	}
+
+	data, err := json.Marshal(NewObservation(1, o, 1013.2*unit.Hectopascal, 2.54*unit.Millimeter))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"station":1,"time":"2021-12-01T03:30:12Z","temp":14,"wind":2.2,"gust":5.4,"winddir":0,
+		"humidity":82,"dewpoint":10.9,"pressure":101320,"precip":2.5,"uv":3,"solarradiation":450.3}`, string(data))
+
+	var wire map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &wire))
+	assert.Equal(t, "2021-12-01T03:30:12Z", wire["time"], "ISO 8601 in the time field")
+	assert.NotContains(t, wire, "dateutc")
+
+	o.HumidityOutdoor = 0
+	data, err = json.Marshal(NewObservation(0, o, 0, 2.54*unit.Millimeter))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"station":0,"time":"2021-12-01T03:30:12Z","temp":14,"wind":2.2,"gust":5.4,"winddir":0,
+		"precip":2.5,"uv":3,"solarradiation":450.3}`, string(data))
+}
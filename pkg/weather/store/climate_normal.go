@@ -0,0 +1,61 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+type ClimateNormal struct {
+	ID            uint `gorm:"primarykey"`
+	Month         int  `gorm:"uniqueIndex:idx_climate_normals_month_day"`
+	Day           int  `gorm:"uniqueIndex:idx_climate_normals_month_day"`
+	TempHiMeanC   sql.NullFloat64
+	TempLoMeanC   sql.NullFloat64
+	TempMeanC     sql.NullFloat64
+	TempHiRecordC sql.NullFloat64
+	TempLoRecordC sql.NullFloat64
+	RainMm        float64
+}
+
+func (m *ClimateNormal) FromClimateNormal(cn model.ClimateNormal) {
+	temp := func(t unit.Temperature) sql.NullFloat64 {
+		if t == 0 {
+			return sql.NullFloat64{}
+		}
+		return sql.NullFloat64{Float64: t.Celsius(), Valid: true}
+	}
+
+	*m = ClimateNormal{
+		Month:         int(cn.Month),
+		Day:           cn.Day,
+		TempHiMeanC:   temp(cn.TempHiMean),
+		TempLoMeanC:   temp(cn.TempLoMean),
+		TempMeanC:     temp(cn.TempMean),
+		TempHiRecordC: temp(cn.TempHiRecord),
+		TempLoRecordC: temp(cn.TempLoRecord),
+		RainMm:        cn.Rain.Millimeters(),
+	}
+}
+
+func (m ClimateNormal) ToClimateNormal() *model.ClimateNormal {
+	temp := func(v sql.NullFloat64) unit.Temperature {
+		if !v.Valid {
+			return 0
+		}
+		return unit.FromCelsius(v.Float64)
+	}
+
+	return &model.ClimateNormal{
+		Month:        time.Month(m.Month),
+		Day:          m.Day,
+		TempHiMean:   temp(m.TempHiMeanC),
+		TempLoMean:   temp(m.TempLoMeanC),
+		TempMean:     temp(m.TempMeanC),
+		TempHiRecord: temp(m.TempHiRecordC),
+		TempLoRecord: temp(m.TempLoRecordC),
+		Rain:         unit.Length(m.RainMm) * unit.Millimeter,
+	}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/lmacrc/weather/pkg/xunit"
+	"github.com/martinlindhe/unit"
+)
+
+type DailySummary struct {
+	ID                   uint             `gorm:"primarykey" csv:"id"`
+	Date                 sqlite.Date      `gorm:"uniqueIndex" csv:"date"`
+	TempHiC              float64          `csv:"temp_hi_c"`
+	TempHiTime           sqlite.Timestamp `csv:"temp_hi_time"`
+	TempLoC              float64          `csv:"temp_lo_c"`
+	TempLoTime           sqlite.Timestamp `csv:"temp_lo_time"`
+	TempAvgC             float64          `csv:"temp_avg_c"`
+	PressureHiHpa        float64          `csv:"pressure_hi_hpa"`
+	PressureHiTime       sqlite.Timestamp `csv:"pressure_hi_time"`
+	PressureLoHpa        float64          `csv:"pressure_lo_hpa"`
+	PressureLoTime       sqlite.Timestamp `csv:"pressure_lo_time"`
+	RainMm               float64          `csv:"rain_mm"`
+	RainRateHiMm         float64          `csv:"rain_rate_hi_mm"`
+	RainRateHiTime       sqlite.Timestamp `csv:"rain_rate_hi_time"`
+	WindGustHiKph        float64          `csv:"wind_gust_hi_kph"`
+	WindGustHiTime       sqlite.Timestamp `csv:"wind_gust_hi_time"`
+	WindGustHiDirDeg     float64          `csv:"wind_gust_hi_dir_deg"`
+	WindSpeedHiKph       float64          `csv:"wind_speed_hi_kph"`
+	WindSpeedHiTime      sqlite.Timestamp `csv:"wind_speed_hi_time"`
+	WindRunKm            float64          `csv:"wind_run_km"`
+	HumidityHiPct        float64          `csv:"humidity_hi_pct"`
+	HumidityLoPct        float64          `csv:"humidity_lo_pct"`
+	EvapotranspirationMm float64          `csv:"evapotranspiration_mm"`
+	SunshineHours        float64          `csv:"sunshine_hours"`
+	DominantWindDirDeg   float64          `csv:"dominant_wind_dir_deg"`
+	HeatingDegreeDays    float64          `csv:"heating_degree_days"`
+	CoolingDegreeDays    float64          `csv:"cooling_degree_days"`
+	GrowingDegreeDays    float64          `csv:"growing_degree_days"`
+	SolarRadiationHiWm2  float64          `csv:"solar_radiation_hi_wm_2"`
+	UltravioletIndexHi   int              `csv:"ultraviolet_index_hi"`
+}
+
+func (m *DailySummary) FromDailySummary(ds model.DailySummary) {
+	*m = DailySummary{
+		Date:                 sqlite.DateFromTime(ds.Date),
+		TempHiC:              ds.TempHi.Celsius(),
+		TempHiTime:           sqlite.FromTime(ds.TempHiTime),
+		TempLoC:              ds.TempLo.Celsius(),
+		TempLoTime:           sqlite.FromTime(ds.TempLoTime),
+		TempAvgC:             ds.TempAvg.Celsius(),
+		PressureHiHpa:        ds.PressureHi.Hectopascals(),
+		PressureHiTime:       sqlite.FromTime(ds.PressureHiTime),
+		PressureLoHpa:        ds.PressureLo.Hectopascals(),
+		PressureLoTime:       sqlite.FromTime(ds.PressureLoTime),
+		RainMm:               ds.Rain.Millimeters(),
+		RainRateHiMm:         ds.RainRateHi.Millimeters(),
+		RainRateHiTime:       sqlite.FromTime(ds.RainRateHiTime),
+		WindGustHiKph:        ds.WindGustHi.KilometersPerHour(),
+		WindGustHiTime:       sqlite.FromTime(ds.WindGustHiTime),
+		WindGustHiDirDeg:     ds.WindGustHiDir.Degrees(),
+		WindSpeedHiKph:       ds.WindSpeedHi.KilometersPerHour(),
+		WindSpeedHiTime:      sqlite.FromTime(ds.WindSpeedHiTime),
+		WindRunKm:            ds.WindRun.Kilometers(),
+		HumidityHiPct:        float64(ds.HumidityHi) / 100.0,
+		HumidityLoPct:        float64(ds.HumidityLo) / 100.0,
+		EvapotranspirationMm: ds.Evapotranspiration.Millimeters(),
+		SunshineHours:        ds.SunshineHours.Hours(),
+		DominantWindDirDeg:   ds.DominantWindDir.Degrees(),
+		HeatingDegreeDays:    ds.HeatingDegreeDays,
+		CoolingDegreeDays:    ds.CoolingDegreeDays,
+		GrowingDegreeDays:    ds.GrowingDegreeDays,
+		SolarRadiationHiWm2:  ds.SolarRadiationHi.WattsPerSquareMetre(),
+		UltravioletIndexHi:   ds.UltravioletIndexHi,
+	}
+}
+
+// ToDailySummary returns the summary, whose date is midnight in loc.
+func (m DailySummary) ToDailySummary(loc *time.Location) *model.DailySummary {
+	return &model.DailySummary{
+		Date:               m.Date.In(loc),
+		TempHi:             unit.FromCelsius(m.TempHiC),
+		TempHiTime:         m.TempHiTime.Time,
+		TempLo:             unit.FromCelsius(m.TempLoC),
+		TempLoTime:         m.TempLoTime.Time,
+		TempAvg:            unit.FromCelsius(m.TempAvgC),
+		PressureHi:         unit.Pressure(m.PressureHiHpa) * unit.Hectopascal,
+		PressureHiTime:     m.PressureHiTime.Time,
+		PressureLo:         unit.Pressure(m.PressureLoHpa) * unit.Hectopascal,
+		PressureLoTime:     m.PressureLoTime.Time,
+		Rain:               unit.Length(m.RainMm) * unit.Millimeter,
+		RainRateHi:         unit.Length(m.RainRateHiMm) * unit.Millimeter,
+		RainRateHiTime:     m.RainRateHiTime.Time,
+		WindGustHi:         unit.Speed(m.WindGustHiKph) * unit.KilometersPerHour,
+		WindGustHiTime:     m.WindGustHiTime.Time,
+		WindGustHiDir:      unit.Angle(m.WindGustHiDirDeg) * unit.Degree,
+		WindSpeedHi:        unit.Speed(m.WindSpeedHiKph) * unit.KilometersPerHour,
+		WindSpeedHiTime:    m.WindSpeedHiTime.Time,
+		WindRun:            unit.Length(m.WindRunKm) * unit.Kilometer,
+		HumidityHi:         int(m.HumidityHiPct * 100),
+		HumidityLo:         int(m.HumidityLoPct * 100),
+		Evapotranspiration: unit.Length(m.EvapotranspirationMm) * unit.Millimeter,
+		SunshineHours:      time.Duration(m.SunshineHours * float64(time.Hour)),
+		DominantWindDir:    unit.Angle(m.DominantWindDirDeg) * unit.Degree,
+		HeatingDegreeDays:  m.HeatingDegreeDays,
+		CoolingDegreeDays:  m.CoolingDegreeDays,
+		GrowingDegreeDays:  m.GrowingDegreeDays,
+		SolarRadiationHi:   xunit.Irradiance(m.SolarRadiationHiWm2) * xunit.WattPerSquareMetre,
+		UltravioletIndexHi: m.UltravioletIndexHi,
+	}
+}
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"math"
+
 	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
 	"github.com/lmacrc/weather/pkg/weather/model"
 	"github.com/lmacrc/weather/pkg/xunit"
@@ -34,7 +36,7 @@ type Observation struct {
 func (m *Observation) FromObservation(wo model.Observation) {
 	*m = Observation{
 		ID:                 wo.ID,
-		Timestamp:          sqlite.Timestamp{wo.Timestamp},
+		Timestamp:          sqlite.FromTime(wo.Timestamp),
 		BarometricAbsHpa:   wo.BarometricAbs.Hectopascals(),
 		BarometricRelHpa:   wo.BarometricRel.Hectopascals(),
 		HourlyRainMm:       wo.HourlyRain.Millimeters(),
@@ -70,8 +72,8 @@ func (m Observation) ToObservation() *model.Observation {
 		TotalRain:        unit.Length(m.TotalRainMm) * unit.Millimeter,
 		EventRain:        unit.Length(m.EventRainMm) * unit.Millimeter,
 		RainRatePerHour:  unit.Length(m.RainRatePerHourMm) * unit.Millimeter,
-		HumidityOutdoor:  int(m.HumidityOutdoorPct * 100),
-		HumidityIndoor:   int(m.HumidityIndoorPct * 100),
+		HumidityOutdoor:  int(math.Round(m.HumidityOutdoorPct * 100)),
+		HumidityIndoor:   int(math.Round(m.HumidityIndoorPct * 100)),
 		WindDir:          unit.Angle(m.WindDirDeg) * unit.Degree,
 		WindGust:         unit.Speed(m.WindGustKph) * unit.KilometersPerHour,
 		WindSpeed:        unit.Speed(m.WindSpeedKph) * unit.KilometersPerHour,
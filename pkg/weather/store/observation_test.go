@@ -0,0 +1,18 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObservation_ToObservation(t *testing.T) {
+	for h := 0; h <= 100; h++ {
+		var m Observation
+		m.FromObservation(model.Observation{HumidityOutdoor: h, HumidityIndoor: h})
+		o := m.ToObservation()
+		assert.Equal(t, h, o.HumidityOutdoor)
+		assert.Equal(t, h, o.HumidityIndoor)
+	}
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"time"
+
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+)
+
+type RainEvent struct {
+	ID             uint             `gorm:"primarykey"`
+	StartTime      sqlite.Timestamp `gorm:"index"`
+	EndTime        sqlite.Timestamp
+	DurationS      int64
+	RainMm         float64
+	RainRateHiMm   float64
+	RainRateHiTime sqlite.Timestamp
+	InProgress     bool
+}
+
+func (m *RainEvent) FromRainEvent(ev model.RainEvent) {
+	*m = RainEvent{
+		ID:             ev.ID,
+		StartTime:      sqlite.FromTime(ev.Start),
+		EndTime:        sqlite.FromTime(ev.End),
+		DurationS:      int64(ev.Duration / time.Second),
+		RainMm:         ev.Rain.Millimeters(),
+		RainRateHiMm:   ev.RainRateHi.Millimeters(),
+		RainRateHiTime: sqlite.FromTime(ev.RainRateHiTime),
+		InProgress:     ev.InProgress,
+	}
+}
+
+func (m RainEvent) ToRainEvent() *model.RainEvent {
+	return &model.RainEvent{
+		ID:             m.ID,
+		Start:          m.StartTime.Time,
+		End:            m.EndTime.Time,
+		Duration:       time.Duration(m.DurationS) * time.Second,
+		Rain:           unit.Length(m.RainMm) * unit.Millimeter,
+		RainRateHi:     unit.Length(m.RainRateHiMm) * unit.Millimeter,
+		RainRateHiTime: m.RainRateHiTime.Time,
+		InProgress:     m.InProgress,
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/sql/driver/sqlite"
 	"github.com/lmacrc/weather/pkg/weather/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -17,15 +19,35 @@ var (
 type Store struct {
 	db  *gorm.DB
 	bus *event.Bus
+	loc *time.Location
 }
 
-func New(db *gorm.DB, bus *event.Bus) (*Store, error) {
-	err := db.AutoMigrate(Observation{})
+type optionFn func(s *Store)
+
+// WithLocation specifies the time zone of the station, which determines
+// the dates of the daily summaries. The default is the time zone of the host.
+func WithLocation(loc *time.Location) optionFn {
+	if loc == nil {
+		panic("loc == nil")
+	}
+
+	return func(s *Store) {
+		s.loc = loc
+	}
+}
+
+func New(db *gorm.DB, bus *event.Bus, opts ...optionFn) (*Store, error) {
+	err := db.AutoMigrate(Observation{}, DailySummary{}, ClimateNormal{}, RainEvent{})
 	if err != nil {
 		return nil, fmt.Errorf("db migrate: %w", err)
 	}
 
-	return &Store{db: db, bus: bus}, nil
+	s := &Store{db: db, bus: bus, loc: time.Local}
+	for _, fn := range opts {
+		fn(s)
+	}
+
+	return s, nil
 }
 
 func (s *Store) DB() *gorm.DB { return s.db }
@@ -43,6 +65,57 @@ func (s *Store) WriteObservation(o model.Observation) (*model.Observation, error
 	return res, tx.Error
 }
 
+// WriteObservations writes obs in batches, without publishing NewObservation events, returning
+// the number written. It is intended for bulk loading historical data, so an observation with the
+// timestamp of an existing observation, or of an earlier one of obs, is skipped, and importing the
+// same data again does not duplicate it.
+func (s *Store) WriteObservations(obs []model.Observation) (int, error) {
+	if len(obs) == 0 {
+		return 0, nil
+	}
+
+	start, end := obs[0].Timestamp, obs[0].Timestamp
+	for _, o := range obs[1:] {
+		if o.Timestamp.Before(start) {
+			start = o.Timestamp
+		}
+		if o.Timestamp.After(end) {
+			end = o.Timestamp
+		}
+	}
+
+	var existing []sqlite.Timestamp
+	err := s.db.Model(&Observation{}).
+		Where("timestamp BETWEEN ? AND ?", sqlite.FromTime(start), sqlite.FromTime(end)).
+		Pluck("timestamp", &existing).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// timestamps are stored to the second
+	seen := make(map[int64]bool, len(existing)+len(obs))
+	for _, ts := range existing {
+		seen[ts.Unix()] = true
+	}
+
+	rows := make([]Observation, 0, len(obs))
+	for i := range obs {
+		key := obs[i].Timestamp.Unix()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var row Observation
+		row.FromObservation(obs[i])
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return len(rows), s.db.CreateInBatches(rows, 500).Error
+}
+
 func (s *Store) LastObservation(now time.Time) *model.Observation {
 	now = now.UTC()
 
@@ -53,3 +126,99 @@ func (s *Store) LastObservation(now time.Time) *model.Observation {
 	}
 	return res.ToObservation()
 }
+
+// WriteDailySummary writes ds, replacing any existing summary for the same date.
+func (s *Store) WriteDailySummary(ds model.DailySummary) error {
+	var mds DailySummary
+	mds.FromDailySummary(ds)
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}},
+		UpdateAll: true,
+	}).Create(&mds).Error
+}
+
+// DailySummaries returns the summaries for the dates from start up to, but excluding, end.
+func (s *Store) DailySummaries(start, end time.Time) ([]*model.DailySummary, error) {
+	var rows []DailySummary
+	tx := s.db.Where("date >= ? AND date < ?", sqlite.DateFromTime(start), sqlite.DateFromTime(end)).
+		Order("date").
+		Find(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := make([]*model.DailySummary, 0, len(rows))
+	for i := range rows {
+		res = append(res, rows[i].ToDailySummary(s.loc))
+	}
+	return res, nil
+}
+
+// WriteClimateNormals writes normals, replacing any existing normal for the same month and day.
+func (s *Store) WriteClimateNormals(normals []model.ClimateNormal) error {
+	if len(normals) == 0 {
+		return nil
+	}
+
+	rows := make([]ClimateNormal, len(normals))
+	for i := range normals {
+		rows[i].FromClimateNormal(normals[i])
+	}
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "month"}, {Name: "day"}},
+		UpdateAll: true,
+	}).Create(&rows).Error
+}
+
+// ClimateNormal returns the normal for the day of the month, or for the whole month when day is zero,
+// and nil when there is no such normal.
+func (s *Store) ClimateNormal(month time.Month, day int) (*model.ClimateNormal, error) {
+	var rows []ClimateNormal
+	tx := s.db.Where("month = ? AND day = ?", int(month), day).Limit(1).Find(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[0].ToClimateNormal(), nil
+}
+
+// WriteRainEvent writes ev, replacing the existing event with the same ID,
+// and assigns the ID of ev when it is a new event.
+func (s *Store) WriteRainEvent(ev *model.RainEvent) error {
+	var m RainEvent
+	m.FromRainEvent(*ev)
+	if err := s.db.Save(&m).Error; err != nil {
+		return err
+	}
+	ev.ID = m.ID
+	return nil
+}
+
+// RainEvents returns the events which overlap the period from start up to, but excluding, end, in order.
+func (s *Store) RainEvents(start, end time.Time) ([]*model.RainEvent, error) {
+	var rows []RainEvent
+	tx := s.db.Where("start_time < ? AND end_time >= ?", sqlite.FromTime(end), sqlite.FromTime(start)).
+		Order("start_time").
+		Find(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := make([]*model.RainEvent, 0, len(rows))
+	for i := range rows {
+		res = append(res, rows[i].ToRainEvent())
+	}
+	return res, nil
+}
+
+// LastRainEvent returns the most recent event, or nil when there are none.
+func (s *Store) LastRainEvent() (*model.RainEvent, error) {
+	var rows []RainEvent
+	tx := s.db.Order("start_time DESC").Limit(1).Find(&rows)
+	if tx.Error != nil || len(rows) == 0 {
+		return nil, tx.Error
+	}
+	return rows[0].ToRainEvent(), nil
+}
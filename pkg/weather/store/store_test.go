@@ -0,0 +1,47 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/event"
+	"github.com/lmacrc/weather/pkg/weather/model"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestStore_WriteObservations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "weather.db")), &gorm.Config{})
+	require.NoError(t, err)
+	st, err := New(db, event.New())
+	require.NoError(t, err)
+
+	ts := time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC)
+	obs := func(minutes ...int) []model.Observation {
+		var res []model.Observation
+		for _, m := range minutes {
+			res = append(res, model.Observation{Timestamp: ts.Add(time.Duration(m) * time.Minute), TempOutdoor: unit.FromCelsius(10)})
+		}
+		return res
+	}
+
+	n, err := st.WriteObservations(obs(0, 5, 10, 5))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n, "duplicate of the batch skipped")
+
+	n, err = st.WriteObservations(obs(10, 15, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "existing observations skipped")
+
+	var count int64
+	require.NoError(t, db.Model(&Observation{}).Count(&count).Error)
+	assert.EqualValues(t, 4, count)
+
+	n, err = st.WriteObservations(nil)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
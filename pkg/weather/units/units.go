@@ -0,0 +1,272 @@
+// Package units provides named measurement units, for reading and writing
+// weather data in units other than those used internally.
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/martinlindhe/unit"
+)
+
+type Temperature string
+
+const (
+	Celsius    Temperature = "C"
+	Fahrenheit Temperature = "F"
+)
+
+func ParseTemperature(s string) (Temperature, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, "°")) {
+	case "c", "celsius":
+		return Celsius, nil
+	case "f", "fahrenheit":
+		return Fahrenheit, nil
+	default:
+		return "", fmt.Errorf("invalid temperature unit %q: expect C,F", s)
+	}
+}
+
+func (u *Temperature) UnmarshalText(text []byte) (err error) {
+	*u, err = ParseTemperature(string(text))
+	return err
+}
+
+// From returns v, measured in units u, as a unit.Temperature.
+func (u Temperature) From(v float64) unit.Temperature {
+	if u == Fahrenheit {
+		return unit.FromFahrenheit(v)
+	}
+	return unit.FromCelsius(v)
+}
+
+// To returns t in units u.
+func (u Temperature) To(t unit.Temperature) float64 {
+	if u == Fahrenheit {
+		return t.Fahrenheit()
+	}
+	return t.Celsius()
+}
+
+// Delta returns the temperature difference d in units u.
+func (u Temperature) Delta(d unit.Temperature) float64 {
+	if u == Fahrenheit {
+		return float64(d) * 9 / 5
+	}
+	return float64(d)
+}
+
+type Speed string
+
+const (
+	MetersPerSecond   Speed = "m/s"
+	KilometersPerHour Speed = "km/h"
+	MilesPerHour      Speed = "mph"
+	Knots             Speed = "kts"
+)
+
+func ParseSpeed(s string) (Speed, error) {
+	switch strings.ToLower(s) {
+	case "m/s", "ms", "mps":
+		return MetersPerSecond, nil
+	case "km/h", "kmh", "kph":
+		return KilometersPerHour, nil
+	case "mph":
+		return MilesPerHour, nil
+	case "kts", "kt", "knots":
+		return Knots, nil
+	default:
+		return "", fmt.Errorf("invalid speed unit %q: expect m/s,km/h,mph,kts", s)
+	}
+}
+
+func (u *Speed) UnmarshalText(text []byte) (err error) {
+	*u, err = ParseSpeed(string(text))
+	return err
+}
+
+func (u Speed) base() unit.Speed {
+	switch u {
+	case MetersPerSecond:
+		return unit.MetersPerSecond
+	case MilesPerHour:
+		return unit.MilesPerHour
+	case Knots:
+		return unit.Knot
+	default:
+		return unit.KilometersPerHour
+	}
+}
+
+// From returns v, measured in units u, as a unit.Speed.
+func (u Speed) From(v float64) unit.Speed { return unit.Speed(v) * u.base() }
+
+// To returns s in units u.
+func (u Speed) To(s unit.Speed) float64 { return float64(s / u.base()) }
+
+// Distance returns the unit of distance travelled in one hour at speed u,
+// which is used to report wind run.
+func (u Speed) Distance() Distance {
+	switch u {
+	case MilesPerHour:
+		return Miles
+	case Knots:
+		return NauticalMiles
+	default:
+		return Kilometers
+	}
+}
+
+type Pressure string
+
+const (
+	Hectopascal   Pressure = "hPa"
+	Millibar      Pressure = "mb"
+	InchOfMercury Pressure = "inHg"
+	Kilopascal    Pressure = "kPa"
+)
+
+func ParsePressure(s string) (Pressure, error) {
+	switch strings.ToLower(s) {
+	case "hpa":
+		return Hectopascal, nil
+	case "mb", "mbar", "millibar":
+		return Millibar, nil
+	case "in", "inhg":
+		return InchOfMercury, nil
+	case "kpa":
+		return Kilopascal, nil
+	default:
+		return "", fmt.Errorf("invalid pressure unit %q: expect hPa,mb,inHg,kPa", s)
+	}
+}
+
+func (u *Pressure) UnmarshalText(text []byte) (err error) {
+	*u, err = ParsePressure(string(text))
+	return err
+}
+
+func (u Pressure) base() unit.Pressure {
+	switch u {
+	case Millibar:
+		return unit.Millibar
+	case InchOfMercury:
+		return unit.InchOfMercury
+	case Kilopascal:
+		return unit.Kilopascal
+	default:
+		return unit.Hectopascal
+	}
+}
+
+// From returns v, measured in units u, as a unit.Pressure.
+func (u Pressure) From(v float64) unit.Pressure { return unit.Pressure(v) * u.base() }
+
+// To returns p in units u.
+func (u Pressure) To(p unit.Pressure) float64 { return float64(p / u.base()) }
+
+// Label returns the label for u used by Cumulus, which abbreviates inches of mercury to "in".
+func (u Pressure) Label() string {
+	if u == InchOfMercury {
+		return "in"
+	}
+	return string(u)
+}
+
+// Rain is a unit for measuring precipitation.
+type Rain string
+
+const (
+	Millimeters Rain = "mm"
+	Centimeters Rain = "cm"
+	Inches      Rain = "in"
+)
+
+func ParseRain(s string) (Rain, error) {
+	switch strings.ToLower(s) {
+	case "mm":
+		return Millimeters, nil
+	case "cm":
+		return Centimeters, nil
+	case "in", "inch", "inches":
+		return Inches, nil
+	default:
+		return "", fmt.Errorf("invalid rain unit %q: expect mm,cm,in", s)
+	}
+}
+
+func (u *Rain) UnmarshalText(text []byte) (err error) {
+	*u, err = ParseRain(string(text))
+	return err
+}
+
+func (u Rain) base() unit.Length {
+	switch u {
+	case Centimeters:
+		return unit.Centimeter
+	case Inches:
+		return unit.Inch
+	default:
+		return unit.Millimeter
+	}
+}
+
+// From returns v, measured in units u, as a unit.Length.
+func (u Rain) From(v float64) unit.Length { return unit.Length(v) * u.base() }
+
+// To returns l in units u.
+func (u Rain) To(l unit.Length) float64 { return float64(l / u.base()) }
+
+// Distance is a unit for measuring wind run.
+type Distance string
+
+const (
+	Kilometers    Distance = "km"
+	Miles         Distance = "miles"
+	NauticalMiles Distance = "nm"
+)
+
+func (u Distance) base() unit.Length {
+	switch u {
+	case Miles:
+		return unit.Mile
+	case NauticalMiles:
+		return unit.NauticalMile
+	default:
+		return unit.Kilometer
+	}
+}
+
+// From returns v, measured in units u, as a unit.Length.
+func (u Distance) From(v float64) unit.Length { return unit.Length(v) * u.base() }
+
+// To returns l in units u.
+func (u Distance) To(l unit.Length) float64 { return float64(l / u.base()) }
+
+// System is a set of units for each measured quantity.
+type System struct {
+	Temperature Temperature
+	Speed       Speed
+	Pressure    Pressure
+	Rain        Rain
+}
+
+var (
+	Metric   = System{Temperature: Celsius, Speed: KilometersPerHour, Pressure: Hectopascal, Rain: Millimeters}
+	MetricWX = System{Temperature: Celsius, Speed: MetersPerSecond, Pressure: Hectopascal, Rain: Millimeters}
+	US       = System{Temperature: Fahrenheit, Speed: MilesPerHour, Pressure: InchOfMercury, Rain: Inches}
+)
+
+// ParseSystem returns the named unit system; one of metric, metricwx or us.
+func ParseSystem(s string) (System, error) {
+	switch strings.ToLower(s) {
+	case "metric":
+		return Metric, nil
+	case "metricwx":
+		return MetricWX, nil
+	case "us":
+		return US, nil
+	default:
+		return System{}, fmt.Errorf("invalid unit system %q: expect metric,metricwx,us", s)
+	}
+}
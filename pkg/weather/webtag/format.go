@@ -0,0 +1,97 @@
+package webtag
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/xmath"
+)
+
+// formatTime formats t using the custom date and time format strings of .NET, as used by
+// the format parameter of Cumulus, such as "dd/MM/yyyy HH:mm".
+// See https://learn.microsoft.com/dotnet/standard/base-types/custom-date-and-time-format-strings
+func formatTime(t time.Time, format string) string {
+	var b strings.Builder
+	pad := func(v, width int) {
+		s := strconv.Itoa(v)
+		for i := len(s); i < width; i++ {
+			b.WriteByte('0')
+		}
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(format); {
+		c := format[i]
+		n := 1
+		for i+n < len(format) && format[i+n] == c {
+			n++
+		}
+
+		switch c {
+		case 'y':
+			if n <= 2 {
+				pad(t.Year()%100, n)
+			} else {
+				pad(t.Year(), n)
+			}
+		case 'M':
+			switch n {
+			case 1, 2:
+				pad(int(t.Month()), n)
+			case 3:
+				b.WriteString(t.Month().String()[:3])
+			default:
+				b.WriteString(t.Month().String())
+			}
+		case 'd':
+			switch n {
+			case 1, 2:
+				pad(t.Day(), n)
+			case 3:
+				b.WriteString(t.Weekday().String()[:3])
+			default:
+				b.WriteString(t.Weekday().String())
+			}
+		case 'H':
+			pad(t.Hour(), xmath.Min(n, 2))
+		case 'h':
+			h := t.Hour() % 12
+			if h == 0 {
+				h = 12
+			}
+			pad(h, xmath.Min(n, 2))
+		case 'm':
+			pad(t.Minute(), xmath.Min(n, 2))
+		case 's':
+			pad(t.Second(), xmath.Min(n, 2))
+		case 't':
+			ampm := "AM"
+			if t.Hour() >= 12 {
+				ampm = "PM"
+			}
+			if n == 1 {
+				ampm = ampm[:1]
+			}
+			b.WriteString(ampm)
+		case '"', '\'':
+			// a quoted literal
+			end := strings.IndexByte(format[i+1:], c)
+			if end < 0 {
+				b.WriteString(format[i+1:])
+				return b.String()
+			}
+			b.WriteString(format[i+1 : i+1+end])
+			n = end + 2
+		case '\\':
+			if i+1 < len(format) {
+				b.WriteByte(format[i+1])
+			}
+			n = 2
+		default:
+			b.WriteString(format[i : i+n])
+		}
+		i += n
+	}
+	return b.String()
+}
@@ -0,0 +1,322 @@
+package webtag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/martinlindhe/unit"
+)
+
+// Station is the name and location of the station.
+type Station struct {
+	Name                          string
+	Latitude, Longitude, Altitude float64
+}
+
+// Data contains the statistics, history and records of the web tags. The values are
+// converted to Units, and the times are in the time zone of the statistics.
+type Data struct {
+	Stats   *reporting.Statistics
+	Units   units.System
+	Station Station
+	Today   reporting.Sample // Today contains the statistics of the current day so far
+
+	// TempHourAgo is the mean temperature of the 10 minutes about an hour ago, or 0 K when not available.
+	TempHourAgo unit.Temperature
+
+	Month   *reporting.Records
+	Year    *reporting.Records
+	AllTime *reporting.Records
+}
+
+// Tag returns the value of the tag with the parameters, each of the form key=value, for Go templates.
+//
+//	{{ .Tag "temp" "dp=2" }}
+func (d *Data) Tag(name string, params ...string) (string, error) {
+	p := Params{}
+	for _, kv := range params {
+		i := strings.IndexByte(kv, '=')
+		if i < 0 {
+			return "", fmt.Errorf("tag %s: invalid parameter %q: expect key=value", name, kv)
+		}
+		p[kv[:i]] = strings.Trim(kv[i+1:], `"`)
+	}
+
+	v, ok := d.tag(name, p)
+	if !ok {
+		return "", fmt.Errorf("unknown tag %s", name)
+	}
+	return v, nil
+}
+
+// number is a value of a tag, written with prec decimal places by default.
+type number struct {
+	v    float64
+	prec int
+}
+
+// clock is a time of a tag, written using the format by default.
+type clock struct {
+	t      time.Time
+	format string
+}
+
+const (
+	timeFormat     = "HH:mm"
+	dateFormat     = "dd/MM/yyyy"
+	dateTimeFormat = "HH:mm 'on' dd MMMM yyyy"
+)
+
+// tag returns the value of the tag, formatted per the parameters:
+//
+//	format  the .NET custom format of a time, such as "dd/MM/yyyy"
+//	dp      the number of decimal places of a number
+//	tc      y to truncate a number to an integer
+//	rc      y to use a decimal point, rather than a comma; numbers always use a decimal point
+func (d *Data) tag(name string, p Params) (string, bool) {
+	fn, ok := tags[name]
+	if !ok {
+		return "", false
+	}
+
+	switch v := fn(d).(type) {
+	case number:
+		if math.IsNaN(v.v) || math.IsInf(v.v, 0) {
+			return "-", true
+		}
+		prec := v.prec
+		if dp, err := strconv.Atoi(p["dp"]); err == nil && dp >= 0 {
+			prec = dp
+		}
+		if p["tc"] == "y" {
+			return strconv.FormatFloat(math.Trunc(v.v), 'f', 0, 64), true
+		}
+		return strconv.FormatFloat(v.v, 'f', prec, 64), true
+	case clock:
+		if v.t.IsZero() {
+			return "----", true
+		}
+		format := v.format
+		if f, ok := p["format"]; ok {
+			format = f
+		}
+		return formatTime(v.t, format), true
+	case int:
+		return strconv.Itoa(v), true
+	case string:
+		return v, true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+func (d *Data) temp(t unit.Temperature) number {
+	if t == 0 {
+		return number{math.NaN(), 1}
+	}
+	return number{d.Units.Temperature.To(t), 1}
+}
+
+// tempChange returns the temperature change t, which is expressed as an offset from 0°C.
+func (d *Data) tempChange(t unit.Temperature) number {
+	return number{d.Units.Temperature.Delta(unit.Temperature(t.Celsius())), 1}
+}
+
+func (d *Data) pressure(p unit.Pressure) number {
+	prec := 1
+	if d.Units.Pressure == units.InchOfMercury {
+		prec = 2
+	}
+	return number{d.Units.Pressure.To(p), prec}
+}
+
+func (d *Data) speed(s unit.Speed) number {
+	return number{d.Units.Speed.To(s), 1}
+}
+
+func (d *Data) rain(l unit.Length) number {
+	prec := 1
+	if d.Units.Rain == units.Inches {
+		prec = 2
+	}
+	return number{d.Units.Rain.To(l), prec}
+}
+
+func (d *Data) records(period string) *reporting.Records {
+	var rec *reporting.Records
+	switch period {
+	case "Month":
+		rec = d.Month
+	case "Year":
+		rec = d.Year
+	default:
+		rec = d.AllTime
+	}
+	if rec == nil {
+		return &reporting.Records{}
+	}
+	return rec
+}
+
+func tempTag(fn func(d *Data) unit.Temperature) func(d *Data) interface{} {
+	return func(d *Data) interface{} { return d.temp(fn(d)) }
+}
+
+func timeTag(fn func(d *Data) time.Time) func(d *Data) interface{} {
+	return func(d *Data) interface{} { return clock{fn(d), timeFormat} }
+}
+
+var tags = map[string]func(d *Data) interface{}{
+	// station
+	"location":  func(d *Data) interface{} { return d.Station.Name },
+	"latitude":  func(d *Data) interface{} { return number{d.Station.Latitude, 4} },
+	"longitude": func(d *Data) interface{} { return number{d.Station.Longitude, 4} },
+	"altitude":  func(d *Data) interface{} { return number{d.Station.Altitude, 0} },
+	"version":   func(d *Data) interface{} { return d.Stats.CumulusVersion },
+	"build":     func(d *Data) interface{} { return d.Stats.CumulusBuildNumber },
+
+	// time of the statistics
+	"date":        func(d *Data) interface{} { return clock{d.Stats.Timestamp, dateFormat} },
+	"time":        func(d *Data) interface{} { return clock{d.Stats.Timestamp, timeFormat} },
+	"timehhmmss":  func(d *Data) interface{} { return clock{d.Stats.Timestamp, "HH:mm:ss"} },
+	"DataStopped": func(d *Data) interface{} { return boolTag(d.Stats.SensorContactLost) },
+	"isdaylight":  func(d *Data) interface{} { return boolTag(d.Stats.IsDaylight) },
+	"IsSunny":     func(d *Data) interface{} { return boolTag(d.Stats.IsSunny) },
+
+	// units
+	"tempunit":      func(d *Data) interface{} { return "°" + string(d.Units.Temperature) },
+	"tempunitnodeg": func(d *Data) interface{} { return string(d.Units.Temperature) },
+	"pressunit":     func(d *Data) interface{} { return d.Units.Pressure.Label() },
+	"rainunit":      func(d *Data) interface{} { return string(d.Units.Rain) },
+	"windunit":      func(d *Data) interface{} { return string(d.Units.Speed) },
+	"windrununit":   func(d *Data) interface{} { return string(d.Units.Speed.Distance()) },
+
+	// current conditions
+	"temp":      tempTag(func(d *Data) unit.Temperature { return d.Stats.OutdoorTemperature }),
+	"apptemp":   tempTag(func(d *Data) unit.Temperature { return d.Stats.ApparentTemp }),
+	"feelslike": tempTag(func(d *Data) unit.Temperature { return d.Stats.TempFeelsLike }),
+	"dew":       tempTag(func(d *Data) unit.Temperature { return d.Stats.DewPoint }),
+	"wchill":    tempTag(func(d *Data) unit.Temperature { return d.Stats.WindChill }),
+	"heatindex": tempTag(func(d *Data) unit.Temperature { return d.Stats.HeatIndex }),
+	"humidex":   tempTag(func(d *Data) unit.Temperature { return d.Stats.Humidex }),
+	"intemp":    tempTag(func(d *Data) unit.Temperature { return d.Stats.IndoorTemp }),
+	"hum":       func(d *Data) interface{} { return d.Stats.OutdoorHumidity },
+	"inhum":     func(d *Data) interface{} { return d.Stats.IndoorHumidity },
+	"temptrend": func(d *Data) interface{} { return d.tempChange(d.Stats.TempTrend) },
+	"TempChangeLastHour": func(d *Data) interface{} {
+		if d.TempHourAgo == 0 || d.Stats.OutdoorTemperature == 0 {
+			return number{math.NaN(), 1}
+		}
+		return d.tempChange(unit.FromCelsius(d.Stats.OutdoorTemperature.Celsius() - d.TempHourAgo.Celsius()))
+	},
+
+	"press":         func(d *Data) interface{} { return d.pressure(d.Stats.BarometricPressure) },
+	"presstrendval": func(d *Data) interface{} { return d.pressure(d.Stats.PressureTrend) },
+	"presstrend":    func(d *Data) interface{} { return d.Stats.PressureTendencyText },
+
+	"wlatest":        func(d *Data) interface{} { return d.speed(d.Stats.WindSpeedLast) },
+	"wspeed":         func(d *Data) interface{} { return d.speed(d.Stats.WindSpeedAvg) },
+	"wgust":          func(d *Data) interface{} { return d.speed(d.Stats.TenMinGustHi) },
+	"bearing":        func(d *Data) interface{} { return number{d.Stats.WindBearing.Degrees(), 0} },
+	"avgbearing":     func(d *Data) interface{} { return number{d.Stats.TenMinWindBearingAvg.Degrees(), 0} },
+	"wdir":           func(d *Data) interface{} { return string(d.Stats.WindDirection) },
+	"avgwdir":        func(d *Data) interface{} { return string(d.Stats.WindDirectionAvg) },
+	"beaufort":       func(d *Data) interface{} { return "F" + strconv.Itoa(d.Stats.WindForce) },
+	"beaufortnumber": func(d *Data) interface{} { return d.Stats.WindForce },
+	"windrun":        func(d *Data) interface{} { return number{d.Units.Speed.Distance().To(d.Stats.WindRun), 1} },
+
+	"rfall":   func(d *Data) interface{} { return d.rain(d.Stats.RainfallToday) },
+	"rrate":   func(d *Data) interface{} { return d.rain(d.Stats.RainRate) },
+	"rhour":   func(d *Data) interface{} { return d.rain(d.Stats.RainfallLastHour) },
+	"r24hour": func(d *Data) interface{} { return d.rain(d.Stats.RainfallLast24Hours) },
+	"rmonth":  func(d *Data) interface{} { return d.rain(d.Stats.MonthlyRainfall) },
+	"ryear":   func(d *Data) interface{} { return d.rain(d.Stats.YearlyRainfall) },
+	"rfallY":  func(d *Data) interface{} { return d.rain(d.Stats.YesterdayRainfall) },
+
+	"UV":              func(d *Data) interface{} { return number{float64(d.Stats.UVIndex), 1} },
+	"SolarRad":        func(d *Data) interface{} { return number{d.Stats.SolarRadiation.WattsPerSquareMetre(), 0} },
+	"CurrentSolarMax": func(d *Data) interface{} { return number{d.Stats.CurrentSolarMax.WattsPerSquareMetre(), 0} },
+	"SunshineHours":   func(d *Data) interface{} { return number{d.Stats.SunshineHoursToday.Hours(), 1} },
+	"ET":              func(d *Data) interface{} { return d.rain(d.Stats.Evapotranspiration) },
+
+	"forecast":       func(d *Data) interface{} { return d.Stats.ZambrettiForecast.String() },
+	"forecastnumber": func(d *Data) interface{} { return d.Stats.ZambrettiForecast.ToInt() },
+
+	// today
+	"tempTH":       tempTag(func(d *Data) unit.Temperature { return d.Stats.TodayTempHi }),
+	"TtempTH":      timeTag(func(d *Data) time.Time { return d.Stats.TodayTempHiTime }),
+	"tempTL":       tempTag(func(d *Data) unit.Temperature { return d.Stats.TodayTempLo }),
+	"TtempTL":      timeTag(func(d *Data) time.Time { return d.Stats.TodayTempLoTime }),
+	"apptempTH":    tempTag(func(d *Data) unit.Temperature { return d.Today.ApparentTemp.Hi }),
+	"TapptempTH":   timeTag(func(d *Data) time.Time { return d.Today.ApparentTemp.HiTime }),
+	"apptempTL":    tempTag(func(d *Data) unit.Temperature { return d.Today.ApparentTemp.Lo }),
+	"TapptempTL":   timeTag(func(d *Data) time.Time { return d.Today.ApparentTemp.LoTime }),
+	"dewpointTH":   tempTag(func(d *Data) unit.Temperature { return d.Today.DewPoint.Hi }),
+	"TdewpointTH":  timeTag(func(d *Data) time.Time { return d.Today.DewPoint.HiTime }),
+	"dewpointTL":   tempTag(func(d *Data) unit.Temperature { return d.Today.DewPoint.Lo }),
+	"TdewpointTL":  timeTag(func(d *Data) time.Time { return d.Today.DewPoint.LoTime }),
+	"wchillTL":     tempTag(func(d *Data) unit.Temperature { return d.Today.WindChill.Lo }),
+	"TwchillTL":    timeTag(func(d *Data) time.Time { return d.Today.WindChill.LoTime }),
+	"heatindexTH":  tempTag(func(d *Data) unit.Temperature { return d.Today.HeatIndex.Hi }),
+	"TheatindexTH": timeTag(func(d *Data) time.Time { return d.Today.HeatIndex.HiTime }),
+	"humTH":        func(d *Data) interface{} { return d.Today.HumidityHi },
+	"ThumTH":       timeTag(func(d *Data) time.Time { return d.Today.HumidityHiTime }),
+	"humTL":        func(d *Data) interface{} { return d.Today.HumidityLo },
+	"ThumTL":       timeTag(func(d *Data) time.Time { return d.Today.HumidityLoTime }),
+	"pressTH":      func(d *Data) interface{} { return d.pressure(d.Stats.TodayPressureHi) },
+	"TpressTH":     timeTag(func(d *Data) time.Time { return d.Stats.TodayPressureHiTime }),
+	"pressTL":      func(d *Data) interface{} { return d.pressure(d.Stats.TodayPressureLo) },
+	"TpressTL":     timeTag(func(d *Data) time.Time { return d.Stats.TodayPressureLoTime }),
+	"windTM":       func(d *Data) interface{} { return d.speed(d.Stats.TodayWindHi) },
+	"TwindTM":      timeTag(func(d *Data) time.Time { return d.Stats.TodayWindHiTime }),
+	"wgustTM":      func(d *Data) interface{} { return d.speed(d.Stats.TodayWindGustHi) },
+	"TwgustTM":     timeTag(func(d *Data) time.Time { return d.Stats.TodayWindGustHiTime }),
+	"rrateTM":      func(d *Data) interface{} { return d.rain(d.Today.RainRateHi) },
+	"TrrateTM":     timeTag(func(d *Data) time.Time { return d.Today.RainRateHiTime }),
+	"UVTH":         func(d *Data) interface{} { return number{float64(d.Today.UVIndexHi), 1} },
+	"SolarTM":      func(d *Data) interface{} { return number{d.Today.SolarRadiationHi.WattsPerSquareMetre(), 0} },
+}
+
+func boolTag(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// recordTags are the records of the month, year and all time, named as
+// MonthTempH, YearTempH or TempH, with the time of the record as MonthTempHT,
+// the date as MonthTempHD, and for all time, the time and date as TTempH.
+var recordTags = []struct {
+	name  string
+	value func(d *Data, rec *reporting.Records) number
+	time  func(rec *reporting.Records) time.Time
+}{
+	{"TempH", func(d *Data, rec *reporting.Records) number { return d.temp(rec.TempHi) }, func(rec *reporting.Records) time.Time { return rec.TempHiTime }},
+	{"TempL", func(d *Data, rec *reporting.Records) number { return d.temp(rec.TempLo) }, func(rec *reporting.Records) time.Time { return rec.TempLoTime }},
+	{"PressH", func(d *Data, rec *reporting.Records) number { return d.pressure(rec.PressureHi) }, func(rec *reporting.Records) time.Time { return rec.PressureHiTime }},
+	{"PressL", func(d *Data, rec *reporting.Records) number { return d.pressure(rec.PressureLo) }, func(rec *reporting.Records) time.Time { return rec.PressureLoTime }},
+	{"WindH", func(d *Data, rec *reporting.Records) number { return d.speed(rec.WindSpeedHi) }, func(rec *reporting.Records) time.Time { return rec.WindSpeedHiTime }},
+	{"GustH", func(d *Data, rec *reporting.Records) number { return d.speed(rec.WindGustHi) }, func(rec *reporting.Records) time.Time { return rec.WindGustHiTime }},
+	{"RainRateH", func(d *Data, rec *reporting.Records) number { return d.rain(rec.RainRateHi) }, func(rec *reporting.Records) time.Time { return rec.RainRateHiTime }},
+	{"DailyRainH", func(d *Data, rec *reporting.Records) number { return d.rain(rec.RainHi) }, func(rec *reporting.Records) time.Time { return rec.RainHiDate }},
+}
+
+func init() {
+	for _, r := range recordTags {
+		r := r
+		for _, period := range []string{"Month", "Year"} {
+			period := period
+			tags[period+r.name] = func(d *Data) interface{} { return r.value(d, d.records(period)) }
+			tags[period+r.name+"T"] = func(d *Data) interface{} { return clock{r.time(d.records(period)), timeFormat} }
+			tags[period+r.name+"D"] = func(d *Data) interface{} { return clock{r.time(d.records(period)), dateFormat} }
+		}
+		tags[r.name] = func(d *Data) interface{} { return r.value(d, d.records("")) }
+		tags["T"+r.name] = func(d *Data) interface{} { return clock{r.time(d.records("")), dateTimeFormat} }
+	}
+}
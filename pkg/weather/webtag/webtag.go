@@ -0,0 +1,137 @@
+// Package webtag renders templates containing Cumulus web tags, such as <#temp> or
+// <#time format="dd/MM/yyyy HH:mm">, with the statistics of the station.
+package webtag
+
+import (
+	"io"
+	"strings"
+)
+
+// https://cumuluswiki.org/a/Webtags
+
+// Params are the parameters of a web tag, such as format or dp.
+type Params map[string]string
+
+// Template is a parsed template, of text and web tags.
+type Template struct {
+	nodes []node
+}
+
+type node struct {
+	text   string // text is the literal text, or the source of the tag
+	tag    string // tag is the name of the tag, or empty for literal text
+	params Params
+}
+
+// Parse parses text for web tags, of the form <#name> or <#name param=value param="value">.
+// Text which is not a well-formed tag is literal.
+func Parse(text string) *Template {
+	t := &Template{}
+	for {
+		i := strings.Index(text, "<#")
+		if i < 0 {
+			break
+		}
+		t.text(text[:i])
+		text = text[i:]
+
+		n, length, ok := parseTag(text)
+		if !ok {
+			t.text(text[:2])
+			text = text[2:]
+			continue
+		}
+		t.nodes = append(t.nodes, n)
+		text = text[length:]
+	}
+	t.text(text)
+	return t
+}
+
+func (t *Template) text(s string) {
+	if s == "" {
+		return
+	}
+	if n := len(t.nodes); n > 0 && t.nodes[n-1].tag == "" {
+		t.nodes[n-1].text += s
+		return
+	}
+	t.nodes = append(t.nodes, node{text: s})
+}
+
+// parseTag parses the tag at the start of s, returning the length of its source.
+func parseTag(s string) (n node, length int, ok bool) {
+	i := 2
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+	if i == 2 {
+		return n, 0, false
+	}
+	n.tag = s[2:i]
+	n.params = Params{}
+
+	for {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i == len(s) {
+			return n, 0, false
+		}
+		if s[i] == '>' {
+			n.text = s[:i+1]
+			return n, i + 1, true
+		}
+
+		start := i
+		for i < len(s) && isNameChar(s[i]) {
+			i++
+		}
+		if i == start || i == len(s) || s[i] != '=' {
+			return n, 0, false
+		}
+		key := s[start:i]
+		i++
+
+		var value string
+		if i < len(s) && s[i] == '"' {
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return n, 0, false
+			}
+			value = s[i+1 : i+1+end]
+			i += end + 2
+		} else {
+			start := i
+			for i < len(s) && s[i] != ' ' && s[i] != '>' {
+				i++
+			}
+			value = s[start:i]
+		}
+		n.params[key] = value
+	}
+}
+
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// Execute writes the template to w, replacing each web tag with its value for d.
+// Unknown tags are written unchanged.
+func (t *Template) Execute(w io.Writer, d *Data) error {
+	var b strings.Builder
+	for _, n := range t.nodes {
+		if n.tag == "" {
+			b.WriteString(n.text)
+			continue
+		}
+		v, ok := d.tag(n.tag, n.params)
+		if !ok {
+			b.WriteString(n.text)
+			continue
+		}
+		b.WriteString(v)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
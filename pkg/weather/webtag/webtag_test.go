@@ -0,0 +1,92 @@
+package webtag
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lmacrc/weather/pkg/weather/reporting"
+	"github.com/lmacrc/weather/pkg/weather/units"
+	"github.com/martinlindhe/unit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testData() *Data {
+	ts := time.Date(2021, 12, 1, 14, 5, 9, 0, time.UTC)
+	return &Data{
+		Stats: &reporting.Statistics{
+			Timestamp:          ts,
+			OutdoorTemperature: unit.FromCelsius(22.45),
+			OutdoorHumidity:    65,
+			TodayTempHi:        unit.FromCelsius(24.1),
+			TodayTempHiTime:    ts.Add(-time.Hour),
+			BarometricPressure: 1012.3 * unit.Hectopascal,
+			RainfallToday:      3 * unit.Millimeter,
+			WindForce:          4,
+		},
+		Units:       units.Metric,
+		Station:     Station{Name: "Launceston"},
+		TempHourAgo: unit.FromCelsius(20.95),
+		Month: &reporting.Records{
+			TempHi:     unit.FromCelsius(31.2),
+			TempHiTime: time.Date(2021, 12, 1, 15, 4, 0, 0, time.UTC),
+		},
+	}
+}
+
+func render(t *testing.T, text string, d *Data) string {
+	var buf bytes.Buffer
+	require.NoError(t, Parse(text).Execute(&buf, d))
+	return buf.String()
+}
+
+func TestTemplate_Execute(t *testing.T) {
+	d := testData()
+	for _, tc := range []struct {
+		text, exp string
+	}{
+		{"<p><#temp><#tempunit></p>", "<p>22.4°C</p>"},
+		{"<#temp dp=2> <#temp tc=y> <#temp rc=y>", "22.45 22 22.4"},
+		{"<#TempChangeLastHour>", "1.5"},
+		{"<#time> <#date format=\"dddd d MMMM yyyy\">", "14:05 Wednesday 1 December 2021"},
+		{"<#TtempTH format=h:mmtt>", "1:05PM"},
+		{"<#TtempTL>", "----"},
+		{"<#MonthTempH> <#MonthTempHT> <#MonthTempHD>", "31.2 15:04 01/12/2021"},
+		{"<#YearTempH> <#TYearTempH>", "- <#TYearTempH>"},
+		{"<#beaufort> <#location> <#press> <#rfall>", "F4 Launceston 1012.3 3.0"},
+		{"<#unknown> <# temp> <#temp", "<#unknown> <# temp> <#temp"},
+	} {
+		assert.Equal(t, tc.exp, render(t, tc.text, d), tc.text)
+	}
+}
+
+func TestTemplate_Execute_Units(t *testing.T) {
+	d := testData()
+	d.Units.Temperature = units.Fahrenheit
+	d.Units.Pressure = units.InchOfMercury
+	assert.Equal(t, "72.4°F 2.7 29.89", render(t, "<#temp><#tempunit> <#TempChangeLastHour> <#press>", d))
+}
+
+func TestData_Tag(t *testing.T) {
+	d := testData()
+	v, err := d.Tag("temp", "dp=2")
+	require.NoError(t, err)
+	assert.Equal(t, "22.45", v)
+
+	v, err = d.Tag("time", `format="yyyy-MM-dd HH:mm:ss"`)
+	require.NoError(t, err)
+	assert.Equal(t, "2021-12-01 14:05:09", v)
+
+	_, err = d.Tag("unknown")
+	assert.Error(t, err)
+	_, err = d.Tag("temp", "dp")
+	assert.Error(t, err)
+}
+
+func TestFormatTime(t *testing.T) {
+	ts := time.Date(2021, 3, 7, 0, 5, 9, 0, time.UTC)
+	assert.Equal(t, "07/03/21 12:05:09 AM", formatTime(ts, "dd/MM/yy hh:mm:ss tt"))
+	assert.Equal(t, "Sun 7 Mar 2021 at 0:05", formatTime(ts, "ddd d MMM yyyy 'at' H:mm"))
+	assert.Equal(t, "7h", formatTime(ts, `d\h`))
+}
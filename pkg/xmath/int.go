@@ -0,0 +1,18 @@
+// Package xmath provides the integer functions missing from the math package.
+package xmath
+
+// Min returns the smaller of a or b.
+func Min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of a or b.
+func Max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}